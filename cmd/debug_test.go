@@ -0,0 +1,85 @@
+//go:build !int
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLexAndParse(t *testing.T) {
+	t.Parallel()
+
+	const query = "SELECT id, name FROM users WHERE id >= 1"
+
+	tests := []struct {
+		name   string
+		args   []string
+		stdin  string
+		golden string
+	}{
+		{
+			name:   "Lex a query given as arguments",
+			args:   []string{"lex", "SELECT", "id, name", "FROM users WHERE id >= 1"},
+			golden: filepath.Join("lex", "lex.txt"),
+		},
+		{
+			name:   "Lex a query as JSON",
+			args:   []string{"lex", "--json", query},
+			golden: filepath.Join("lex", "lex.json"),
+		},
+		{
+			name:   "Parse a query read from the standard input",
+			args:   []string{"parse"},
+			stdin:  query,
+			golden: filepath.Join("parse", "parse.txt"),
+		},
+		{
+			name:   "Parse a query as JSON",
+			args:   []string{"parse", "--json", query},
+			golden: filepath.Join("parse", "parse.json"),
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			b := bytes.NewBufferString("")
+
+			copyRootCmd := newRootCmd()
+
+			copyRootCmd.SetIn(strings.NewReader(tt.stdin))
+			copyRootCmd.SetOut(b)
+			copyRootCmd.SetArgs(tt.args)
+
+			if err := copyRootCmd.Execute(); err != nil {
+				t.Fatal(err)
+			}
+
+			wantBytes, err := os.ReadFile(filepath.Join("testdata", tt.golden))
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantBytes = bytes.ReplaceAll(wantBytes, []byte("\r\n"), []byte("\n"))
+
+			if diff := cmp.Diff(strings.TrimSpace(b.String()), strings.TrimSpace(string(wantBytes))); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("Report syntax errors", func(t *testing.T) {
+		copyRootCmd := newRootCmd()
+
+		copyRootCmd.SetArgs([]string{"parse", "SELECT * FORM users"})
+
+		if err := copyRootCmd.Execute(); err == nil {
+			t.Error("expect error, however parse succeeded")
+		}
+	})
+}
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nao1215/aiondb/shell"
+	"github.com/spf13/cobra"
+)
+
+func newExecCmd() *cobra.Command {
+	inputs := &execInputs{}
+	cmd := &cobra.Command{
+		Use:   "exec",
+		Short: "Run SQL files and commands on an in-memory database",
+		Long: `exec starts an in-memory AION DB engine and runs the SQL files (-f) and commands (-c)
+in the order they are given. It stops at the first failing statement, reports its file,
+statement number and position, and exits with a non-zero status.`,
+		Example: "   aion exec -f schema.sql -f seed.sql -c \"SELECT * FROM users\"",
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			return runExec(cmd, inputs)
+		},
+	}
+	cmd.Flags().VarP(&execInputFlag{inputs: inputs, file: true}, "file", "f", "SQL file to run, - reads the standard input (can be repeated)")
+	cmd.Flags().VarP(&execInputFlag{inputs: inputs}, "command", "c", "SQL command to run (can be repeated)")
+	cmd.Flags().StringP("format", "F", string(shell.FormatTable), "output format of result sets (table, csv, json, markdown)")
+	return cmd
+}
+
+// execInput is a SQL file or command given to exec.
+type execInput struct {
+	// file is true if value is a file path, false if it is a SQL command.
+	file bool
+	// value is the file path or the SQL command.
+	value string
+}
+
+// execInputs is the list of files and commands given to exec, in command line order.
+type execInputs struct {
+	inputs []execInput
+}
+
+// execInputFlag is a -f or -c flag. Both flags append to the same list to keep their order.
+type execInputFlag struct {
+	// inputs is the list the flag values are appended to.
+	inputs *execInputs
+	// file is true for -f, false for -c.
+	file bool
+}
+
+// String returns the values of the flag.
+func (f *execInputFlag) String() string {
+	values := []string{}
+	for _, in := range f.inputs.inputs {
+		if in.file == f.file {
+			values = append(values, in.value)
+		}
+	}
+	if len(values) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(values, ",") + "]"
+}
+
+// Set appends a value of the flag.
+func (f *execInputFlag) Set(value string) error {
+	f.inputs.inputs = append(f.inputs.inputs, execInput{file: f.file, value: value})
+	return nil
+}
+
+// Type returns the type of the flag shown in the help.
+func (f *execInputFlag) Type() string {
+	if f.file {
+		return "file"
+	}
+	return "sql"
+}
+
+func runExec(cmd *cobra.Command, inputs *execInputs) error {
+	if len(inputs.inputs) == 0 {
+		return errors.New("no SQL file (-f) or command (-c) to run")
+	}
+
+	name, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+	format, err := shell.ParseFormat(name)
+	if err != nil {
+		return err
+	}
+
+	scripts := make([]shell.Script, 0, len(inputs.inputs))
+	commands := 0
+	for _, in := range inputs.inputs {
+		if !in.file {
+			commands++
+			scripts = append(scripts, shell.Script{Name: fmt.Sprintf("command %d", commands), SQL: in.value})
+			continue
+		}
+
+		var b []byte
+		if in.value == "-" {
+			b, err = io.ReadAll(cmd.InOrStdin())
+		} else {
+			b, err = os.ReadFile(in.value)
+		}
+		if err != nil {
+			return err
+		}
+		scripts = append(scripts, shell.Script{Name: in.value, SQL: string(b)})
+	}
+	return shell.Exec(scripts, cmd.OutOrStdout(), format)
+}
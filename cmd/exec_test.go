@@ -0,0 +1,101 @@
+//go:build !int
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExec(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Check exec --help", func(t *testing.T) {
+		b := bytes.NewBufferString("")
+
+		copyRootCmd := newRootCmd()
+
+		copyRootCmd.SetOut(b)
+		copyRootCmd.SetArgs([]string{"exec", "--help"})
+
+		if err := copyRootCmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		gotBytes, err := io.ReadAll(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotBytes = bytes.ReplaceAll(gotBytes, []byte("\r\n"), []byte("\n"))
+
+		wantBytes, err := os.ReadFile(filepath.Join("testdata", "exec", "exec_help.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantBytes = bytes.ReplaceAll(wantBytes, []byte("\r\n"), []byte("\n"))
+
+		if diff := cmp.Diff(strings.TrimSpace(string(gotBytes)), strings.TrimSpace(string(wantBytes))); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Run files and commands in order", func(t *testing.T) {
+		b := bytes.NewBufferString("")
+
+		copyRootCmd := newRootCmd()
+
+		copyRootCmd.SetOut(b)
+		copyRootCmd.SetArgs([]string{
+			"exec",
+			"-f", filepath.Join("testdata", "exec", "schema.sql"),
+			"-f", filepath.Join("testdata", "exec", "seed.sql"),
+			"-c", "SELECT title, price FROM books ORDER BY price",
+			"-F", "markdown",
+		})
+
+		if err := copyRootCmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+
+		wantBytes, err := os.ReadFile(filepath.Join("testdata", "exec", "exec.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantBytes = bytes.ReplaceAll(wantBytes, []byte("\r\n"), []byte("\n"))
+
+		if diff := cmp.Diff(strings.TrimSpace(b.String()), strings.TrimSpace(string(wantBytes))); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Report the failing statement", func(t *testing.T) {
+		copyRootCmd := newRootCmd()
+
+		file := filepath.Join("testdata", "exec", "broken_seed.sql")
+		copyRootCmd.SetOut(io.Discard)
+		copyRootCmd.SetArgs([]string{"exec", "-f", filepath.Join("testdata", "exec", "schema.sql"), "-f", file})
+
+		err := copyRootCmd.Execute()
+		if err == nil {
+			t.Fatal("expect error, however exec succeeded")
+		}
+		if want := file + ":3:3: statement 2: "; !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("mismatch error: want prefix=%s, got=%s", want, err)
+		}
+	})
+
+	t.Run("Nothing to run", func(t *testing.T) {
+		copyRootCmd := newRootCmd()
+
+		copyRootCmd.SetArgs([]string{"exec"})
+
+		if err := copyRootCmd.Execute(); err == nil {
+			t.Error("expect error, however exec succeeded")
+		}
+	})
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/nao1215/aiondb/engine/parser/core"
+	"github.com/nao1215/aiondb/shell"
+	"github.com/spf13/cobra"
+)
+
+// stdinName is the name of the standard input in the messages of fmt.
+const stdinName = "<stdin>"
+
+func newFmtCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "fmt [FILE...]",
+		Short: "Format SQL files",
+		Long: `fmt rewrites SQL files in a consistent style: upper case keywords, one selected column
+per line, a line per clause, JOIN and WHERE condition, and one column per line in CREATE TABLE.
+The standard input is formatted to the standard output if there is no file.
+Comments between statements are kept, statements containing comments are left as they are.
+
+With --check, files are not rewritten: the unformatted files are listed and fmt exits with
+a non-zero status if there is any.`,
+		Example: "   aion fmt migrations/*.sql\n   aion fmt --check migrations/*.sql\n   aion fmt --dialect mysql < query.sql",
+		RunE:    runFmt,
+	}
+	cmd.Flags().Bool("check", false, "list unformatted files instead of rewriting them, and fail if there is any")
+	cmd.Flags().String("dialect", core.SQLSyntaxModePostgreSQL.String(), "SQL dialect of the files (postgres, mysql, sqlite, oracle)")
+	return cmd
+}
+
+func runFmt(cmd *cobra.Command, args []string) error {
+	check, err := cmd.Flags().GetBool("check")
+	if err != nil {
+		return err
+	}
+	dialect, err := cmd.Flags().GetString("dialect")
+	if err != nil {
+		return err
+	}
+	mode, err := core.ParseSQLSyntaxMode(dialect)
+	if err != nil {
+		return err
+	}
+
+	if len(args) == 0 {
+		b, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return err
+		}
+		formatted, err := shell.FormatScript(shell.Script{Name: stdinName, SQL: string(b)}, mode)
+		if err != nil {
+			return err
+		}
+		if !check {
+			_, err := io.WriteString(cmd.OutOrStdout(), formatted)
+			return err
+		}
+		if formatted != string(b) {
+			fmt.Fprintln(cmd.OutOrStdout(), stdinName)
+			return errors.New("the standard input is not formatted")
+		}
+		return nil
+	}
+
+	unformatted := 0
+	for _, path := range args {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		formatted, err := shell.FormatScript(shell.Script{Name: path, SQL: string(b)}, mode)
+		if err != nil {
+			return err
+		}
+		if formatted == string(b) {
+			continue
+		}
+		if check {
+			unformatted++
+			fmt.Fprintln(cmd.OutOrStdout(), path)
+			continue
+		}
+
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(formatted), info.Mode().Perm()); err != nil {
+			return err
+		}
+	}
+	if unformatted > 0 {
+		return fmt.Errorf("%d of %d files are not formatted", unformatted, len(args))
+	}
+	return nil
+}
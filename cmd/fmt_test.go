@@ -0,0 +1,154 @@
+//go:build !int
+
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFmt(t *testing.T) {
+	t.Parallel()
+
+	unformatted := filepath.Join("testdata", "fmt", "unformatted.sql")
+	formatted := filepath.Join("testdata", "fmt", "formatted.sql")
+
+	t.Run("Check fmt --help", func(t *testing.T) {
+		b := bytes.NewBufferString("")
+
+		copyRootCmd := newRootCmd()
+
+		copyRootCmd.SetOut(b)
+		copyRootCmd.SetArgs([]string{"fmt", "--help"})
+
+		if err := copyRootCmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		wantBytes, err := os.ReadFile(filepath.Join("testdata", "fmt", "fmt_help.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantBytes = bytes.ReplaceAll(wantBytes, []byte("\r\n"), []byte("\n"))
+
+		if diff := cmp.Diff(strings.TrimSpace(string(wantBytes)), strings.TrimSpace(b.String())); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Format the standard input", func(t *testing.T) {
+		in, err := os.ReadFile(unformatted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b := bytes.NewBufferString("")
+
+		copyRootCmd := newRootCmd()
+
+		copyRootCmd.SetIn(bytes.NewReader(in))
+		copyRootCmd.SetOut(b)
+		copyRootCmd.SetArgs([]string{"fmt"})
+
+		if err := copyRootCmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		wantBytes, err := os.ReadFile(formatted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(string(wantBytes), b.String()); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Rewrite files", func(t *testing.T) {
+		in, err := os.ReadFile(unformatted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(t.TempDir(), "books.sql")
+		if err := os.WriteFile(path, in, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		copyRootCmd := newRootCmd()
+
+		copyRootCmd.SetArgs([]string{"fmt", path})
+
+		if err := copyRootCmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		gotBytes, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantBytes, err := os.ReadFile(formatted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(string(wantBytes), string(gotBytes)); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Check formatted files", func(t *testing.T) {
+		b := bytes.NewBufferString("")
+
+		copyRootCmd := newRootCmd()
+
+		copyRootCmd.SetOut(b)
+		copyRootCmd.SetArgs([]string{"fmt", "--check", formatted})
+
+		if err := copyRootCmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		if b.String() != "" {
+			t.Errorf("expect no output, got %q", b.String())
+		}
+	})
+
+	t.Run("Check unformatted files", func(t *testing.T) {
+		b := bytes.NewBufferString("")
+
+		copyRootCmd := newRootCmd()
+
+		copyRootCmd.SetOut(b)
+		copyRootCmd.SetArgs([]string{"fmt", "--check", formatted, unformatted})
+
+		if err := copyRootCmd.Execute(); err == nil {
+			t.Fatal("expect error, however fmt --check succeeded")
+		}
+		if diff := cmp.Diff(unformatted+"\n", b.String()); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Report syntax errors", func(t *testing.T) {
+		copyRootCmd := newRootCmd()
+
+		copyRootCmd.SetIn(strings.NewReader("SELECT * FROM books;\nSELECT * FORM books"))
+		copyRootCmd.SetArgs([]string{"fmt"})
+
+		err := copyRootCmd.Execute()
+		if err == nil {
+			t.Fatal("expect error, however fmt succeeded")
+		}
+		if want := stdinName + ":2:10: statement 2: "; !strings.HasPrefix(err.Error(), want) {
+			t.Errorf("mismatch error: want prefix=%s, got=%s", want, err)
+		}
+	})
+
+	t.Run("Unknown dialect", func(t *testing.T) {
+		copyRootCmd := newRootCmd()
+
+		copyRootCmd.SetArgs([]string{"fmt", "--dialect", "db2", formatted})
+
+		if err := copyRootCmd.Execute(); err == nil {
+			t.Error("expect error, however fmt succeeded")
+		}
+	})
+}
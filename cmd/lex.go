@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/nao1215/aiondb/engine/parser"
+	"github.com/nao1215/aiondb/engine/parser/core"
+	"github.com/spf13/cobra"
+)
+
+func newLexCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lex [SQL]",
+		Short: "Print the tokens of a SQL query",
+		Long: `lex prints the token stream AION DB produces for a SQL query, one token per line.
+The query is read from the arguments, or from the standard input if there is no argument.`,
+		Example: "   aion lex \"SELECT * FROM users WHERE id = 1\"\n   aion lex --json < query.sql",
+		RunE:    lex,
+	}
+	cmd.Flags().Bool("json", false, "print tokens as JSON")
+	return cmd
+}
+
+func lex(cmd *cobra.Command, args []string) error {
+	asJSON, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return err
+	}
+	query, err := readQuery(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	tokens, err := parser.NewLexer(query, core.SQLSyntaxModePostgreSQL).Lex()
+	if err != nil {
+		return err
+	}
+	tokens = core.StripSpaces(tokens)
+
+	if asJSON {
+		return writeJSON(cmd.OutOrStdout(), tokens)
+	}
+	w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+	for _, t := range tokens {
+		fmt.Fprintf(w, "%s\t%s\n", t.ID, t.Lexeme)
+	}
+	return w.Flush()
+}
+
+// readQuery returns the query given as arguments, or read from the standard input if there is no argument.
+func readQuery(cmd *cobra.Command, args []string) (string, error) {
+	if len(args) > 0 {
+		return strings.Join(args, " "), nil
+	}
+	b, err := io.ReadAll(cmd.InOrStdin())
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// writeJSON writes v as indented JSON.
+func writeJSON(w io.Writer, v interface{}) error {
+	e := json.NewEncoder(w)
+	e.SetIndent("", "  ")
+	return e.Encode(v)
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/nao1215/aiondb/engine/parser"
+	"github.com/nao1215/aiondb/engine/parser/core"
+	"github.com/spf13/cobra"
+)
+
+func newParseCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "parse [SQL]",
+		Short: "Print the declaration tree of a SQL query",
+		Long: `parse prints the declaration tree AION DB produces for a SQL query, the tree executed by the engine.
+The query is read from the arguments, or from the standard input if there is no argument.`,
+		Example: "   aion parse \"SELECT * FROM users WHERE id = 1\"\n   aion parse --json < query.sql",
+		RunE:    parse,
+	}
+	cmd.Flags().Bool("json", false, "print the declaration tree as JSON")
+	return cmd
+}
+
+func parse(cmd *cobra.Command, args []string) error {
+	asJSON, err := cmd.Flags().GetBool("json")
+	if err != nil {
+		return err
+	}
+	query, err := readQuery(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	stmts, err := parser.NewParser(core.SQLSyntaxModePostgreSQL).Parse(query)
+	if err != nil {
+		return err
+	}
+
+	if asJSON {
+		return writeJSON(cmd.OutOrStdout(), stmts)
+	}
+	for i, stmt := range stmts {
+		if i > 0 {
+			fmt.Fprintln(cmd.OutOrStdout())
+		}
+		stmt.Fprint(cmd.OutOrStdout())
+	}
+	return nil
+}
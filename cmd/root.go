@@ -18,6 +18,12 @@ func newRootCmd() *cobra.Command {
 
 	cmd.AddCommand(newVersionCmd())
 	cmd.AddCommand(newBugReportCmd())
+	cmd.AddCommand(newShellCmd())
+	cmd.AddCommand(newExecCmd())
+	cmd.AddCommand(newLexCmd())
+	cmd.AddCommand(newParseCmd())
+	cmd.AddCommand(newServeCmd())
+	cmd.AddCommand(newFmtCmd())
 
 	return cmd
 }
@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/nao1215/aiondb/engine"
+	"github.com/nao1215/aiondb/engine/protocol"
+	"github.com/spf13/cobra"
+)
+
+const (
+	// defaultListenAddress is the default address aion serve listens on.
+	defaultListenAddress = "tcp://127.0.0.1:5433"
+	// defaultPostgresAddress is the default address aion serve --pg listens on.
+	defaultPostgresAddress = "tcp://127.0.0.1:5432"
+	// defaultMySQLAddress is the default address aion serve --mysql listens on.
+	defaultMySQLAddress = "tcp://127.0.0.1:3306"
+)
+
+func newServeCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "serve",
+		Short: "Start an in-memory AION DB engine shared over TCP or a Unix socket",
+		Long: `serve starts an in-memory AION DB engine and accepts connections on a TCP address
+or a Unix socket until it is interrupted. All connections share the same data, so several
+test processes can use the same database. Go programs connect with the aiondb driver and
+the listen address as DSN, e.g. sql.Open("aiondb", "tcp://127.0.0.1:5433").
+With --pg, the server speaks the PostgreSQL wire protocol instead (port 5432 by default),
+so that psql, lib/pq, pgx and the PostgreSQL drivers of other languages can connect to it.
+With --mysql, the server speaks the MySQL client/server protocol (port 3306 by default),
+so that the mysql client and go-sql-driver/mysql can connect to it.
+Data is lost when the server stops.`,
+		Example: "   aion serve\n   aion serve --listen tcp://127.0.0.1:15433\n   aion serve --listen unix:///tmp/aion.sock\n   aion serve --pg\n   aion serve --mysql",
+		RunE:    runServe,
+	}
+	cmd.Flags().StringP("listen", "l", defaultListenAddress, "address to listen on (tcp://HOST:PORT or unix://PATH)")
+	cmd.Flags().Bool("pg", false, "speak the PostgreSQL wire protocol (listen on "+defaultPostgresAddress+" by default)")
+	cmd.Flags().Bool("mysql", false, "speak the MySQL client/server protocol (listen on "+defaultMySQLAddress+" by default)")
+	cmd.MarkFlagsMutuallyExclusive("pg", "mysql")
+	return cmd
+}
+
+func runServe(cmd *cobra.Command, _ []string) error {
+	listen, err := cmd.Flags().GetString("listen")
+	if err != nil {
+		return err
+	}
+	pg, err := cmd.Flags().GetBool("pg")
+	if err != nil {
+		return err
+	}
+	mysql, err := cmd.Flags().GetBool("mysql")
+	if err != nil {
+		return err
+	}
+	if !cmd.Flags().Changed("listen") {
+		switch {
+		case pg:
+			listen = defaultPostgresAddress
+		case mysql:
+			listen = defaultMySQLAddress
+		}
+	}
+	network, address, err := protocol.ParseAddress(listen)
+	if err != nil {
+		return err
+	}
+
+	newEndpoint, wire := protocol.NewNetworkEngineEndpoint, "AION DB"
+	switch {
+	case pg:
+		newEndpoint, wire = protocol.NewPostgresEngineEndpoint, "PostgreSQL"
+	case mysql:
+		newEndpoint, wire = protocol.NewMySQLEngineEndpoint, "MySQL"
+	}
+	endpoint, err := newEndpoint(network, address)
+	if err != nil {
+		return err
+	}
+	e, err := engine.New(endpoint)
+	if err != nil {
+		endpoint.Close()
+		return err
+	}
+	defer func() {
+		e.Stop()
+		// Close the endpoint before exiting, so that the Unix socket file is removed
+		endpoint.Close()
+	}()
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(cmd.OutOrStdout(), "AION DB is listening on %s://%s (%s protocol)\n", network, endpoint.Addr(), wire) //nolint
+	select {
+	case <-ctx.Done():
+	case <-e.Done():
+	}
+	return nil
+}
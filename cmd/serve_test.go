@@ -0,0 +1,167 @@
+//go:build !int
+
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/aiondb/driver"
+)
+
+func TestServe(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Check serve --help", func(t *testing.T) {
+		t.Parallel()
+
+		b := bytes.NewBufferString("")
+
+		copyRootCmd := newRootCmd()
+
+		copyRootCmd.SetOut(b)
+		copyRootCmd.SetArgs([]string{"serve", "--help"})
+
+		if err := copyRootCmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		gotBytes, err := io.ReadAll(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotBytes = bytes.ReplaceAll(gotBytes, []byte("\r\n"), []byte("\n"))
+
+		wantBytes, err := os.ReadFile(filepath.Join("testdata", "serve", "serve_help.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantBytes = bytes.ReplaceAll(wantBytes, []byte("\r\n"), []byte("\n"))
+
+		if diff := cmp.Diff(strings.TrimSpace(string(gotBytes)), strings.TrimSpace(string(wantBytes))); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Share an engine on a Unix socket", func(t *testing.T) {
+		t.Parallel()
+
+		socket := filepath.Join(t.TempDir(), "aion.sock")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		r, w := io.Pipe()
+
+		copyRootCmd := newRootCmd()
+		copyRootCmd.SetOut(w)
+		copyRootCmd.SetArgs([]string{"serve", "--listen", "unix://" + socket})
+		done := make(chan error)
+		go func() {
+			done <- copyRootCmd.ExecuteContext(ctx)
+			w.Close()
+		}()
+
+		line, err := bufio.NewReader(r).ReadString('\n')
+		if err != nil {
+			t.Fatal(<-done)
+		}
+		if want := "AION DB is listening on unix://" + socket + " (AION DB protocol)\n"; line != want {
+			t.Errorf("mismatch output: want=%q, got=%q", want, line)
+		}
+
+		db, err := sql.Open(driver.DriverName, "unix://"+socket)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		if _, err := db.Exec("CREATE TABLE account (id BIGSERIAL PRIMARY KEY, email TEXT)"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := db.Exec("INSERT INTO account (email) VALUES ($1)", "foo@example.com"); err != nil {
+			t.Fatal(err)
+		}
+		var email string
+		if err := db.QueryRow("SELECT email FROM account").Scan(&email); err != nil {
+			t.Fatal(err)
+		}
+		if email != "foo@example.com" {
+			t.Errorf("mismatch email: want=foo@example.com, got=%s", email)
+		}
+
+		cancel()
+		if err := <-done; err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Stat(socket); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("expect socket file to be removed, got=%v", err)
+		}
+	})
+
+	for _, wire := range []struct {
+		flag string
+		name string
+	}{
+		{flag: "--pg", name: "PostgreSQL"},
+		{flag: "--mysql", name: "MySQL"},
+	} {
+		wire := wire
+		t.Run("Speak the "+wire.name+" protocol", func(t *testing.T) {
+			t.Parallel()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			r, w := io.Pipe()
+
+			copyRootCmd := newRootCmd()
+			copyRootCmd.SetOut(w)
+			copyRootCmd.SetArgs([]string{"serve", wire.flag, "--listen", "tcp://127.0.0.1:0"})
+			done := make(chan error)
+			go func() {
+				done <- copyRootCmd.ExecuteContext(ctx)
+				w.Close()
+			}()
+
+			line, err := bufio.NewReader(r).ReadString('\n')
+			if err != nil {
+				t.Fatal(<-done)
+			}
+			if !strings.HasPrefix(line, "AION DB is listening on tcp://127.0.0.1:") || !strings.HasSuffix(line, " ("+wire.name+" protocol)\n") {
+				t.Errorf("unexpected output: %q", line)
+			}
+
+			cancel()
+			if err := <-done; err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+
+	t.Run("Reject both --pg and --mysql", func(t *testing.T) {
+		t.Parallel()
+
+		copyRootCmd := newRootCmd()
+		copyRootCmd.SetOut(io.Discard)
+		copyRootCmd.SetErr(io.Discard)
+		copyRootCmd.SetArgs([]string{"serve", "--pg", "--mysql"})
+		if err := copyRootCmd.Execute(); err == nil {
+			t.Error("expect error, however serve succeeded")
+		}
+	})
+
+	t.Run("Reject unsupported network", func(t *testing.T) {
+		t.Parallel()
+
+		copyRootCmd := newRootCmd()
+		copyRootCmd.SetArgs([]string{"serve", "--listen", "udp://127.0.0.1:5433"})
+		if err := copyRootCmd.Execute(); err == nil {
+			t.Error("expect error, however serve succeeded")
+		}
+	})
+}
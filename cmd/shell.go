@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/nao1215/aiondb/shell"
+	"github.com/spf13/cobra"
+)
+
+func newShellCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "shell",
+		Short: "Start AION SHELL, an interactive SQL shell on an in-memory database",
+		Long: `shell starts an in-memory AION DB engine and runs the SQL statements read from the standard input.
+Statements can span several lines and are terminated by a semicolon.
+psql-like meta-commands are available: \dt, \d NAME, \i FILE, \pset format, \timing and \? for help.
+Data is lost when the shell exits (\q, exit, quit or end of input).`,
+		Example: "   aion shell\n   aion shell --format json < queries.sql",
+		RunE:    runShell,
+	}
+	cmd.Flags().StringP("format", "F", string(shell.FormatTable), "output format of result sets (table, csv, json, markdown)")
+	return cmd
+}
+
+func runShell(cmd *cobra.Command, _ []string) error {
+	name, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+	format, err := shell.ParseFormat(name)
+	if err != nil {
+		return err
+	}
+
+	s := shell.New(cmd.InOrStdin(), cmd.OutOrStdout(), isTerminal(cmd))
+	s.SetFormat(format)
+	return s.Run()
+}
+
+// isTerminal returns true if the standard input of the command is a terminal.
+func isTerminal(cmd *cobra.Command) bool {
+	f, ok := cmd.InOrStdin().(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
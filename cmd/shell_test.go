@@ -0,0 +1,106 @@
+//go:build !int
+
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestShell(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Check shell --help", func(t *testing.T) {
+		b := bytes.NewBufferString("")
+
+		copyRootCmd := newRootCmd()
+
+		copyRootCmd.SetOut(b)
+		copyRootCmd.SetArgs([]string{"shell", "--help"})
+
+		if err := copyRootCmd.Execute(); err != nil {
+			t.Fatal(err)
+		}
+		gotBytes, err := io.ReadAll(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gotBytes = bytes.ReplaceAll(gotBytes, []byte("\r\n"), []byte("\n"))
+
+		wantBytes, err := os.ReadFile(filepath.Join("testdata", "shell", "shell_help.txt"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		wantBytes = bytes.ReplaceAll(wantBytes, []byte("\r\n"), []byte("\n"))
+
+		if diff := cmp.Diff(strings.TrimSpace(string(gotBytes)), strings.TrimSpace(string(wantBytes))); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	tests := []struct {
+		name   string
+		args   []string
+		golden string
+	}{
+		{
+			name:   "Run statements from the standard input",
+			args:   []string{"shell"},
+			golden: "schema.txt",
+		},
+		{
+			name:   "Run statements with JSON output",
+			args:   []string{"shell", "--format", "json"},
+			golden: "schema.json",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			in, err := os.Open(filepath.Join("testdata", "shell", "schema.sql"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer in.Close()
+			b := bytes.NewBufferString("")
+
+			copyRootCmd := newRootCmd()
+
+			copyRootCmd.SetIn(in)
+			copyRootCmd.SetOut(b)
+			copyRootCmd.SetArgs(tt.args)
+
+			if err := copyRootCmd.Execute(); err != nil {
+				t.Fatal(err)
+			}
+
+			wantBytes, err := os.ReadFile(filepath.Join("testdata", "shell", tt.golden))
+			if err != nil {
+				t.Fatal(err)
+			}
+			wantBytes = bytes.ReplaceAll(wantBytes, []byte("\r\n"), []byte("\n"))
+
+			if diff := cmp.Diff(strings.TrimSpace(b.String()), strings.TrimSpace(string(wantBytes))); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("Unknown output format", func(t *testing.T) {
+		copyRootCmd := newRootCmd()
+
+		copyRootCmd.SetIn(strings.NewReader(""))
+		copyRootCmd.SetArgs([]string{"shell", "--format", "xml"})
+
+		if err := copyRootCmd.Execute(); err == nil {
+			t.Error("expect error, however shell succeeded")
+		}
+	})
+}
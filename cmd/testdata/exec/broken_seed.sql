@@ -0,0 +1,3 @@
+INSERT INTO books (title, price) VALUES ('AION', 12.5);
+
+  INSERT INTO books (title) VALUES ('AION');
@@ -0,0 +1,6 @@
+-- Tables of the library
+CREATE TABLE books (
+  id SERIAL PRIMARY KEY,
+  title TEXT NOT NULL UNIQUE,
+  price NUMERIC
+);
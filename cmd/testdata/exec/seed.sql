@@ -0,0 +1,2 @@
+INSERT INTO books (title, price) VALUES ('AION', 12.5);
+INSERT INTO books (title) VALUES ('Metal');
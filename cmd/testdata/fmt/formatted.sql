@@ -0,0 +1,19 @@
+-- books of the store
+CREATE TABLE books (
+    id SERIAL PRIMARY KEY,
+    title VARCHAR(50) NOT NULL,
+    price INT DEFAULT 0
+);
+
+INSERT INTO books (title, price)
+VALUES
+    ('Dune', 10),
+    ('Emma', 8);
+
+SELECT
+    title,
+    price
+FROM books
+WHERE price > 5
+    AND title <> 'Emma'
+ORDER BY price DESC;
@@ -0,0 +1,4 @@
+-- books of the store
+create table books (id serial primary key, title varchar(50) not null, price int default 0);
+insert into books (title, price) values ('Dune', 10), ('Emma', 8);
+select title, price from books where price > 5 and title <> 'Emma' order by price desc;
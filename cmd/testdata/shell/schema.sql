@@ -0,0 +1,7 @@
+CREATE TABLE books (
+  id SERIAL PRIMARY KEY,
+  title TEXT NOT NULL,
+  price NUMERIC
+);
+INSERT INTO books (title, price) VALUES ('AION', 12.5), ('Metal', NULL);
+SELECT id, title, price FROM books ORDER BY id DESC;
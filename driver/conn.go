@@ -0,0 +1,71 @@
+package driver
+
+import (
+	"database/sql/driver"
+	"errors"
+
+	"github.com/nao1215/aiondb/engine/parser/core"
+	"github.com/nao1215/aiondb/engine/protocol"
+)
+
+// ErrRollbackNotSupported means "the engine cannot undo a transaction"
+var ErrRollbackNotSupported = errors.New("aiondb: rollback is not supported")
+
+// Conn is the AION DB implementation of driver.Conn.
+// It is not used concurrently by multiple goroutines (see database/sql/driver).
+type Conn struct {
+	// conn is the connection to the engine.
+	conn protocol.DriverConn
+	// mode is the SQL syntax mode of the statements, arguments are formatted for it.
+	mode core.SQLSyntaxMode
+}
+
+// newConn returns a new Conn wrapping the given protocol connection.
+func newConn(conn protocol.DriverConn, mode core.SQLSyntaxMode) *Conn {
+	return &Conn{
+		conn: conn,
+		mode: mode,
+	}
+}
+
+// Prepare returns a prepared statement, bound to this connection.
+func (c *Conn) Prepare(query string) (driver.Stmt, error) {
+	if c.conn == nil {
+		return nil, ErrConnClosed
+	}
+	return newStmt(c, query), nil
+}
+
+// Close invalidates and potentially stops any current prepared statements
+// and transactions, marking this connection as no longer in use.
+func (c *Conn) Close() error {
+	if c.conn == nil {
+		return nil
+	}
+	c.conn.Close()
+	c.conn = nil
+	return nil
+}
+
+// Begin starts and returns a new transaction.
+// AION DB has no transaction support: statements are applied immediately
+// and a rollback returns ErrRollbackNotSupported.
+func (c *Conn) Begin() (driver.Tx, error) {
+	if c.conn == nil {
+		return nil, ErrConnClosed
+	}
+	return &tx{}, nil
+}
+
+// tx is a no-op transaction.
+type tx struct{}
+
+// Commit commits the transaction. Statements are already applied, so it does nothing.
+func (t *tx) Commit() error {
+	return nil
+}
+
+// Rollback aborts the transaction. It always fails because statements are already applied.
+func (t *tx) Rollback() error {
+	return ErrRollbackNotSupported
+}
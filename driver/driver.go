@@ -0,0 +1,96 @@
+// Package driver implements the database/sql/driver interfaces for AION DB.
+// A blank import registers the driver under the name "aiondb":
+//
+//	import _ "github.com/nao1215/aiondb/driver"
+//
+//	db, err := sql.Open("aiondb", "testdb")
+//
+// Each DSN names an in-memory engine running in the same process. Connections
+// opened with the same DSN share the same data, and the data is dropped when
+// the last connection to the DSN is closed.
+//
+// A DSN with a tcp:// or unix:// scheme connects to an engine started by
+// aion serve, so that several processes share the same data:
+//
+//	db, err := sql.Open("aiondb", "tcp://127.0.0.1:5433")
+//	db, err := sql.Open("aiondb", "unix:///tmp/aion.sock")
+//
+// Statements are written in the PostgreSQL syntax by default. The dialect
+// option selects the syntax of the statements of each connection, so that
+// services written for different RDBMSs can share the same test helper:
+//
+//	db, err := sql.Open("aiondb", "aiondb://testdb?dialect=mysql")
+//	db, err := sql.Open("aiondb", "tcp://127.0.0.1:5433?dialect=sqlite")
+//
+// The dialects are postgres, mysql, sqlite and oracle. Placeholders are
+// replaced with the arguments quoted for the dialect: $1 and ? are always
+// supported, SQLite also supports ?NNN, and SQLite and Oracle bind :name
+// placeholders to named arguments (sql.Named) or to the next argument.
+//
+// The driver only talks to the engine through protocol.DriverEndpoint and
+// protocol.DriverConn, so the code under test does not know that it is not
+// connected to a real RDBMS.
+package driver
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+
+	"github.com/nao1215/aiondb/engine"
+	"github.com/nao1215/aiondb/engine/protocol"
+)
+
+// DriverName is the name used to register the driver in database/sql.
+const DriverName = "aiondb"
+
+var (
+	// ErrNoEndpoint means "the driver has no endpoint to connect to"
+	ErrNoEndpoint = errors.New("aiondb: driver has no endpoint")
+	// ErrConnClosed means "the connection is already closed"
+	ErrConnClosed = errors.New("aiondb: connection is closed")
+)
+
+func init() { //nolint:gochecknoinits
+	sql.Register(DriverName, NewDriver(engine.NewLocalEndpoint()))
+}
+
+// Driver is the AION DB implementation of driver.Driver.
+type Driver struct {
+	// endpoint creates the connections to the engine.
+	endpoint protocol.DriverEndpoint
+	// network creates the connections to engines named by a network DSN.
+	network protocol.DriverEndpoint
+}
+
+// NewDriver returns a new Driver connecting through the given endpoint.
+func NewDriver(endpoint protocol.DriverEndpoint) *Driver {
+	return &Driver{
+		endpoint: endpoint,
+		network:  protocol.NewNetworkDriverEndpoint(),
+	}
+}
+
+// Open returns a new connection to the database.
+// The name is a DSN understood by the driver endpoint, or a network DSN
+// (e.g. "tcp://127.0.0.1:5433") of an engine started by aion serve.
+// Both may select the SQL syntax mode with the dialect option.
+func (d *Driver) Open(dsn string) (driver.Conn, error) {
+	endpoint := d.endpoint
+	if protocol.IsNetworkDSN(dsn) {
+		endpoint = d.network
+	}
+	if endpoint == nil {
+		return nil, ErrNoEndpoint
+	}
+	mode, err := protocol.SyntaxModeOfDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := endpoint.New(dsn)
+	if err != nil {
+		return nil, err
+	}
+	return newConn(conn, mode), nil
+}
@@ -0,0 +1,552 @@
+package driver
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/aiondb/engine"
+	"github.com/nao1215/aiondb/engine/parser/core"
+	"github.com/nao1215/aiondb/engine/protocol"
+)
+
+// fakeEndpoint is a protocol.DriverEndpoint returning fakeConn.
+type fakeEndpoint struct {
+	conn *fakeConn
+}
+
+func (f *fakeEndpoint) New(_ string) (protocol.DriverConn, error) {
+	return f.conn, nil
+}
+
+// fakeConn records the statements and returns canned rows and results.
+type fakeConn struct {
+	stmts  []string
+	header []*string
+	rows   [][]*string
+	closed bool
+}
+
+func (f *fakeConn) WriteQuery(query string) error {
+	f.stmts = append(f.stmts, query)
+	return nil
+}
+
+func (f *fakeConn) WriteExec(stmt string) error {
+	f.stmts = append(f.stmts, stmt)
+	return nil
+}
+
+func (f *fakeConn) ReadResult() (int64, int64, error) {
+	return 3, 1, nil
+}
+
+func (f *fakeConn) ReadRows() (chan []*string, error) {
+	ch := make(chan []*string, len(f.rows)+1)
+	ch <- f.header
+	for _, r := range f.rows {
+		ch <- r
+	}
+	close(ch)
+	return ch, nil
+}
+
+func (f *fakeConn) Close() {
+	f.closed = true
+}
+
+// text returns a non-NULL value of a row.
+func text(s string) *string {
+	return &s
+}
+
+// connector opens connections with a Driver without registering it.
+type connector struct {
+	d *Driver
+}
+
+func (c *connector) Connect(_ context.Context) (driver.Conn, error) {
+	return c.d.Open("testdb")
+}
+
+func (c *connector) Driver() driver.Driver {
+	return c.d
+}
+
+func TestDriver(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Exec and Query through database/sql", func(t *testing.T) {
+		t.Parallel()
+
+		conn := &fakeConn{
+			header: []*string{text("id"), text("name"), text("created_at")},
+			rows: [][]*string{
+				{text("1"), text("aion"), text("2023-05-30 10:00:00 +0000 UTC")},
+				{text("2"), nil, text("2023-05-30 11:00:00 +0000 UTC")},
+			},
+		}
+		db := sql.OpenDB(&connector{d: NewDriver(&fakeEndpoint{conn: conn})})
+
+		res, err := db.Exec("INSERT INTO user (name) VALUES ($1)", "aion")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id, _ := res.LastInsertId(); id != 3 {
+			t.Errorf("mismatch last insert id: want=3, got=%d", id)
+		}
+
+		rows, err := db.Query("SELECT * FROM user WHERE id > ?", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		type user struct {
+			id        int
+			name      sql.NullString
+			createdAt time.Time
+		}
+		got := []user{}
+		for rows.Next() {
+			var u user
+			if err := rows.Scan(&u.id, &u.name, &u.createdAt); err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, u)
+		}
+		if err := rows.Err(); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(got) != 2 || got[0].name.String != "aion" || got[1].name.Valid || got[1].createdAt.Hour() != 11 {
+			t.Errorf("unexpected rows: %+v", got)
+		}
+
+		want := []string{
+			"INSERT INTO user (name) VALUES ($$aion$$)",
+			"SELECT * FROM user WHERE id > $$0$$",
+		}
+		if diff := cmp.Diff(want, conn.stmts); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Open without endpoint", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := (&Driver{}).Open("testdb"); !errors.Is(err, ErrNoEndpoint) {
+			t.Errorf("mismatch error: want=%v, got=%v", ErrNoEndpoint, err)
+		}
+	})
+}
+
+func TestReplaceArguments(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mode    core.SQLSyntaxMode
+		query   string
+		args    []driver.Value
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "postgres placeholders",
+			mode:  core.SQLSyntaxModePostgreSQL,
+			query: "UPDATE t SET a = $2 WHERE b = $1",
+			args:  []driver.Value{int64(1), "x"},
+			want:  "UPDATE t SET a = $$x$$ WHERE b = $$1$$",
+		},
+		{
+			name:  "odbc placeholders and null",
+			mode:  core.SQLSyntaxModePostgreSQL,
+			query: "INSERT INTO t (a, b) VALUES (?, ?)",
+			args:  []driver.Value{nil, 1.5},
+			want:  "INSERT INTO t (a, b) VALUES (null, $$1.5$$)",
+		},
+		{
+			name:  "placeholders inside quotes are kept",
+			mode:  core.SQLSyntaxModePostgreSQL,
+			query: "SELECT * FROM t WHERE a = '?' AND b = ?",
+			args:  []driver.Value{"c"},
+			want:  "SELECT * FROM t WHERE a = '?' AND b = $$c$$",
+		},
+		{
+			name:  "placeholders inside escape strings, dollar quotes and comments are kept",
+			mode:  core.SQLSyntaxModePostgreSQL,
+			query: "SELECT E'it\\'s ?', $tag$ $1 $tag$ FROM t -- $1\nWHERE a = /* ? */ $1",
+			args:  []driver.Value{"c"},
+			want:  "SELECT E'it\\'s ?', $tag$ $1 $tag$ FROM t -- $1\nWHERE a = /* ? */ $$c$$",
+		},
+		{
+			name:    "missing argument",
+			mode:    core.SQLSyntaxModePostgreSQL,
+			query:   "SELECT * FROM t WHERE a = $2",
+			args:    []driver.Value{"c"},
+			wantErr: true,
+		},
+		{
+			name:    "argument with dollar quotes",
+			mode:    core.SQLSyntaxModePostgreSQL,
+			query:   "SELECT * FROM t WHERE a = $1",
+			args:    []driver.Value{"$$"},
+			wantErr: true,
+		},
+		{
+			name:  "mysql quotes with backslash escapes",
+			mode:  core.SQLSyntaxModeMySQL,
+			query: "SELECT * FROM `t?` WHERE a = 'it\\'s ?' AND b = ? AND c = ?",
+			args:  []driver.Value{`it's \ $$`, int64(1)},
+			want:  "SELECT * FROM `t?` WHERE a = 'it\\'s ?' AND b = 'it\\'s \\\\ $$' AND c = '1'",
+		},
+		{
+			name:  "sqlite numbered and named placeholders",
+			mode:  core.SQLSyntaxModeSQLite,
+			query: "UPDATE t SET a = ?2, b = :name WHERE c = ?1 AND d = 'it''s ?'",
+			args:  []driver.Value{"it's", int64(2)},
+			want:  "UPDATE t SET a = '2', b = 'it''s' WHERE c = 'it''s' AND d = 'it''s ?'",
+		},
+		{
+			name:  "oracle bind variables",
+			mode:  core.SQLSyntaxModeOracle,
+			query: "SELECT * FROM t WHERE a = :a AND b = :b",
+			args:  []driver.Value{"x", nil},
+			want:  "SELECT * FROM t WHERE a = 'x' AND b = null",
+		},
+		{
+			name:    "oracle bind variable without argument",
+			mode:    core.SQLSyntaxModeOracle,
+			query:   "SELECT * FROM t WHERE a = :a AND b = :b",
+			args:    []driver.Value{"x"},
+			wantErr: true,
+		},
+		{
+			name:  "postgres casts are not bind variables",
+			mode:  core.SQLSyntaxModePostgreSQL,
+			query: "SELECT a::text FROM t WHERE b = $1",
+			args:  []driver.Value{"x"},
+			want:  "SELECT a::text FROM t WHERE b = $$x$$",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := replaceArguments(tt.query, namedValues(tt.args), tt.mode)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("want=%s, got=%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open(DriverName, "aiondb://TestOpen")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec(`CREATE TABLE account (id BIGSERIAL PRIMARY KEY, email TEXT UNIQUE, created_at TIMESTAMP DEFAULT NOW())`); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, email := range []string{"foo@example.com", "bar@example.com"} {
+		res, err := db.Exec("INSERT INTO account (email) VALUES ($1)", email)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n, _ := res.RowsAffected(); n != 1 {
+			t.Errorf("mismatch rows affected: want=1, got=%d", n)
+		}
+	}
+	if _, err := db.Exec("INSERT INTO account (email) VALUES ($1)", "foo@example.com"); err == nil {
+		t.Error("expect unique constraint violation, however insert succeeded")
+	}
+
+	var id int64
+	var createdAt time.Time
+	row := db.QueryRow("SELECT id, created_at FROM account WHERE email = $1", "bar@example.com")
+	if err := row.Scan(&id, &createdAt); err != nil {
+		t.Fatal(err)
+	}
+	if id != 2 || createdAt.IsZero() {
+		t.Errorf("unexpected row: id=%d, created_at=%v", id, createdAt)
+	}
+}
+
+func TestOpenNull(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open(DriverName, "aiondb://TestOpenNull")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := db.Exec("CREATE TABLE note (id BIGSERIAL PRIMARY KEY, body TEXT)"); err != nil {
+		t.Fatal(err)
+	}
+	for _, body := range []interface{}{"<nil>", nil, "NULL"} {
+		if _, err := db.Exec("INSERT INTO note (body) VALUES ($1)", body); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rows, err := db.Query("SELECT body FROM note ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	got := []sql.NullString{}
+	for rows.Next() {
+		var body sql.NullString
+		if err := rows.Scan(&body); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, body)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := []sql.NullString{{String: "<nil>", Valid: true}, {}, {String: "NULL", Valid: true}}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("value is mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestOpenMultiStatement(t *testing.T) {
+	t.Parallel()
+
+	db, err := sql.Open(DriverName, "aiondb://TestOpenMultiStatement")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	// The statements share a connection, which must stay in sync after each call
+	db.SetMaxOpenConns(1)
+
+	testMultiStatement(t, db)
+}
+
+// testMultiStatement runs several statements with a single Exec, then checks that
+// the following calls read their own reply.
+func testMultiStatement(t *testing.T, db *sql.DB) {
+	t.Helper()
+
+	res, err := db.Exec(`CREATE TABLE account (id BIGSERIAL PRIMARY KEY, email TEXT UNIQUE);
+		INSERT INTO account (email) VALUES ('foo@example.com');
+		INSERT INTO account (email) VALUES ('bar@example.com'), ('baz@example.com')`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n, _ := res.RowsAffected(); n != 4 {
+		t.Errorf("mismatch rows affected: want=4, got=%d", n)
+	}
+
+	// The batch stops at the failing statement
+	if _, err := db.Exec(`INSERT INTO account (email) VALUES ('qux@example.com');
+		INSERT INTO account (email) VALUES ('foo@example.com');
+		INSERT INTO account (email) VALUES ('quux@example.com')`); err == nil {
+		t.Error("expect unique constraint violation, however insert succeeded")
+	}
+
+	rows, err := db.Query("SELECT email FROM account ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	got := []string{}
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, email)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"foo@example.com", "bar@example.com", "baz@example.com", "qux@example.com"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("value is mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestOpenNetwork(t *testing.T) {
+	t.Parallel()
+
+	endpoint, err := protocol.NewNetworkEngineEndpoint("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := engine.New(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Stop()
+	dsn := "tcp://" + endpoint.Addr().String()
+
+	// Two pools share the data of the engine, like two processes would
+	writer, err := sql.Open(DriverName, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer writer.Close()
+	reader, err := sql.Open(DriverName, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer reader.Close()
+
+	if _, err := writer.Exec(`CREATE TABLE account (id BIGSERIAL PRIMARY KEY, email TEXT UNIQUE)`); err != nil {
+		t.Fatal(err)
+	}
+	for _, email := range []string{"foo@example.com", "bar@example.com"} {
+		if _, err := writer.Exec("INSERT INTO account (email) VALUES ($1)", email); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := writer.Exec("INSERT INTO account (email) VALUES ($1)", "foo@example.com"); err == nil {
+		t.Error("expect unique constraint violation, however insert succeeded")
+	}
+
+	rows, err := reader.Query("SELECT email FROM account ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+	got := []string{}
+	for rows.Next() {
+		var email string
+		if err := rows.Scan(&email); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, email)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff([]string{"foo@example.com", "bar@example.com"}, got); diff != "" {
+		t.Errorf("value is mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestOpenNetworkMultiStatement(t *testing.T) {
+	t.Parallel()
+
+	endpoint, err := protocol.NewNetworkEngineEndpoint("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := engine.New(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer e.Stop()
+
+	db, err := sql.Open(DriverName, "tcp://"+endpoint.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	// The statements share a connection, which must stay in sync after each call
+	db.SetMaxOpenConns(1)
+
+	testMultiStatement(t, db)
+}
+
+func TestOpenDialect(t *testing.T) {
+	t.Parallel()
+
+	endpoint, err := protocol.NewNetworkEngineEndpoint("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	e, err := engine.New(endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(e.Stop)
+
+	tests := []struct {
+		name   string
+		local  string
+		remote string
+	}{
+		{name: "local engine", local: "aiondb://TestOpenDialect"},
+		{name: "network engine", remote: "tcp://" + endpoint.Addr().String()},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Services written for different RDBMSs share the same data
+			dsn := tt.local + tt.remote
+			open := func(dialect string) *sql.DB {
+				t.Helper()
+				db, err := sql.Open(DriverName, dsn+"?dialect="+dialect)
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { db.Close() })
+				return db
+			}
+			mysql, sqlite, oracle := open("mysql"), open("sqlite"), open("oracle")
+
+			if _, err := mysql.Exec("CREATE TABLE `users` (`id` INT PRIMARY KEY AUTO_INCREMENT, `name` VARCHAR(255))"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := mysql.Exec("INSERT INTO `users` (`name`) VALUES (?)", `it's \ mysql`); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := sqlite.Exec("INSERT OR IGNORE INTO [users] (name) VALUES (?1)", "it's sqlite"); err != nil {
+				t.Fatal(err)
+			}
+
+			rows, err := oracle.Query("SELECT name FROM users WHERE name <> :name ORDER BY id FETCH FIRST 5 ROWS ONLY", sql.Named("name", "nobody"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rows.Close()
+			got := []string{}
+			for rows.Next() {
+				var name string
+				if err := rows.Scan(&name); err != nil {
+					t.Fatal(err)
+				}
+				got = append(got, name)
+			}
+			if err := rows.Err(); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff([]string{`it's \ mysql`, "it's sqlite"}, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	db, err := sql.Open(DriverName, "aiondb://TestOpenDialect?dialect=db2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	if err := db.Ping(); err == nil {
+		t.Error("expect error for an unknown dialect, however no error")
+	}
+}
@@ -0,0 +1,27 @@
+package driver
+
+// Result is the AION DB implementation of driver.Result.
+type Result struct {
+	// lastInsertedID is the ID of the last inserted row.
+	lastInsertedID int64
+	// rowsAffected is the number of rows affected by the statement.
+	rowsAffected int64
+}
+
+// newResult returns a new Result.
+func newResult(lastInsertedID int64, rowsAffected int64) *Result {
+	return &Result{
+		lastInsertedID: lastInsertedID,
+		rowsAffected:   rowsAffected,
+	}
+}
+
+// LastInsertId returns the database's auto-generated ID after an INSERT into a table with primary key.
+func (r *Result) LastInsertId() (int64, error) {
+	return r.lastInsertedID, nil
+}
+
+// RowsAffected returns the number of rows affected by the query.
+func (r *Result) RowsAffected() (int64, error) {
+	return r.rowsAffected, nil
+}
@@ -0,0 +1,88 @@
+package driver
+
+import (
+	"database/sql/driver"
+	"errors"
+	"io"
+	"time"
+
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// Rows is the AION DB implementation of driver.Rows.
+type Rows struct {
+	// columns is the row header sent by the engine.
+	columns []string
+	// rows receives the rows until the engine has sent the last one.
+	rows chan []*string
+}
+
+// newRows reads the row header and returns a new Rows.
+func newRows(rows chan []*string) (*Rows, error) {
+	header, ok := <-rows
+	if !ok {
+		return nil, errors.New("aiondb: no row header received")
+	}
+	columns := make([]string, 0, len(header))
+	for _, name := range header {
+		if name == nil {
+			return nil, errors.New("aiondb: NULL column name received")
+		}
+		columns = append(columns, *name)
+	}
+	return &Rows{
+		columns: columns,
+		rows:    rows,
+	}, nil
+}
+
+// Columns returns the names of the columns.
+func (r *Rows) Columns() []string {
+	return r.columns
+}
+
+// Close closes the rows iterator. The remaining rows are discarded.
+func (r *Rows) Close() error {
+	if r.rows == nil {
+		return nil
+	}
+	for range r.rows {
+		// discard the remaining rows
+	}
+	r.rows = nil
+	return nil
+}
+
+// Next is called to populate the next row of data into the provided slice.
+func (r *Rows) Next(dest []driver.Value) error {
+	if r.rows == nil {
+		return io.EOF
+	}
+
+	value, ok := <-r.rows
+	if !ok {
+		r.rows = nil
+		return io.EOF
+	}
+
+	for i := range dest {
+		if i >= len(value) {
+			dest[i] = nil
+			continue
+		}
+		dest[i] = convertValue(value[i])
+	}
+	return nil
+}
+
+// convertValue converts a value sent by the engine to a driver value, NULL is nil.
+// Timestamps stored by the engine are returned as time.Time.
+func convertValue(v *string) driver.Value {
+	if v == nil {
+		return nil
+	}
+	if t, err := time.Parse(core.DateLongFormat, *v); err == nil {
+		return t
+	}
+	return *v
+}
@@ -0,0 +1,261 @@
+package driver
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nao1215/aiondb/engine/parser/core"
+	"github.com/nao1215/aiondb/engine/parser/postgres"
+)
+
+// Stmt is the AION DB implementation of driver.Stmt.
+type Stmt struct {
+	// conn is the connection the statement is bound to.
+	conn *Conn
+	// query is the statement with its placeholders ($1, ?, ?NNN or :name).
+	query string
+}
+
+// newStmt returns a new Stmt bound to the given connection.
+func newStmt(c *Conn, query string) *Stmt {
+	return &Stmt{
+		conn:  c,
+		query: query,
+	}
+}
+
+// Close closes the statement. There is nothing to release on the engine side.
+func (s *Stmt) Close() error {
+	return nil
+}
+
+// NumInput returns -1: the engine does not know the number of placeholders
+// before parsing, so database/sql does not check it.
+func (s *Stmt) NumInput() int {
+	return -1
+}
+
+// Exec executes a query that doesn't return rows, such as an INSERT or UPDATE.
+func (s *Stmt) Exec(args []driver.Value) (driver.Result, error) {
+	return s.ExecContext(context.Background(), namedValues(args))
+}
+
+// ExecContext executes a query that doesn't return rows, such as an INSERT or UPDATE.
+// Named arguments (sql.Named) are bound to the :name placeholders.
+func (s *Stmt) ExecContext(_ context.Context, args []driver.NamedValue) (driver.Result, error) {
+	if s.conn.conn == nil {
+		return nil, ErrConnClosed
+	}
+
+	query, err := replaceArguments(s.query, args, s.conn.mode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.conn.conn.WriteExec(query); err != nil {
+		return nil, err
+	}
+
+	lastInsertedID, rowsAffected, err := s.conn.conn.ReadResult()
+	if err != nil {
+		return nil, err
+	}
+	return newResult(lastInsertedID, rowsAffected), nil
+}
+
+// Query executes a query that may return rows, such as a SELECT.
+func (s *Stmt) Query(args []driver.Value) (driver.Rows, error) {
+	return s.QueryContext(context.Background(), namedValues(args))
+}
+
+// QueryContext executes a query that may return rows, such as a SELECT.
+// Named arguments (sql.Named) are bound to the :name placeholders.
+func (s *Stmt) QueryContext(_ context.Context, args []driver.NamedValue) (driver.Rows, error) {
+	if s.conn.conn == nil {
+		return nil, ErrConnClosed
+	}
+
+	query, err := replaceArguments(s.query, args, s.conn.mode)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.conn.conn.WriteQuery(query); err != nil {
+		return nil, err
+	}
+
+	rowsChannel, err := s.conn.conn.ReadRows()
+	if err != nil {
+		return nil, err
+	}
+	return newRows(rowsChannel)
+}
+
+// namedValues returns the positional arguments as driver.NamedValue without name.
+func namedValues(args []driver.Value) []driver.NamedValue {
+	named := make([]driver.NamedValue, 0, len(args))
+	for i, v := range args {
+		named = append(named, driver.NamedValue{Ordinal: i + 1, Value: v})
+	}
+	return named
+}
+
+// replaceArguments replaces the placeholders of the query with the given arguments,
+// formatted for the SQL syntax mode of the connection. The PostgreSQL ($1, $2, ...)
+// and the ODBC (?) styles are supported by every mode, SQLite also supports ?NNN,
+// and SQLite and Oracle bind :name to the named argument or to the next argument.
+// Placeholders inside quoted strings and identifiers are left untouched, and so are
+// the placeholders inside comments in the PostgreSQL mode.
+func replaceArguments(query string, args []driver.NamedValue, mode core.SQLSyntaxMode) (string, error) {
+	if len(args) == 0 {
+		return query, nil
+	}
+
+	var b strings.Builder
+	var scanner postgres.Scanner
+	next := 0
+	for i := 0; i < len(query); i++ {
+		if mode == core.SQLSyntaxModePostgreSQL {
+			// Strings (including E'...' and $tag$...$tag$), quoted identifiers and comments
+			if end, kind := scanner.Next(query, i); kind != postgres.ScanCode {
+				b.WriteString(query[i:end])
+				i = end - 1
+				continue
+			}
+		}
+
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := quoteEnd(query, i, mode)
+			if end < 0 {
+				b.WriteString(query[i:])
+				return b.String(), nil
+			}
+			b.WriteString(query[i : end+1])
+			i = end
+		case c == '$' && i+1 < len(query) && isDigit(query[i+1]),
+			c == '?' && mode == core.SQLSyntaxModeSQLite && i+1 < len(query) && isDigit(query[i+1]):
+			j := i + 1
+			for j < len(query) && isDigit(query[j]) {
+				j++
+			}
+			index, err := strconv.Atoi(query[i+1 : j])
+			if err != nil {
+				return "", err
+			}
+			if index < 1 || index > len(args) {
+				return "", fmt.Errorf("aiondb: placeholder %s has no argument", query[i:j])
+			}
+			v, err := formatArgument(args[index-1].Value, mode)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+			i = j - 1
+		case c == ':' && (mode == core.SQLSyntaxModeSQLite || mode == core.SQLSyntaxModeOracle) &&
+			i+1 < len(query) && isNameByte(query[i+1]) && (i == 0 || query[i-1] != ':'):
+			j := i + 1
+			for j < len(query) && isNameByte(query[j]) {
+				j++
+			}
+			arg, ok := namedArgument(args, query[i+1:j])
+			if !ok {
+				if next >= len(args) {
+					return "", fmt.Errorf("aiondb: placeholder %s has no argument", query[i:j])
+				}
+				arg = args[next]
+				next++
+			}
+			v, err := formatArgument(arg.Value, mode)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+			i = j - 1
+		case c == '?':
+			if next >= len(args) {
+				return "", fmt.Errorf("aiondb: placeholder ? at position %d has no argument", i)
+			}
+			v, err := formatArgument(args[next].Value, mode)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+			next++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
+
+// quoteEnd returns the index of the quote closing the string or identifier starting at
+// query[start], or -1 if it is not closed. MySQL strings may contain backslash escapes.
+// A doubled quote closes the string and opens the next one, which is equivalent.
+func quoteEnd(query string, start int, mode core.SQLSyntaxMode) int {
+	quote := query[start]
+	for i := start + 1; i < len(query); i++ {
+		switch {
+		case query[i] == '\\' && mode == core.SQLSyntaxModeMySQL && quote != '`':
+			i++
+		case query[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// namedArgument returns the argument named name (e.g. sql.Named("name", v) for :name).
+func namedArgument(args []driver.NamedValue, name string) (driver.NamedValue, bool) {
+	for _, arg := range args {
+		if arg.Name != "" && strings.EqualFold(arg.Name, name) {
+			return arg, true
+		}
+	}
+	return driver.NamedValue{}, false
+}
+
+// formatArgument returns the SQL representation of a driver value in the SQL syntax mode.
+// PostgreSQL values are escaped with $$ so that they are lexed as a single token, other
+// modes use quoted strings: MySQL escapes with backslashes, SQLite and Oracle double the quotes.
+func formatArgument(v driver.Value, mode core.SQLSyntaxMode) (string, error) {
+	var s string
+	switch v := v.(type) {
+	case nil:
+		return "null", nil
+	case time.Time:
+		s = v.Format(core.DateLongFormat)
+	case []byte:
+		s = string(v)
+	default:
+		s = fmt.Sprintf("%v", v)
+	}
+
+	switch mode {
+	case core.SQLSyntaxModeMySQL:
+		return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(s) + "'", nil
+	case core.SQLSyntaxModeSQLite, core.SQLSyntaxModeOracle:
+		return "'" + strings.ReplaceAll(s, "'", "''") + "'", nil
+	default:
+		if strings.Contains(s, "$$") {
+			return "", errors.New("aiondb: argument must not contain $$")
+		}
+		return "$$" + s + "$$", nil
+	}
+}
+
+// isDigit returns true if c is an ASCII digit.
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// isNameByte returns true if c may be part of the name of a :name placeholder.
+func isNameByte(c byte) bool {
+	return isDigit(c) || c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
@@ -1,7 +1,10 @@
 package engine
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"strconv"
 	"strings"
 	"time"
 
@@ -23,12 +26,22 @@ type Attribute struct {
 	typeInstance interface{}
 	// defaultValue is the default value of the attribute
 	defaultValue interface{}
+	// defaultExpr is the default value as written in the CREATE TABLE statement.
+	defaultExpr string
 	// domain is the set of allowable values for the attribute
 	domain Domain
 	// autoIncrement is true if the attribute is auto-incremented
 	autoIncrement bool
 	// unique is true if the attribute is unique
 	unique bool
+	// notNull is true if the attribute cannot be NULL
+	notNull bool
+	// primaryKey is true if the attribute is the primary key of its table
+	primaryKey bool
+	// nocase is true if the values of the attribute are compared case-insensitively (COLLATE NOCASE)
+	nocase bool
+	// affinity is the type affinity of the attribute of a SQLite table, empty otherwise.
+	affinity string
 }
 
 // NewAttribute initialize a new Attribute struct
@@ -41,55 +54,293 @@ func NewAttribute(name string, typeName string, autoIncrement bool) Attribute {
 	return a
 }
 
-// parseAttribute parses a declaration and returns an Attribute
-func parseAttribute(decl *core.Decl) (Attribute, error) {
-	attr := Attribute{}
+// equal returns true if the given values of the attribute are equal. NULL is only equal to NULL.
+func (a Attribute) equal(v1, v2 interface{}) bool {
+	if v1 == nil || v2 == nil {
+		return v1 == nil && v2 == nil
+	}
+	return equalText(fmt.Sprintf("%v", v1), fmt.Sprintf("%v", v2), a.nocase)
+}
 
-	// Attribute name
-	if decl.TokenID != core.TokenIDString {
-		return attr, fmt.Errorf("engine: expected attribute name, got %v", decl.TokenID)
+// newAttribute returns the Attribute of a column definition of a CREATE TABLE statement.
+func newAttribute(column *core.ColumnDef) (Attribute, error) {
+	attr := Attribute{
+		name:          column.Name,
+		typeName:      column.Type.Name,
+		autoIncrement: column.AutoIncrement,
+		unique:        column.Unique || column.PrimaryKey,
+		notNull:       column.NotNull || column.PrimaryKey,
+		primaryKey:    column.PrimaryKey,
+		nocase:        column.Collate == "NOCASE",
+		affinity:      column.Type.Affinity,
 	}
-	attr.name = decl.Lexeme.String()
 
-	// Attribute type
-	if len(decl.DeclList) < 1 {
-		return attr, fmt.Errorf("attribute %s has no type", decl.Lexeme.String())
+	// Type size (e.g. varchar(255), decimal(10,2)) and WITH TIME ZONE
+	if len(column.Type.Args) > 0 {
+		attr.typeName = fmt.Sprintf("%s(%s)", attr.typeName, strings.Join(column.Type.Args, ","))
 	}
-	if decl.DeclList[0].TokenID != core.TokenIDString {
-		return attr, fmt.Errorf("engine: expected attribute type, got %v:%v", decl.DeclList[0].TokenID, decl.DeclList[0].Lexeme)
+	if column.Type.WithTimeZone {
+		attr.typeName += " with time zone"
 	}
-	attr.typeName = decl.DeclList[0].Lexeme.String()
 
-	// Maybe domain and special thing like primary key
-	typeDecl := decl.DeclList[1:]
-	for i := range typeDecl {
-		if typeDecl[i].TokenID == core.TokenIDAutoincrement {
-			attr.autoIncrement = true
-		}
-
-		if typeDecl[i].TokenID == core.TokenIDDefault {
-			switch typeDecl[i].DeclList[0].TokenID {
-			case core.TokenIDLocalTimestamp, core.TokenIDNow:
-				attr.defaultValue = func() interface{} { return time.Now().Format(core.DateLongFormat) }
-			default:
-				attr.defaultValue = typeDecl[i].DeclList[0].Lexeme
-			}
+	switch d := column.Default.(type) {
+	case nil:
+	case *core.Literal:
+		attr.defaultExpr = d.Value
+		if d.Kind == core.LiteralNow || d.Kind == core.LiteralLocalTimestamp {
+			attr.defaultValue = func() interface{} { return time.Now().Format(core.DateLongFormat) }
+			break
 		}
-		// Check if attribute is unique
-		if typeDecl[i].TokenID == core.TokenIDUnique {
-			attr.unique = true
+		v, err := attributeValue(attr, d)
+		if err != nil {
+			return attr, fmt.Errorf("invalid default value for attribute %s: %w", attr.name, err)
 		}
+		attr.defaultValue = v
+	default:
+		return attr, fmt.Errorf("invalid default value for attribute %s", attr.name)
 	}
 
-	if strings.ToLower(attr.typeName) == "bigserial" {
+	if isSerialType(attr.typeName) {
 		attr.autoIncrement = true
 	}
 	return attr, nil
 }
 
+// attributeValue converts a value expression to the internal value of the attribute:
+// int64 for integer types, float64 for numeric types, nil for NULL and string otherwise.
+func attributeValue(attr Attribute, expr core.Expr) (interface{}, error) {
+	var lit *core.Literal
+	switch v := expr.(type) {
+	case *core.Literal:
+		lit = v
+	case *core.Param:
+		return nil, unboundParameterError(v)
+	case *core.SequenceValue:
+		return nil, sequenceValueError(v)
+	default:
+		return nil, fmt.Errorf("unsupported value for attribute %s", attr.name)
+	}
+
+	switch lit.Kind {
+	case core.LiteralNull:
+		return nil, nil
+	case core.LiteralNow, core.LiteralLocalTimestamp:
+		return time.Now().Format(core.DateLongFormat), nil
+	default:
+	}
+
+	if attr.affinity != "" {
+		return affinityValue(attr, lit)
+	}
+	if lit.Kind == core.LiteralNumber && lit.Number != core.NumberKindNone {
+		return numberValue(attr, lit)
+	}
+	if !isIntegerType(attr.typeName) && !isNumericType(attr.typeName) {
+		return lit.Value, nil
+	}
+
+	// A quoted number (e.g. '42') is converted like the numeric literal it contains
+	text := strings.TrimPrefix(strings.TrimSpace(lit.Value), "+")
+	kind := core.NumberKindOf(text)
+	if kind == core.NumberKindNone {
+		return nil, invalidNumberError(attr, lit.Value)
+	}
+	return numberValue(attr, &core.Literal{Kind: core.LiteralNumber, Value: text, Number: kind})
+}
+
+// numberValue converts a numeric literal to the internal value of the attribute according
+// to the kind of the literal. Decimals and floats are not integers (e.g. 1.5 cannot be
+// inserted into an INTEGER column).
+func numberValue(attr Attribute, lit *core.Literal) (interface{}, error) {
+	switch {
+	case isIntegerType(attr.typeName):
+		if lit.Number != core.NumberKindInteger {
+			return nil, invalidNumberError(attr, lit.Value)
+		}
+		v, err := core.ParseInt(core.Lexeme(lit.Value))
+		if err != nil {
+			return nil, numberError(attr, lit.Value, err)
+		}
+		return v, nil
+	case isNumericType(attr.typeName):
+		v, err := core.NumberValue(lit.Number, core.Lexeme(lit.Value))
+		if err != nil {
+			return nil, numberError(attr, lit.Value, err)
+		}
+		if i, ok := v.(int64); ok {
+			return float64(i), nil
+		}
+		return v, nil
+	default:
+		return literalText(lit)
+	}
+}
+
+// numberError returns the error of a number which cannot be converted to the value of
+// the attribute, with the message of PostgreSQL.
+func numberError(attr Attribute, text string, err error) error {
+	if errors.Is(err, strconv.ErrRange) {
+		return fmt.Errorf("value \"%s\" is out of range for type %s", text, sqlTypeName(attr.typeName))
+	}
+	return invalidNumberError(attr, text)
+}
+
+// invalidNumberError returns the error of a text which is not a number of the type
+// of the attribute, with the message of PostgreSQL.
+func invalidNumberError(attr Attribute, text string) error {
+	return fmt.Errorf("invalid input syntax for type %s: \"%s\"", sqlTypeName(attr.typeName), text)
+}
+
+// sqlTypeName returns the name of a numeric type used by PostgreSQL in its messages
+// (e.g. integer for INT(11)). The other types keep their name.
+func sqlTypeName(typeName string) string {
+	switch baseTypeName(typeName) {
+	case "int", "integer", "serial":
+		return "integer"
+	case "int64", "bigint", "bigserial":
+		return "bigint"
+	case "smallint", "smallserial":
+		return "smallint"
+	case "numeric", "decimal", "number":
+		return "numeric"
+	case "float", "double":
+		return "double precision"
+	case "real":
+		return "real"
+	default:
+		return typeName
+	}
+}
+
+// affinityValue returns the value of a literal for an attribute of a SQLite table,
+// converted like SQLite does with the type affinity of the attribute. A TEXT attribute
+// stores numbers as text. INTEGER, REAL and NUMERIC attributes store numbers, and texts
+// which are well-formed numbers, as numbers: the other texts are stored as they are.
+// A BLOB attribute stores the value as it is.
+func affinityValue(attr Attribute, lit *core.Literal) (interface{}, error) {
+	text, err := literalText(lit)
+	if err != nil {
+		return nil, err
+	}
+	if attr.affinity == "TEXT" {
+		return text, nil
+	}
+	if attr.affinity == "BLOB" {
+		if lit.Number == core.NumberKindNone {
+			return text, nil
+		}
+		return core.NumberValue(lit.Number, core.Lexeme(lit.Value))
+	}
+
+	s := strings.TrimSpace(text)
+	if s == "" || strings.Trim(s, "+-.0123456789eE") != "" {
+		return text, nil
+	}
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		if attr.affinity == "REAL" {
+			return float64(i), nil
+		}
+		return i, nil
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return text, nil
+	}
+	// Like SQLite, a real without fractional part is stored as an integer if it fits
+	if attr.affinity != "REAL" && f == math.Trunc(f) && math.Abs(f) < 1<<63 {
+		return int64(f), nil
+	}
+	return f, nil
+}
+
+// isDefault returns true if the expression is the DEFAULT keyword of INSERT and UPDATE statements.
+func isDefault(expr core.Expr) bool {
+	lit, ok := expr.(*core.Literal)
+	return ok && lit.Kind == core.LiteralDefault
+}
+
+// literalText returns the text of a literal, e.g. to compare it with the values of an
+// attribute. Numbers are written in their canonical form (e.g. 0x1F is 31,
+// 1.5e-3 is 0.0015 and 1.50 is 1.5), the form of the numeric values of the attributes.
+func literalText(lit *core.Literal) (string, error) {
+	switch lit.Number {
+	case core.NumberKindInteger, core.NumberKindDecimal, core.NumberKindFloat:
+		v, err := core.NumberValue(lit.Number, core.Lexeme(lit.Value))
+		if err != nil {
+			return "", err
+		}
+		if f, ok := v.(float64); ok {
+			return strconv.FormatFloat(f, 'f', -1, 64), nil
+		}
+		return fmt.Sprint(v), nil
+	default:
+		return lit.Value, nil
+	}
+}
+
+// valueText returns the text of a value compared with the values of an attribute,
+// see literalText.
+func valueText(expr core.Expr) (string, error) {
+	switch v := expr.(type) {
+	case *core.Literal:
+		return literalText(v)
+	case *core.Param:
+		return "", unboundParameterError(v)
+	case *core.SequenceValue:
+		return "", sequenceValueError(v)
+	default:
+		return "", errors.New("only literal values can be compared with attributes")
+	}
+}
+
+// unboundParameterError returns the error for a parameter (e.g. ?1, :name) without value.
+// The values of the parameters are set by the driver before the query is executed.
+func unboundParameterError(p *core.Param) error {
+	return fmt.Errorf("no value bound to parameter %s", p.Name)
+}
+
+// isSerialType returns true if the type is auto-incremented.
+func isSerialType(typeName string) bool {
+	switch strings.ToLower(typeName) {
+	case "serial", "bigserial", "smallserial":
+		return true
+	default:
+		return false
+	}
+}
+
+// isIntegerType returns true if the values of the type are stored as int64.
+func isIntegerType(typeName string) bool {
+	switch baseTypeName(typeName) {
+	case "int64", "int", "integer", "smallint", "bigint", "serial", "bigserial", "smallserial":
+		return true
+	default:
+		return false
+	}
+}
+
+// isNumericType returns true if the values of the type are stored as float64.
+func isNumericType(typeName string) bool {
+	switch baseTypeName(typeName) {
+	case "numeric", "decimal", "real", "float", "double", "number":
+		return true
+	default:
+		return false
+	}
+}
+
+// baseTypeName returns the lower case name of a type without its size (e.g. int for INT(11)).
+func baseTypeName(typeName string) string {
+	name := strings.ToLower(typeName)
+	if i := strings.IndexByte(name, '('); i >= 0 {
+		name = name[:i]
+	}
+	return strings.TrimSpace(name)
+}
+
 // attributeExistsInTable checks if an attribute exists in a table
 func attributeExistsInTable(e *Engine, attr string, table string) error {
-	r := e.relation(table)
+	r := e.readRelation(table)
 	if r == nil {
 		return fmt.Errorf("table \"%s\" does not exist", table)
 	}
@@ -128,12 +379,12 @@ func attributesExistInTables(e *Engine, attributes []Attribute, tables []string)
 			if err := attributeExistsInTable(e, attr.name, t); err == nil {
 				found++
 			}
-			if found == 0 {
-				return fmt.Errorf("attribute %s does not exist in tables %v", attr.name, tables)
-			}
-			if found > 1 {
-				return fmt.Errorf("ambiguous attribute %s", attr.name)
-			}
+		}
+		if found == 0 {
+			return fmt.Errorf("attribute %s does not exist in tables %v", attr.name, tables)
+		}
+		if found > 1 {
+			return fmt.Errorf("ambiguous attribute %s", attr.name)
 		}
 	}
 	return nil
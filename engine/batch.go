@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"github.com/nao1215/aiondb/engine/protocol"
+)
+
+// batch is a wrapper around protocol.EngineConn for the statements sent at once
+// (e.g. "INSERT ...; INSERT ..."). A driver reads a single reply per call, so only the
+// reply of the last statement is written: its rows, or its result with the rows
+// affected by the previous statements added. The first error stops the batch.
+type batch struct {
+	// realConn is the underlying connection
+	realConn protocol.EngineConn
+	// last is true while the last statement of the batch is executed
+	last bool
+	// lastInsertedID is the last ID inserted by the statements executed so far
+	lastInsertedID int64
+	// rowsAffected is the number of rows affected by the statements executed so far
+	rowsAffected int64
+	// err is the error written by a statement which is not the last one
+	err error
+}
+
+// batchConn returns a connection that writes a single reply for several statements.
+func batchConn(conn protocol.EngineConn) *batch {
+	return &batch{realConn: conn}
+}
+
+// ReadStatement reads a statement from the underlying connection.
+// NOTE: This should not be used.
+func (b *batch) ReadStatement() (string, error) {
+	return "", nil
+}
+
+// WriteResult adds the result of a statement to the result of the batch.
+// The result of the batch is written with the result of the last statement.
+func (b *batch) WriteResult(lastInsertedID, rowsAffected int64) error {
+	if lastInsertedID != 0 {
+		b.lastInsertedID = lastInsertedID
+	}
+	b.rowsAffected += rowsAffected
+	if !b.last {
+		return nil
+	}
+	return b.realConn.WriteResult(b.lastInsertedID, b.rowsAffected)
+}
+
+// WriteError writes an error to the underlying connection if the statement is the
+// last one, otherwise it keeps the error to stop the batch.
+func (b *batch) WriteError(err error) error {
+	if !b.last {
+		b.err = err
+		return nil
+	}
+	return b.realConn.WriteError(err)
+}
+
+// WriteRowHeader writes a row header to the underlying connection if the statement
+// is the last one.
+func (b *batch) WriteRowHeader(header []string) error {
+	if !b.last {
+		return nil
+	}
+	return b.realConn.WriteRowHeader(header)
+}
+
+// WriteRow writes a row to the underlying connection if the statement is the last one.
+// The rows of the other statements (e.g. INSERT ... RETURNING) are counted as affected.
+func (b *batch) WriteRow(row []*string) error {
+	if !b.last {
+		b.rowsAffected++
+		return nil
+	}
+	return b.realConn.WriteRow(row)
+}
+
+// WriteRowEnd writes a row end to the underlying connection if the statement is the
+// last one.
+func (b *batch) WriteRowEnd() error {
+	if !b.last {
+		return nil
+	}
+	return b.realConn.WriteRowEnd()
+}
@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Tables returns the tables of the engine sorted by name.
+func (e *Engine) Tables() []*Table {
+	e.Lock()
+	defer e.Unlock()
+
+	tables := make([]*Table, 0, len(e.relations))
+	for _, r := range e.relations {
+		tables = append(tables, r.table)
+	}
+	sort.Slice(tables, func(i, j int) bool {
+		return tables[i].name < tables[j].name
+	})
+	return tables
+}
+
+// Table returns the table with the given name, or nil if it does not exist.
+func (e *Engine) Table(name string) *Table {
+	r := e.relation(name)
+	if r == nil {
+		return nil
+	}
+	return r.table
+}
+
+// Name returns the name of the table.
+func (t *Table) Name() string {
+	return t.name
+}
+
+// Attributes returns the attributes of the table in declaration order.
+func (t *Table) Attributes() []Attribute {
+	return append([]Attribute{}, t.attributes...)
+}
+
+// PrimaryKey returns the attributes of the composite primary key of the table, if any.
+// A single attribute primary key is reported by Attribute.PrimaryKey.
+func (t *Table) PrimaryKey() []string {
+	return append([]string{}, t.primaryKey...)
+}
+
+// Name returns the name of the attribute.
+func (a Attribute) Name() string {
+	return a.name
+}
+
+// TypeName returns the type of the attribute as declared (e.g. "varchar(255)").
+func (a Attribute) TypeName() string {
+	return a.typeName
+}
+
+// Default returns the default value of the attribute as an SQL expression,
+// or an empty string if the attribute has no default value.
+func (a Attribute) Default() string {
+	switch a.defaultValue.(type) {
+	case nil:
+		return a.defaultExpr
+	case string:
+		return fmt.Sprintf("'%s'", a.defaultExpr)
+	default:
+		return a.defaultExpr
+	}
+}
+
+// NotNull returns true if the attribute cannot be NULL.
+func (a Attribute) NotNull() bool {
+	return a.notNull
+}
+
+// Unique returns true if the values of the attribute are unique.
+func (a Attribute) Unique() bool {
+	return a.unique
+}
+
+// PrimaryKey returns true if the attribute is the primary key of its table.
+func (a Attribute) PrimaryKey() bool {
+	return a.primaryKey
+}
+
+// AutoIncrement returns true if the attribute is auto-incremented.
+func (a Attribute) AutoIncrement() bool {
+	return a.autoIncrement
+}
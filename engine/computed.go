@@ -0,0 +1,116 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// computedColumn is a column of the select list whose value is computed for each row
+// instead of being read from a table: a constant, NVL(attribute, value) or the NEXTVAL
+// and CURRVAL pseudocolumns of a sequence.
+type computedColumn struct {
+	// name is the key of the column in the virtual rows
+	name string
+	// eval returns the value of the column for a virtual row
+	eval func(row virtualRow) (interface{}, error)
+}
+
+// isComputedColumn returns true if the expression of the select list is a computed column.
+func isComputedColumn(expr core.Expr) bool {
+	switch v := expr.(type) {
+	case *core.Literal, *core.SequenceValue:
+		return true
+	case *core.FuncCall:
+		return v.Name != "count"
+	default:
+		return false
+	}
+}
+
+// computedColumnExecutor returns the computed column of an expression of the select list.
+// index is the position of the column among the computed columns of the statement.
+func computedColumnExecutor(e *Engine, expr core.Expr, tables []string, index int) (computedColumn, error) {
+	// The name contains a period, so that it is not qualified with a table name,
+	// and the text after the first period is used as column alias.
+	c := computedColumn{name: fmt.Sprintf("#%d.", index)}
+
+	switch v := expr.(type) {
+	case *core.Literal:
+		if v.Kind != core.LiteralNumber {
+			return c, fmt.Errorf("cannot select %s", v.Value)
+		}
+		c.name += v.Value
+		value, err := literalText(v)
+		if err != nil {
+			return c, err
+		}
+		c.eval = func(virtualRow) (interface{}, error) {
+			return value, nil
+		}
+	case *core.SequenceValue:
+		pseudocolumn := "currval"
+		if v.Next {
+			pseudocolumn = "nextval"
+		}
+		c.name += pseudocolumn
+		c.eval = func(virtualRow) (interface{}, error) {
+			return e.sequenceValue(v)
+		}
+	case *core.FuncCall:
+		c.name += v.Name
+		if len(v.Args) != 2 {
+			return c, fmt.Errorf("%s expects 2 arguments, got %d", v.Name, len(v.Args))
+		}
+		column, ok := v.Args[0].(*core.ColumnRef)
+		if !ok {
+			return c, fmt.Errorf("%s expects an attribute as first argument", v.Name)
+		}
+		attr, err := qualifyAttribute(e, column, tables)
+		if err != nil {
+			return c, err
+		}
+		var value string
+		switch arg := v.Args[1].(type) {
+		case *core.Literal:
+			value = arg.Value
+		case *core.Param:
+			return c, unboundParameterError(arg)
+		default:
+			return c, fmt.Errorf("%s expects a value as second argument", v.Name)
+		}
+		c.eval = func(row virtualRow) (interface{}, error) {
+			v, ok := row[attr]
+			if !ok {
+				return nil, fmt.Errorf("could not select attribute %s", attr)
+			}
+			if v.v == nil {
+				return value, nil
+			}
+			return v.v, nil
+		}
+	default:
+		return c, fmt.Errorf("cannot select %T", expr)
+	}
+	return c, nil
+}
+
+// computedSelectFunction adds the computed columns to the rows, then feeds them to
+// the select function it wraps.
+type computedSelectFunction struct {
+	selectFunctor
+	// columns is the list of computed columns of the select list.
+	columns []computedColumn
+}
+
+// FeedVirtualRow computes the columns of the row and feeds it to the wrapped select function.
+func (f *computedSelectFunction) FeedVirtualRow(row virtualRow) error {
+	for _, c := range f.columns {
+		v, err := c.eval(row)
+		if err != nil {
+			return err
+		}
+		row[c.name] = Value{v: v, valid: true, lexeme: c.name}
+	}
+	return f.selectFunctor.FeedVirtualRow(row)
+}
@@ -8,47 +8,36 @@ import (
 )
 
 // deleteExecutor executes a DELETE statement
-func deleteExecutor(e *Engine, deleteDecl *core.Decl, conn protocol.EngineConn) error {
-	// get tables to be deleted
-	tables := fromExecutor(deleteDecl.DeclList[0])
-
-	// If len is 1, it means no predicates so truncate table
-	if len(deleteDecl.DeclList) == 1 {
-		return truncateTable(e, tables[0], conn)
+func deleteExecutor(e *Engine, stmt *core.DeleteStmt, conn protocol.EngineConn) error {
+	// No predicates, so truncate table
+	if stmt.Where == nil {
+		return truncateTable(e, NewTable(stmt.Table.Name), conn)
 	}
 
-	// get WHERE declaration
-	predicates, err := whereExecutor(deleteDecl.DeclList[1], tables[0].name)
+	// get WHERE predicates
+	predicates, err := whereExecutor(stmt.Where, stmt.Table.Name)
 	if err != nil {
 		return err
 	}
-	return deleteRows(e, tables, conn, predicates)
+	return deleteRows(e, stmt.Table.Name, conn, predicates)
 }
 
 // deleteRows deletes rows from a table
-func deleteRows(e *Engine, tables []*Table, conn protocol.EngineConn, predicates []Predicate) error {
-	r := e.relation(tables[0].name)
+func deleteRows(e *Engine, tableName string, conn protocol.EngineConn, predicates []Predicate) error {
+	r := e.relation(tableName)
 	if r == nil {
-		return fmt.Errorf("table %s not found", tables[0].name)
+		return fmt.Errorf("table %s not found", tableName)
 	}
 	r.Lock()
 	defer r.Unlock()
 
-	var ok, res bool
-	var err error
 	var rowsDeleted int64
 	lenRows := len(r.rows)
 	for i := 0; i < lenRows; i++ {
-		ok = true
-		// If the row validate all predicates, write it
-		for _, predicate := range predicates {
-			if res, err = predicate.Evaluate(r.rows[i], r.table); err != nil {
-				return err
-			}
-			if !res {
-				ok = false
-				continue
-			}
+		// If the row validate all predicates, delete it
+		ok, err := matchTuple(r, r.rows[i], predicates)
+		if err != nil {
+			return err
 		}
 
 		if ok {
@@ -0,0 +1,53 @@
+package engine
+
+import (
+	"fmt"
+
+	"github.com/nao1215/aiondb/engine/parser/core"
+	"github.com/nao1215/aiondb/engine/protocol"
+)
+
+// describing returns true if the statements read from the connection must only be
+// described, see protocol.DescribeConn.
+func describing(conn protocol.EngineConn) bool {
+	c, ok := conn.(protocol.DescribeConn)
+	return ok && c.Describing()
+}
+
+// describeQuery writes the row header of the result of a statement without running it.
+// The header is derived from the statement and the tables, the statements which
+// return no rows write nothing. Parameters may be left unbound.
+func (e *Engine) describeQuery(stmt core.Statement, conn protocol.EngineConn) error {
+	ast, err := core.NewAST(stmt)
+	if err != nil {
+		return err
+	}
+
+	var header []string
+	switch s := ast.(type) {
+	case *core.SelectStmt:
+		sel, err := selectionExecutor(e, s)
+		if err != nil {
+			return err
+		}
+		header = sel.header(len(s.DistinctOn))
+	case *core.InsertStmt:
+		if s.Returning == nil {
+			return nil
+		}
+		r := e.relation(s.Table.Name)
+		if r == nil {
+			return fmt.Errorf("table %s does not exist", s.Table.Name)
+		}
+		if header, _, err = returningAttributes(r.table, s.Returning); err != nil {
+			return err
+		}
+	default:
+		return nil
+	}
+
+	if err := conn.WriteRowHeader(header); err != nil {
+		return err
+	}
+	return conn.WriteRowEnd()
+}
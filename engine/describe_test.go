@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/aiondb/engine/parser"
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+func TestDescribeQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		query string
+		want  *recorder
+	}{
+		{
+			name:  "Select with a parameter",
+			query: "SELECT id, name FROM users WHERE id = $1",
+			want:  &recorder{header: []string{"id", "name"}, ended: true},
+		},
+		{
+			name:  "Select all with a leading comment",
+			query: "-- comment\nSELECT * FROM users",
+			want:  &recorder{header: []string{"id", "name"}, ended: true},
+		},
+		{
+			name:  "Count",
+			query: "SELECT COUNT(*) FROM users WHERE name = $1",
+			want:  &recorder{header: []string{"count"}, ended: true},
+		},
+		{
+			name:  "Insert with RETURNING",
+			query: "INSERT INTO users (id, name) VALUES ($1, $2) RETURNING id",
+			want:  &recorder{header: []string{"id"}, ended: true},
+		},
+		{
+			name:  "Insert without RETURNING",
+			query: "INSERT INTO users (id, name) VALUES ($1, $2)",
+			want:  &recorder{},
+		},
+		{
+			name:  "Update",
+			query: "UPDATE users SET name = $1 WHERE id = $2",
+			want:  &recorder{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := newTestEngine(t)
+			if _, err := run(t, e, "CREATE TABLE users (id INT PRIMARY KEY, name TEXT)"); err != nil {
+				t.Fatal(err)
+			}
+			stmts, err := parser.NewParser(core.SQLSyntaxModePostgreSQL).Parse(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			got := &recorder{}
+			if err := e.describeQuery(stmts[0], got); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(recorder{})); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+
+			// The statement is not run
+			rows, err := run(t, e, "SELECT COUNT(*) FROM users")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff([][]string{{"0"}}, rows.rows); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -48,7 +48,7 @@ func (l *distinct) WriteRowHeader(header []string) error {
 }
 
 // WriteRow writes a row.
-func (l *distinct) WriteRow(row []string) error {
+func (l *distinct) WriteRow(row []*string) error {
 	if l.len > 0 {
 		if l.seen.exists(row[:l.len]) {
 			return nil
@@ -93,22 +93,40 @@ func (l *distinct) equalRows(a, b []string) bool {
 	return true
 }
 
+// seenValue is a value of a seen row. NULL values are equal to each other,
+// and differ from any text.
+type seenValue struct {
+	// null is true for NULL.
+	null bool
+	// text is the text of a value which is not NULL.
+	text string
+}
+
+// newSeenValue returns the seen value of a value of a row.
+func newSeenValue(v *string) seenValue {
+	if v == nil {
+		return seenValue{null: true}
+	}
+	return seenValue{text: *v}
+}
+
 // seen is a map of seen rows
-type seen map[string]seen
+type seen map[seenValue]seen
 
 // exists returns true if the row exists in the map
-func (s seen) exists(r []string) bool {
-	if c, ok := s[r[0]]; ok {
+func (s seen) exists(r []*string) bool {
+	key := newSeenValue(r[0])
+	if c, ok := s[key]; ok {
 		if len(r) == 1 {
 			return true
 		}
 		return c.exists(r[1:])
 	}
 
-	s[r[0]] = make(seen)
+	s[key] = make(seen)
 	if len(r) == 1 {
 		return false
 	}
 	// does not exists, but we want to populate the tree fully
-	return s[r[0]].exists(r[1:])
+	return s[key].exists(r[1:])
 }
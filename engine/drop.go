@@ -7,17 +7,9 @@ import (
 	"github.com/nao1215/aiondb/engine/protocol"
 )
 
-// dropExecutor executes drop statement
-func dropExecutor(e *Engine, dropDecl *core.Decl, conn protocol.EngineConn) error {
-	// Should have table token
-	if dropDecl.DeclList == nil ||
-		len(dropDecl.DeclList) != 1 ||
-		dropDecl.DeclList[0].TokenID != core.TokenIDTable ||
-		len(dropDecl.DeclList[0].DeclList) != 1 {
-		return fmt.Errorf("unexpected drop arguments")
-	}
-
-	table := dropDecl.DeclList[0].DeclList[0].Lexeme.String()
+// dropTableExecutor executes a DROP TABLE statement
+func dropTableExecutor(e *Engine, stmt *core.DropTableStmt, conn protocol.EngineConn) error {
+	table := stmt.Table.Name
 	if r := e.relation(table); r == nil {
 		return fmt.Errorf("relation '%s' not found", table)
 	}
@@ -25,3 +17,11 @@ func dropExecutor(e *Engine, dropDecl *core.Decl, conn protocol.EngineConn) erro
 
 	return conn.WriteResult(0, 1)
 }
+
+// dropSequenceExecutor executes a DROP SEQUENCE statement
+func dropSequenceExecutor(e *Engine, stmt *core.DropSequenceStmt, conn protocol.EngineConn) error {
+	if err := e.dropSequence(stmt.Name); err != nil {
+		return err
+	}
+	return conn.WriteResult(0, 1)
+}
@@ -7,6 +7,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"strings"
 	"sync"
 
 	"github.com/nao1215/aiondb/engine/parser"
@@ -14,9 +15,6 @@ import (
 	"github.com/nao1215/aiondb/engine/protocol"
 )
 
-// executor is a function that executes a statement
-type executor func(*Engine, *core.Decl, protocol.EngineConn) error
-
 // Engine is the root struct of AION DB server.
 // It contains the endpoint to accept connections from drivers,
 // the relations and the operations executors.
@@ -25,14 +23,14 @@ type Engine struct {
 	endpoint protocol.EngineEndpoint
 	// relations is the map of all relations.
 	relations map[string]*Relation
-	// opsExecutors is the map of all operations executors.
-	opsExecutors map[core.TokenID]executor
+	// sequences is the map of all sequences.
+	sequences map[string]*Sequence
 	// stop is the channel used to stop the listening loop.
-	// Any value send to this channel (through Engine.stop), will stop the listening loop
+	// It is closed (through Engine.Stop) to stop the listening loop.
 	stop chan bool
-	// parser is the parser used to parse the SQL statements.
-	parser parser.Parser
-	// mu is the mutex used to protect the relations map.
+	// stopOnce ensures the stop channel is closed only once.
+	stopOnce sync.Once
+	// mu is the mutex used to protect the relations and sequences maps.
 	sync.Mutex
 }
 
@@ -43,22 +41,8 @@ func New(endpoint protocol.EngineEndpoint) (e *Engine, err error) {
 	}
 
 	e.stop = make(chan bool)
-	e.opsExecutors = map[core.TokenID]executor{
-		core.TokenIDCreate: createExecutor,
-		// core.TokenIDTable:    createTableExecutor,
-		// core.TokenIDSelect:   selectExecutor,
-		core.TokenIDInsert: insertIntoTableExecutor,
-		core.TokenIDDelete: deleteExecutor,
-		// core.TokenIDUpdate:   updateExecutor,
-		core.TokenIDIf:       ifExecutor,
-		core.TokenIDNot:      notExecutor,
-		core.TokenIDExists:   existsExecutor,
-		core.TokenIDTruncate: truncateExecutor,
-		core.TokenIDDrop:     dropExecutor,
-		core.TokenIDGrant:    grantExecutor,
-	}
 	e.relations = make(map[string]*Relation)
-	e.parser = parser.NewParser(core.SQLSyntaxModePostgreSQL)
+	e.sequences = make(map[string]*Sequence)
 
 	e.start()
 	return
@@ -81,7 +65,11 @@ func (e *Engine) listen() {
 				e.Stop()
 				return
 			}
-			newConnectionChannel <- conn
+			select {
+			case newConnectionChannel <- conn:
+			case <-e.stop:
+				return
+			}
 		}
 	}()
 
@@ -97,22 +85,32 @@ func (e *Engine) listen() {
 	}
 }
 
-// Stop stops the listening loop.
+// Stop stops the listening loop. It is safe to call it several times.
 func (e *Engine) Stop() {
-	if e.stop == nil {
-		// already stopped
-		return
-	}
-
-	go func() {
-		e.stop <- true
+	e.stopOnce.Do(func() {
 		close(e.stop)
-		e.stop = nil
-	}()
+	})
+}
+
+// Done returns a channel closed once the engine is stopped,
+// either by Stop or because its endpoint stopped accepting connections.
+func (e *Engine) Done() <-chan bool {
+	return e.stop
 }
 
 // handleConnection handles a new connection.
+// Each connection has its own parser, for the SQL syntax mode of its statements.
+// A panic of the connection is written to it as an error and closes the connection,
+// the other connections are not affected.
 func (e *Engine) handleConnection(conn protocol.EngineConn) {
+	defer func() {
+		if r := recover(); r != nil {
+			conn.WriteError(fmt.Errorf("fatal error: %s", r)) //nolint
+		}
+	}()
+
+	var p parser.Parser
+	var mode core.SQLSyntaxMode
 	for {
 		stmt, err := conn.ReadStatement()
 		if errors.Is(err, io.EOF) {
@@ -123,14 +121,22 @@ func (e *Engine) handleConnection(conn protocol.EngineConn) {
 			return
 		}
 
-		stmtList, err := e.parser.Parse(stmt)
+		// The syntax mode of a network connection is known once its first frame is read
+		if m := syntaxMode(conn); p == nil || m != mode {
+			p, mode = parser.NewParser(m), m
+		}
+		stmtList, err := p.Parse(stmt)
 		if err != nil {
 			// TODO: handle error
 			conn.WriteError(err) //nolint
 			continue
 		}
 
-		err = e.executeQueries(stmtList, conn)
+		if describing(conn) && len(stmtList) == 1 {
+			err = e.describeQuery(stmtList[0], conn)
+		} else {
+			err = e.executeQueries(stmtList, conn)
+		}
 		if err != nil {
 			// TODO: handle error
 			conn.WriteError(err) //nolint
@@ -139,30 +145,81 @@ func (e *Engine) handleConnection(conn protocol.EngineConn) {
 	}
 }
 
-// executeQuery executes a single query.
+// syntaxMode returns the SQL syntax mode of the statements of a connection.
+// Connections which do not implement protocol.SyntaxModeConn use the PostgreSQL syntax.
+func syntaxMode(conn protocol.EngineConn) core.SQLSyntaxMode {
+	if c, ok := conn.(protocol.SyntaxModeConn); ok {
+		return c.SyntaxMode()
+	}
+	return core.SQLSyntaxModePostgreSQL
+}
+
+// Execute executes statements parsed by the caller and writes their results to conn.
+// It lets tools run scripts without going through an endpoint.
+func (e *Engine) Execute(stmts []core.Statement, conn protocol.EngineConn) error {
+	return e.executeQueries(stmts, conn)
+}
+
+// executeQueries executes the statements in order, it stops at the first error.
+// Several statements sent at once get a single reply, see batch.
+// A panic of an executor is returned as an error, so that it does not stop the engine.
 func (e *Engine) executeQueries(stmts []core.Statement, conn protocol.EngineConn) (err error) {
 	defer func() {
 		if r := recover(); r != nil {
 			err = fmt.Errorf("fatal error: %s", r)
-			return
 		}
 	}()
 
-	for _, v := range stmts {
-		err = e.executeQuery(v, conn)
-		if err != nil {
+	if len(stmts) == 1 {
+		return e.executeQuery(stmts[0], conn)
+	}
+
+	// A single reply is written for all the statements, see batch.
+	b := batchConn(conn)
+	for i, v := range stmts {
+		b.last = i == len(stmts)-1
+		if err := e.executeQuery(v, b); err != nil {
 			return err
 		}
+		if b.err != nil {
+			return b.err
+		}
 	}
 	return nil
 }
 
 // executeQuery executes a single query.
+// The statement is first converted to the typed AST, which checks the structure of the
+// declaration tree, then the executor of the statement runs on the typed AST.
 func (e *Engine) executeQuery(stmt core.Statement, conn protocol.EngineConn) error {
-	if e.opsExecutors[stmt.Decls[0].TokenID] != nil {
-		return e.opsExecutors[stmt.Decls[0].TokenID](e, stmt.Decls[0], conn)
+	ast, err := core.NewAST(stmt)
+	if err != nil {
+		return err
+	}
+	switch s := ast.(type) {
+	case *core.SelectStmt:
+		return selectExecutor(e, s, conn)
+	case *core.InsertStmt:
+		return insertIntoTableExecutor(e, s, conn)
+	case *core.UpdateStmt:
+		return updateExecutor(e, s, conn)
+	case *core.DeleteStmt:
+		return deleteExecutor(e, s, conn)
+	case *core.CreateTableStmt:
+		return createTableExecutor(e, s, conn)
+	case *core.TruncateStmt:
+		return truncateExecutor(e, s, conn)
+	case *core.DropTableStmt:
+		return dropTableExecutor(e, s, conn)
+	case *core.DropSequenceStmt:
+		return dropSequenceExecutor(e, s, conn)
+	case *core.CreateSequenceStmt:
+		return createSequenceExecutor(e, s, conn)
+	case *core.GrantStmt:
+		return grantExecutor(e, s, conn)
+	default:
+		return errors.New("not implemented")
 	}
-	return errors.New("not implemented")
 }
 
 // relation returns the relation with the given name.
@@ -173,25 +230,27 @@ func (e *Engine) relation(name string) *Relation {
 	return r
 }
 
+// readRelation returns the relation with the given name to be read by a SELECT statement.
+// Unless a table named dual exists, DUAL is a relation with a single row like the DUAL
+// table of Oracle, so that expressions can be selected without table (e.g. SELECT 1 FROM DUAL).
+func (e *Engine) readRelation(name string) *Relation {
+	if r := e.relation(name); r != nil || !strings.EqualFold(name, "dual") {
+		return r
+	}
+	t := NewTable(name)
+	t.attributes = []Attribute{NewAttribute("dummy", "varchar2(1)", false)}
+	r := NewRelation(t)
+	r.rows = []*Tuple{NewTuple("X")}
+	return r
+}
+
 func (e *Engine) drop(name string) {
 	e.Lock()
 	delete(e.relations, name)
 	e.Unlock()
 }
 
-// createExecutor executes a CREATE statement.
-func createExecutor(e *Engine, createDecl *core.Decl, conn protocol.EngineConn) error {
-	if len(createDecl.DeclList) == 0 {
-		return errors.New("parsing failed, no declaration after CREATE")
-	}
-
-	if e.opsExecutors[createDecl.DeclList[0].TokenID] != nil {
-		return e.opsExecutors[createDecl.DeclList[0].TokenID](e, createDecl.DeclList[0], conn)
-	}
-	return errors.New("parsing failed, unknown token " + createDecl.DeclList[0].Lexeme.String())
-}
-
 // grantExecutor executes a GRANT statement.
-func grantExecutor(_ *Engine, _ *core.Decl, conn protocol.EngineConn) error {
+func grantExecutor(_ *Engine, _ *core.GrantStmt, conn protocol.EngineConn) error {
 	return conn.WriteResult(0, 0)
 }
@@ -0,0 +1,126 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/nao1215/aiondb/engine/parser"
+	"github.com/nao1215/aiondb/engine/parser/core"
+	"github.com/nao1215/aiondb/engine/protocol"
+)
+
+// recorder is a protocol.EngineConn recording what the engine writes.
+type recorder struct {
+	header         []string
+	rows           [][]string
+	ended          bool
+	lastInsertedID int64
+	rowsAffected   int64
+}
+
+func (r *recorder) ReadStatement() (string, error) {
+	return "", nil
+}
+
+func (r *recorder) WriteResult(lastInsertedID int64, rowsAffected int64) error {
+	r.lastInsertedID = lastInsertedID
+	r.rowsAffected = rowsAffected
+	return nil
+}
+
+func (r *recorder) WriteError(err error) error {
+	return err
+}
+
+func (r *recorder) WriteRowHeader(header []string) error {
+	r.header = header
+	return nil
+}
+
+// WriteRow records the row, NULL values are recorded as "NULL".
+func (r *recorder) WriteRow(row []*string) error {
+	values := make([]string, 0, len(row))
+	for _, v := range row {
+		if v == nil {
+			values = append(values, "NULL")
+			continue
+		}
+		values = append(values, *v)
+	}
+	r.rows = append(r.rows, values)
+	return nil
+}
+
+func (r *recorder) WriteRowEnd() error {
+	r.ended = true
+	return nil
+}
+
+// newTestEngine returns an engine stopped at the end of the test.
+func newTestEngine(t *testing.T) *Engine {
+	t.Helper()
+
+	_, engineEndpoint := protocol.NewChannelEndpoints()
+	e, err := New(engineEndpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(e.Stop)
+	return e
+}
+
+// run parses and executes the query, and returns what the engine wrote.
+func run(t *testing.T, e *Engine, query string) (*recorder, error) {
+	t.Helper()
+	return runWithMode(t, e, core.SQLSyntaxModePostgreSQL, query)
+}
+
+// runWithMode parses the query with the SQL syntax mode and executes it, and returns what the engine wrote.
+func runWithMode(t *testing.T, e *Engine, mode core.SQLSyntaxMode, query string) (*recorder, error) {
+	t.Helper()
+
+	stmts, err := parser.NewParser(mode).Parse(query)
+	if err != nil {
+		return nil, err
+	}
+	r := &recorder{}
+	return r, e.executeQueries(stmts, r)
+}
+
+// addRelation adds a relation with the given attributes and rows to the engine.
+func addRelation(e *Engine, name string, attributes []Attribute, rows ...[]interface{}) {
+	t := NewTable(name)
+	t.attributes = attributes
+	r := NewRelation(t)
+	for _, values := range rows {
+		_ = r.Insert(NewTuple(values...))
+	}
+	e.relations[name] = r
+}
+
+// panicConn is a protocol.EngineConn whose ReadStatement panics.
+type panicConn struct {
+	recorder
+	err error
+}
+
+func (c *panicConn) ReadStatement() (string, error) {
+	panic("index out of range")
+}
+
+func (c *panicConn) WriteError(err error) error {
+	c.err = err
+	return nil
+}
+
+func TestHandleConnectionRecoversPanic(t *testing.T) {
+	t.Parallel()
+
+	e := newTestEngine(t)
+	conn := &panicConn{}
+	e.handleConnection(conn)
+
+	want := "fatal error: index out of range"
+	if conn.err == nil || conn.err.Error() != want {
+		t.Errorf("mismatch error: want=%s, got=%v", want, conn.err)
+	}
+}
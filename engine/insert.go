@@ -3,144 +3,276 @@ package engine
 import (
 	"errors"
 	"fmt"
-	"strconv"
-	"strings"
-	"time"
 
 	"github.com/nao1215/aiondb/engine/parser/core"
 	"github.com/nao1215/aiondb/engine/protocol"
 )
 
 // insertIntoTableExecutor is the executor for INSERT INTO statements.
-func insertIntoTableExecutor(e *Engine, insertDecl *core.Decl, conn protocol.EngineConn) error {
-	// Get table and concerned attributes and write lock it
-	intoDecl := insertDecl.DeclList[0]
-	r, attributes, err := getRelation(e, intoDecl)
+func insertIntoTableExecutor(e *Engine, stmt *core.InsertStmt, conn protocol.EngineConn) error {
+	// Get table and check concerned attributes, then write lock it
+	r, err := getRelation(e, stmt)
 	if err != nil {
 		return err
 	}
 	r.Lock()
 	defer r.Unlock()
 
-	// Check for RETURNING clause
-	var returnedID string
-	if len(insertDecl.DeclList) > 2 {
-		for i := range insertDecl.DeclList {
-			if insertDecl.DeclList[i].TokenID == core.TokenIDReturning {
-				returningDecl := insertDecl.DeclList[i]
-				returnedID = returningDecl.Lexeme.String()
-				break
-			}
+	var assignments []assignment
+	if stmt.OnDuplicateKeyUpdate != nil {
+		if assignments, err = setExecutor(r.table, stmt.OnDuplicateKeyUpdate); err != nil {
+			return err
 		}
 	}
 
 	// Create a new tuple with values
-	ids := []int64{}
-	valuesDecl := insertDecl.DeclList[1]
-	for _, valueListDecl := range valuesDecl.DeclList {
+	var lastID, affected int64
+	tuples := []*Tuple{}
+	for _, row := range stmt.Rows {
 		// TODO handle all inserts atomically
-		id, err := insert(r, attributes, valueListDecl.DeclList, returnedID)
+		values, err := e.sequenceValues(row)
+		if err != nil {
+			return err
+		}
+
+		if stmt.OnDuplicateKeyUpdate != nil {
+			id, n, err := upsert(r, stmt.Columns, values, assignments)
+			if err != nil {
+				return err
+			}
+			lastID = id
+			affected += n
+			continue
+		}
+
+		if stmt.Or != core.ConflictAbort {
+			id, t, err := insertOr(r, stmt.Columns, values, stmt.Or)
+			if err != nil {
+				return err
+			}
+			if t == nil { // ignored
+				continue
+			}
+			lastID = id
+			affected++
+			tuples = append(tuples, t)
+			continue
+		}
+
+		id, t, err := insert(r, stmt.Columns, values)
 		if err != nil {
 			return err
 		}
-		ids = append(ids, id)
+		lastID = id
+		affected++
+		tuples = append(tuples, t)
+	}
+
+	// if RETURNING clause is present
+	if stmt.Returning != nil {
+		return writeReturning(r, stmt.Returning, tuples, conn)
+	}
+	return conn.WriteResult(lastID, affected)
+}
+
+// writeReturning writes the attributes of the RETURNING clause of the given tuples.
+func writeReturning(r *Relation, returning []core.Expr, tuples []*Tuple, conn protocol.EngineConn) error {
+	header, indexes, err := returningAttributes(r.table, returning)
+	if err != nil {
+		return err
 	}
 
-	// if RETURNING decl is not present
-	if returnedID != "" {
-		if err := conn.WriteRowHeader([]string{returnedID}); err != nil {
+	if err := conn.WriteRowHeader(header); err != nil {
+		return err
+	}
+	for _, t := range tuples {
+		row := make([]*string, 0, len(indexes))
+		for _, i := range indexes {
+			row = append(row, rowValue(t.Values[i]))
+		}
+		if err := conn.WriteRow(row); err != nil {
 			return err
 		}
-		for _, id := range ids {
-			if err := conn.WriteRow([]string{fmt.Sprintf("%v", id)}); err != nil {
-				return err
+	}
+	return conn.WriteRowEnd()
+}
+
+// returningAttributes returns the names and the indexes of the attributes of a RETURNING clause.
+func returningAttributes(t *Table, returning []core.Expr) ([]string, []int, error) {
+	var header []string
+	var indexes []int
+	for _, expr := range returning {
+		switch v := expr.(type) {
+		case *core.Star:
+			for i, attr := range t.attributes {
+				header = append(header, attr.name)
+				indexes = append(indexes, i)
 			}
+		case *core.ColumnRef:
+			i := t.attributeIndex(v.Name)
+			if i < 0 {
+				return nil, nil, fmt.Errorf("attribute %s does not exist in table %s", v.Name, t.name)
+			}
+			header = append(header, v.Name)
+			indexes = append(indexes, i)
+		default:
+			return nil, nil, fmt.Errorf("cannot return %T", expr)
 		}
-		return conn.WriteRowEnd()
 	}
-	return conn.WriteResult(ids[len(ids)-1], (int64)(len(ids)))
+	return header, indexes, nil
 }
 
-// getRelation returns the relation and the attributes of the table
-func getRelation(e *Engine, intoDecl *core.Decl) (*Relation, []*core.Decl, error) {
-	// Decl[0] is the table name
-	r := e.relation(intoDecl.DeclList[0].Lexeme.String())
+// getRelation returns the relation rows are inserted into, after checking the inserted attributes exist.
+func getRelation(e *Engine, stmt *core.InsertStmt) (*Relation, error) {
+	r := e.relation(stmt.Table.Name)
 	if r == nil {
-		return nil, nil, errors.New("table " + intoDecl.DeclList[0].Lexeme.String() + " does not exist")
+		return nil, errors.New("table " + stmt.Table.Name + " does not exist")
+	}
+
+	for _, column := range stmt.Columns {
+		if err := attributeExistsInTable(e, column, stmt.Table.Name); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// insert inserts a new tuple in the relation. It returns the value assigned
+// to the auto-incremented attribute, if any, and the inserted tuple.
+func insert(r *Relation, attributes []string, values []core.Expr) (int64, *Tuple, error) {
+	id, t, err := newTuple(r, attributes, values)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if err := checkTuple(r, t); err != nil {
+		return 0, nil, err
+	}
+
+	// Insert tuple
+	if err := r.Insert(t); err != nil {
+		return 0, nil, err
+	}
+	return id, t, nil
+}
+
+// insertOr inserts a new tuple in the relation like insert, resolving the conflicts with the
+// constraints of the relation like INSERT OR REPLACE and INSERT OR IGNORE of SQLite.
+// REPLACE deletes the rows having the same unique or primary key values as the tuple before
+// inserting it. IGNORE skips the tuple if it violates a constraint, the returned tuple is nil then.
+func insertOr(r *Relation, attributes []string, values []core.Expr, conflict core.ConflictAction) (int64, *Tuple, error) {
+	id, t, err := newTuple(r, attributes, values)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	rows := r.rows
+	if conflict == core.ConflictReplace {
+		kept := make([]*Tuple, 0, len(r.rows))
+		for _, row := range r.rows {
+			if !conflicts(r, row, t) {
+				kept = append(kept, row)
+			}
+		}
+		r.rows = kept
+	}
+
+	if err := checkTuple(r, t); err != nil {
+		r.rows = rows
+		if conflict == core.ConflictIgnore {
+			return 0, nil, nil
+		}
+		return 0, nil, err
 	}
+	if err := r.Insert(t); err != nil {
+		r.rows = rows
+		return 0, nil, err
+	}
+	return id, t, nil
+}
+
+// upsert inserts a new tuple in the relation like insert, unless the tuple has the same
+// unique or primary key values as an existing row. The assignments of the ON DUPLICATE KEY
+// UPDATE clause are applied to this row instead. Like MySQL, it returns the value of the
+// auto-incremented attribute, if any, and 1 if the tuple was inserted or 2 if a row was updated.
+func upsert(r *Relation, attributes []string, values []core.Expr, assignments []assignment) (int64, int64, error) {
+	id, t, err := newTuple(r, attributes, values)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	i := duplicateRow(r, t)
+	if i < 0 {
+		if err := checkTuple(r, t); err != nil {
+			return 0, 0, err
+		}
+		if err := r.Insert(t); err != nil {
+			return 0, 0, err
+		}
+		return id, 1, nil
+	}
+
+	row := r.rows[i]
+	updated, err := updateTuple(r, row, assignments, t)
+	if err != nil {
+		return 0, 0, err
+	}
+	r.rows[i] = updated
+	if err := checkTuple(r, updated); err != nil {
+		r.rows[i] = row
+		return 0, 0, err
+	}
+	bumpSequence(r, updated)
 
-	for i := range intoDecl.DeclList[0].DeclList {
-		if err := attributeExistsInTable(e, intoDecl.DeclList[0].DeclList[i].Lexeme.String(), intoDecl.DeclList[0].Lexeme.String()); err != nil {
-			return nil, nil, err
+	id = 0
+	for j, attr := range r.table.attributes {
+		if v, ok := updated.Values[j].(int64); ok && attr.autoIncrement {
+			id = v
 		}
 	}
-	return r, intoDecl.DeclList[0].DeclList, nil
+	return id, 2, nil
 }
 
-// insert inserts a new tuple in the relation
-func insert(r *Relation, attributes []*core.Decl, values []*core.Decl, returnedID string) (int64, error) {
-	var assigned bool
+// newTuple returns a new tuple with the given values of the attributes, and the default values
+// of the other attributes. It returns the value assigned to the auto-incremented attribute, if any.
+// The constraints of the relation are not checked.
+func newTuple(r *Relation, attributes []string, values []core.Expr) (int64, *Tuple, error) {
 	var id int64
-	var valuesindex int
+
+	if len(attributes) != len(values) {
+		return 0, nil, fmt.Errorf("INSERT has %d target columns but %d expressions", len(attributes), len(values))
+	}
 
 	// Create tuple
 	t := NewTuple()
 
-	for attrindex, attr := range r.table.attributes {
-		assigned = false
-		for x, decl := range attributes {
-			if attr.name == decl.Lexeme.String() && !attr.autoIncrement {
-				// Before adding value in tuple, check it's not a builtin func or arithmetic operation
-				switch values[x].TokenID {
-				case core.TokenIDNow:
-					t.Append(time.Now().Format(core.DateLongFormat))
-				default:
-					switch strings.ToLower(attr.typeName) {
-					case "int64", "int":
-						val, err := strconv.ParseInt(values[x].Lexeme.String(), 10, 64)
-						if err != nil {
-							return 0, err
-						}
-						t.Append(val)
-					case "numeric", "decimal":
-						val, err := strconv.ParseFloat(values[x].Lexeme.String(), 64)
-						if err != nil {
-							return 0, err
-						}
-						t.Append(val)
-					default:
-						t.Append(values[x].Lexeme)
-					}
-				}
-				valuesindex = x
-				assigned = true
-				if returnedID == attr.name {
-					var err error
-					id, err = strconv.ParseInt(values[x].Lexeme.String(), 10, 64)
-					if err != nil {
-						return 0, err
-					}
-				}
+	for _, attr := range r.table.attributes {
+		var value interface{}
+		assigned := false
+		for x, name := range attributes {
+			if attr.name != name || isDefault(values[x]) {
+				continue
+			}
+			// Before adding value in tuple, check it's not a builtin func or arithmetic operation
+			v, err := attributeValue(attr, values[x])
+			if err != nil {
+				return 0, nil, err
 			}
+			value = v
+			assigned = true
 		}
 
 		// If attribute is AUTO INCREMENT, compute it and assign it
 		if attr.autoIncrement {
-			assigned = true
-			id = int64(len(r.rows) + 1)
-			t.Append(id)
-		}
-
-		// Do we have a UNIQUE attribute ? if so
-		if attr.unique {
-			for i := range r.rows { // check all value already in relation (yup, no index tree)
-				val, ok := r.rows[i].Values[attrindex].(string)
-				if !ok {
-					return 0, fmt.Errorf("failed to type assertion")
-				}
-				if val == string(values[valuesindex].Lexeme) {
-					return 0, fmt.Errorf("unique constraint violation")
-				}
+			if !assigned || value == nil {
+				r.sequence++
+				value = r.sequence
+				assigned = true
+			} else if v, ok := value.(int64); ok && v > r.sequence {
+				r.sequence = v
+			}
+			if v, ok := value.(int64); ok {
+				id = v
 			}
 		}
 
@@ -148,16 +280,99 @@ func insert(r *Relation, attributes []*core.Decl, values []*core.Decl, returnedI
 		if !assigned {
 			switch val := attr.defaultValue.(type) {
 			case func() interface{}:
-				t.Append(val)
+				value = val()
 			default:
-				t.Append(attr.defaultValue)
+				value = attr.defaultValue
 			}
 		}
+		t.Append(value)
 	}
+	return id, t, nil
+}
 
-	// Insert tuple
-	if err := r.Insert(t); err != nil {
-		return 0, err
+// duplicateRow returns the index of the first row conflicting with the tuple, or -1 if there is none.
+func duplicateRow(r *Relation, t *Tuple) int {
+	for i, row := range r.rows {
+		if conflicts(r, row, t) {
+			return i
+		}
+	}
+	return -1
+}
+
+// conflicts returns true if the row has the same value as the tuple for a unique
+// attribute or the same composite primary key.
+func conflicts(r *Relation, row *Tuple, t *Tuple) bool {
+	for j, attr := range r.table.attributes {
+		if attr.unique && t.Values[j] != nil && attr.equal(row.Values[j], t.Values[j]) {
+			return true
+		}
+	}
+
+	if len(r.table.primaryKey) == 0 {
+		return false
+	}
+	for _, name := range r.table.primaryKey {
+		j := r.table.attributeIndex(name)
+		if !r.table.attributes[j].equal(row.Values[j], t.Values[j]) {
+			return false
+		}
+	}
+	return true
+}
+
+// checkConstraints checks the NOT NULL and UNIQUE constraints of the attribute for the given value.
+// The tuple being replaced, if any, is excluded from the UNIQUE check.
+func checkConstraints(r *Relation, attr Attribute, value interface{}, replaced *Tuple) error {
+	if value == nil {
+		if attr.notNull {
+			return fmt.Errorf("null value in column \"%s\" violates not-null constraint", attr.name)
+		}
+		// NULL values never conflict
+		return nil
+	}
+
+	if !attr.unique {
+		return nil
+	}
+	index := r.table.attributeIndex(attr.name)
+	for i := range r.rows { // check all value already in relation (yup, no index tree)
+		if r.rows[i] == replaced {
+			continue
+		}
+		if attr.equal(r.rows[i].Values[index], value) {
+			return fmt.Errorf("duplicate key value violates unique constraint on column \"%s\"", attr.name)
+		}
+	}
+	return nil
+}
+
+// checkPrimaryKey checks the composite primary key of the relation, if any, for the given tuple.
+// The tuple being replaced, if any, is excluded from the check.
+func checkPrimaryKey(r *Relation, t *Tuple, replaced *Tuple) error {
+	if len(r.table.primaryKey) == 0 {
+		return nil
+	}
+
+	indexes := make([]int, 0, len(r.table.primaryKey))
+	for _, name := range r.table.primaryKey {
+		indexes = append(indexes, r.table.attributeIndex(name))
+	}
+
+	for _, row := range r.rows {
+		if row == replaced {
+			continue
+		}
+		equal := true
+		for _, i := range indexes {
+			if !r.table.attributes[i].equal(row.Values[i], t.Values[i]) {
+				equal = false
+				break
+			}
+		}
+		if equal {
+			return fmt.Errorf("duplicate key value violates primary key constraint on columns %v", r.table.primaryKey)
+		}
 	}
-	return id, nil
+	return nil
 }
@@ -0,0 +1,463 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+func TestInsertOnDuplicateKeyUpdate(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) *Engine {
+		t.Helper()
+
+		e := newTestEngine(t)
+		for _, query := range []string{
+			"CREATE TABLE `users` (`id` INT NOT NULL AUTO_INCREMENT, `email` VARCHAR(255) UNIQUE KEY, `name` TEXT NOT NULL, `visits` INT DEFAULT 1, `score` DECIMAL(10, 2) DEFAULT NULL, PRIMARY KEY (`id`)) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4",
+			"INSERT INTO `users` (`email`, `name`) VALUES ('a@example.com', 'alice'), ('b@example.com', 'bob')",
+		} {
+			if _, err := runWithMode(t, e, core.SQLSyntaxModeMySQL, query); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return e
+	}
+
+	tests := []struct {
+		name           string
+		query          string
+		lastInsertedID int64
+		rowsAffected   int64
+		want           [][]string
+		wantErr        bool
+	}{
+		{
+			name:           "insert a new row",
+			query:          "INSERT INTO users (email, name) VALUES ('c@example.com', 'carol') ON DUPLICATE KEY UPDATE visits = 10",
+			lastInsertedID: 3,
+			rowsAffected:   1,
+			want: [][]string{
+				{"1", "a@example.com", "alice", "1"},
+				{"2", "b@example.com", "bob", "1"},
+				{"3", "c@example.com", "carol", "1"},
+			},
+		},
+		{
+			name:           "update the row with the same unique value",
+			query:          "INSERT INTO users (email, name) VALUES ('b@example.com', 'robert') ON DUPLICATE KEY UPDATE name = VALUES(name), visits = 2",
+			lastInsertedID: 2,
+			rowsAffected:   2,
+			want: [][]string{
+				{"1", "a@example.com", "alice", "1"},
+				{"2", "b@example.com", "robert", "2"},
+			},
+		},
+		{
+			name:           "update the row with the same primary key",
+			query:          "INSERT INTO users (id, name) VALUES (1, 'alicia'), (4, 'dave') ON DUPLICATE KEY UPDATE `name` = VALUES(`name`)",
+			lastInsertedID: 4,
+			rowsAffected:   3,
+			want: [][]string{
+				{"1", "a@example.com", "alicia", "1"},
+				{"2", "b@example.com", "bob", "1"},
+				{"4", "NULL", "dave", "1"},
+			},
+		},
+		{
+			name:    "the update violates a unique constraint",
+			query:   "INSERT INTO users (id, name) VALUES (1, 'alice') ON DUPLICATE KEY UPDATE email = 'b@example.com'",
+			wantErr: true,
+		},
+		{
+			name:    "unknown attribute in VALUES()",
+			query:   "INSERT INTO users (id, name) VALUES (1, 'alice') ON DUPLICATE KEY UPDATE name = VALUES(nickname)",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := setup(t)
+			got, err := runWithMode(t, e, core.SQLSyntaxModeMySQL, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			rows, err := run(t, e, "SELECT id, email, name, visits FROM users ORDER BY id")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr {
+				tt.want = [][]string{
+					{"1", "a@example.com", "alice", "1"},
+					{"2", "b@example.com", "bob", "1"},
+				}
+			} else if got.lastInsertedID != tt.lastInsertedID || got.rowsAffected != tt.rowsAffected {
+				t.Errorf("mismatch result: want=(%d, %d), got=(%d, %d)", tt.lastInsertedID, tt.rowsAffected, got.lastInsertedID, got.rowsAffected)
+			}
+			if diff := cmp.Diff(tt.want, rows.rows); diff != "" {
+				t.Errorf("rows are mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInsertOrReplaceAndIgnore(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) *Engine {
+		t.Helper()
+
+		e := newTestEngine(t)
+		for _, query := range []string{
+			"CREATE TABLE users (id INTEGER PRIMARY KEY, email TEXT UNIQUE COLLATE NOCASE, name VARCHAR(20) NOT NULL)",
+			"INSERT INTO users (email, name) VALUES ('a@example.com', 'alice'), ('b@example.com', 'bob')",
+		} {
+			if _, err := runWithMode(t, e, core.SQLSyntaxModeSQLite, query); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return e
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		rowsAffected int64
+		want         [][]string
+		wantErr      bool
+	}{
+		{
+			name:         "INSERT OR REPLACE without conflict",
+			query:        "INSERT OR REPLACE INTO users (email, name) VALUES ('c@example.com', 'carol')",
+			rowsAffected: 1,
+			want:         [][]string{{"1", "a@example.com", "alice"}, {"2", "b@example.com", "bob"}, {"3", "c@example.com", "carol"}},
+		},
+		{
+			name:         "INSERT OR REPLACE the row with the same unique value ignoring case",
+			query:        "INSERT OR REPLACE INTO users (email, name) VALUES ('B@EXAMPLE.COM', 'robert')",
+			rowsAffected: 1,
+			want:         [][]string{{"1", "a@example.com", "alice"}, {"3", "B@EXAMPLE.COM", "robert"}},
+		},
+		{
+			name:         "REPLACE INTO the rows with the same primary key and unique value",
+			query:        "REPLACE INTO users (id, email, name) VALUES (1, 'b@example.com', 'alicia')",
+			rowsAffected: 1,
+			want:         [][]string{{"1", "b@example.com", "alicia"}},
+		},
+		{
+			name:         "INSERT OR IGNORE skips the conflicting rows",
+			query:        "INSERT OR IGNORE INTO users (id, email, name) VALUES (1, 'x@example.com', 'xavier'), (5, 'A@example.com', 'anna'), (6, 'f@example.com', 'frank')",
+			rowsAffected: 1,
+			want:         [][]string{{"1", "a@example.com", "alice"}, {"2", "b@example.com", "bob"}, {"6", "f@example.com", "frank"}},
+		},
+		{
+			name:         "INSERT OR IGNORE skips the rows violating NOT NULL",
+			query:        "INSERT OR IGNORE INTO users (email, name) VALUES ('c@example.com', NULL)",
+			rowsAffected: 0,
+			want:         [][]string{{"1", "a@example.com", "alice"}, {"2", "b@example.com", "bob"}},
+		},
+		{
+			name:    "INSERT OR REPLACE still checks NOT NULL",
+			query:   "INSERT OR REPLACE INTO users (id, email, name) VALUES (1, 'a@example.com', NULL)",
+			wantErr: true,
+		},
+		{
+			name:    "INSERT fails on a unique value differing in case",
+			query:   "INSERT INTO users (email, name) VALUES ('A@EXAMPLE.COM', 'alice')",
+			wantErr: true,
+		},
+		{
+			name:    "unbound parameter",
+			query:   "INSERT INTO users (email, name) VALUES (?1, :name)",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := setup(t)
+			got, err := runWithMode(t, e, core.SQLSyntaxModeSQLite, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			rows, err := run(t, e, "SELECT id, email, name FROM users ORDER BY id")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr {
+				tt.want = [][]string{{"1", "a@example.com", "alice"}, {"2", "b@example.com", "bob"}}
+			} else if got.rowsAffected != tt.rowsAffected {
+				t.Errorf("mismatch rows affected: want=%d, got=%d", tt.rowsAffected, got.rowsAffected)
+			}
+			if diff := cmp.Diff(tt.want, rows.rows); diff != "" {
+				t.Errorf("rows are mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInsertNumericLiterals(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		query   string
+		where   string
+		want    [][]string
+		wantErr bool
+	}{
+		{
+			name:  "integers",
+			query: "INSERT INTO numbers (i, n, s) VALUES (-7, 42, 0x1F), (0x1F, 0b101, 1_000)",
+			want:  [][]string{{"-7", "42", "31"}, {"31", "5", "1000"}},
+		},
+		{
+			name:  "decimals and floats",
+			query: "INSERT INTO numbers (i, n, s) VALUES (1, 1.5e-3, 1.50), (2, -2.5, 1.5E3)",
+			want:  [][]string{{"1", "0.0015", "1.5"}, {"2", "-2.5", "1500"}},
+		},
+		{
+			name:  "decimals compared with another scale",
+			query: "INSERT INTO numbers (i, n, s) VALUES (1, 1.50, 2.0), (2, 2.5, 2.50)",
+			where: "n = 1.50",
+			want:  [][]string{{"1", "1.5", "2"}},
+		},
+		{
+			name:  "decimals in a list",
+			query: "INSERT INTO numbers (i, n, s) VALUES (1, 1.50, 2.0), (2, 2.5, 2.50)",
+			where: "s IN (2.00, 3.0)",
+			want:  [][]string{{"1", "1.5", "2"}},
+		},
+		{
+			name:    "decimal into an integer attribute",
+			query:   "INSERT INTO numbers (i) VALUES (1.5)",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := newTestEngine(t)
+			if _, err := run(t, e, "CREATE TABLE numbers (i INTEGER, n NUMERIC, s TEXT)"); err != nil {
+				t.Fatal(err)
+			}
+			_, err := run(t, e, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			where := tt.where
+			if where == "" {
+				where = "i IN (-7, 0x1F, 1, 2)"
+			}
+			rows, err := run(t, e, "SELECT i, n, s FROM numbers WHERE "+where)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, rows.rows); diff != "" {
+				t.Errorf("rows are mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInsertQuotedNumbers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		query   string
+		want    [][]string
+		wantErr string
+	}{
+		{
+			name:  "quoted numbers",
+			query: "INSERT INTO numbers (i, b, n) VALUES (' 42 ', '0x1F', '+1.5')",
+			want:  [][]string{{"42", "31", "1.5"}},
+		},
+		{
+			name:    "text into an integer attribute",
+			query:   "INSERT INTO numbers (i) VALUES ('abc')",
+			wantErr: `invalid input syntax for type integer: "abc"`,
+		},
+		{
+			name:    "quoted decimal into an integer attribute",
+			query:   "INSERT INTO numbers (i) VALUES ('1.5')",
+			wantErr: `invalid input syntax for type integer: "1.5"`,
+		},
+		{
+			name:    "text into a numeric attribute",
+			query:   "INSERT INTO numbers (n) VALUES ('1.5.2')",
+			wantErr: `invalid input syntax for type numeric: "1.5.2"`,
+		},
+		{
+			name:    "quoted integer out of range",
+			query:   "INSERT INTO numbers (b) VALUES ('9223372036854775808')",
+			wantErr: `value "9223372036854775808" is out of range for type bigint`,
+		},
+		{
+			name:    "integer out of range",
+			query:   "INSERT INTO numbers (i) VALUES (9223372036854775808)",
+			wantErr: `value "9223372036854775808" is out of range for type integer`,
+		},
+		{
+			name:    "quoted float out of range",
+			query:   "INSERT INTO numbers (n) VALUES ('1e400')",
+			wantErr: `value "1e400" is out of range for type numeric`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := newTestEngine(t)
+			if _, err := run(t, e, "CREATE TABLE numbers (i INTEGER, b BIGINT, n NUMERIC)"); err != nil {
+				t.Fatal(err)
+			}
+			_, err := run(t, e, tt.query)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("mismatch error: want=%s, got=%v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			rows, err := run(t, e, "SELECT i, b, n FROM numbers")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, rows.rows); diff != "" {
+				t.Errorf("rows are mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInsertIntegerTypeWithSize(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		query   string
+		want    [][]string
+		wantErr bool
+	}{
+		{
+			name:  "integers",
+			query: "INSERT INTO t (id, big) VALUES (1, 20)",
+			want:  [][]string{{"1", "20"}},
+		},
+		{
+			name:    "decimal into INT(11)",
+			query:   "INSERT INTO t (id, big) VALUES (1.5, 20)",
+			wantErr: true,
+		},
+		{
+			name:    "decimal into BIGINT(20)",
+			query:   "INSERT INTO t (id, big) VALUES (1, 1.5)",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := newTestEngine(t)
+			if _, err := runWithMode(t, e, core.SQLSyntaxModeMySQL, "CREATE TABLE t (id INT(11), big BIGINT(20))"); err != nil {
+				t.Fatal(err)
+			}
+			_, err := runWithMode(t, e, core.SQLSyntaxModeMySQL, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			rows, err := runWithMode(t, e, core.SQLSyntaxModeMySQL, "SELECT id, big FROM t")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, rows.rows); diff != "" {
+				t.Errorf("rows are mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestInsertTypeAffinity(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		query string
+		want  [][]string
+	}{
+		{
+			name:  "texts which are not numbers are stored as text",
+			query: "INSERT INTO t (id, created, flag, price, note) VALUES (1, '2024-01-01 00:00:00', 'yes', 'free', 'x')",
+			want:  [][]string{{"1", "2024-01-01 00:00:00", "yes", "free", "x"}},
+		},
+		{
+			name:  "texts which are numbers are stored as numbers",
+			query: "INSERT INTO t (id, created, flag, price, note) VALUES ('1', ' 10 ', '1', '2.50', 3.50)",
+			want:  [][]string{{"1", "10", "1", "2.5", "3.5"}},
+		},
+		{
+			name:  "reals without fractional part are stored as integers",
+			query: "INSERT INTO t (id, created, flag, price, note) VALUES (1.0, 2e3, 1, 3.0, 'x')",
+			want:  [][]string{{"1", "2000", "1", "3", "x"}},
+		},
+		{
+			name:  "text in an INTEGER column",
+			query: "INSERT INTO t (id, created, flag, price, note) VALUES ('abc', 1, 0, 1.5, 'x')",
+			want:  [][]string{{"abc", "1", "0", "1.5", "x"}},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := newTestEngine(t)
+			if _, err := runWithMode(t, e, core.SQLSyntaxModeSQLite, "CREATE TABLE t (id INT, created DATETIME, flag BOOLEAN, price DECIMAL(10, 2), note TEXT)"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := runWithMode(t, e, core.SQLSyntaxModeSQLite, tt.query); err != nil {
+				t.Fatal(err)
+			}
+
+			rows, err := run(t, e, "SELECT id, created, flag, price, note FROM t")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, rows.rows); diff != "" {
+				t.Errorf("rows are mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -24,6 +24,20 @@ func (v virtualRow) String() string {
 	return l1 + l2
 }
 
+// addTuple adds the values of a tuple of the relation to the virtual row.
+func (v virtualRow) addTuple(r *Relation, t *Tuple) {
+	for index := range t.Values {
+		val := Value{
+			v:      t.Values[index],
+			valid:  true,
+			lexeme: r.table.attributes[index].name,
+			table:  r.table.name,
+			nocase: r.table.attributes[index].nocase,
+		}
+		v[val.table+"."+val.lexeme] = val
+	}
+}
+
 // joiner is 4 types of predicates 'INNER', 'LEFT', 'RIGHT', 'FULL' with NATURAL option
 type joiner interface {
 	// Evaluate returns true if the virtualRow matches the joiner
@@ -84,8 +98,8 @@ func (i *inner) Evaluate(row virtualRow, r *Relation, index int) (bool, error) {
 		return false, fmt.Errorf("joining on table %s, attribute %s not found", i.t2Value.table, i.t2Value.lexeme)
 	}
 
-	// let's say for now the only operator is '='
-	if fmt.Sprintf("%v", t1.v) == fmt.Sprintf("%v", t2.v) {
+	// let's say for now the only operator is '='. Like SQL, NULL does not join.
+	if t1.v != nil && t2.v != nil && fmt.Sprintf("%v", t1.v) == fmt.Sprintf("%v", t2.v) {
 		return true, nil
 	}
 	return false, nil
@@ -99,7 +113,7 @@ func (i *inner) Evaluate(row virtualRow, r *Relation, index int) (bool, error) {
 func generateVirtualRows(e *Engine, attr []Attribute, conn protocol.EngineConn, t1Name string, joinPredicates []joiner, selectPredicates []PredicateLinker, functors []selectFunctor) error {
 
 	// get t1 and lock it
-	t1 := e.relation(t1Name)
+	t1 := e.readRelation(t1Name)
 	if t1 == nil {
 		return fmt.Errorf("table %s not found", t1Name)
 	}
@@ -109,7 +123,7 @@ func generateVirtualRows(e *Engine, attr []Attribute, conn protocol.EngineConn,
 	// all joined tables in a map of relation
 	relations := make(map[string]*Relation)
 	for _, j := range joinPredicates {
-		r := e.relation(j.On())
+		r := e.readRelation(j.On())
 		if r == nil {
 			return fmt.Errorf("table %s not found", j.On())
 		}
@@ -122,7 +136,7 @@ func generateVirtualRows(e *Engine, attr []Attribute, conn protocol.EngineConn,
 	header := make([]string, 0, len(attr))
 	alias := make([]string, 0, len(attr))
 	for _, a := range attr {
-		alias = append(alias, a.name)
+		alias = append(alias, columnAlias(a.name))
 		if !strings.Contains(a.name, ".") {
 			a.name = t1Name + "." + a.name
 		}
@@ -140,15 +154,7 @@ func generateVirtualRows(e *Engine, attr []Attribute, conn protocol.EngineConn,
 	for i := range t1.rows {
 		// create virtualrow
 		row := make(virtualRow)
-		for index := range t1.rows[i].Values {
-			v := Value{
-				v:      t1.rows[i].Values[index],
-				valid:  true,
-				lexeme: t1.table.attributes[index].name,
-				table:  t1Name,
-			}
-			row[v.table+"."+v.lexeme] = v
-		}
+		row.addTuple(t1, t1.rows[i])
 
 		// for first join predicates
 		err := join(row, relations, joinPredicates, 0, selectPredicates, functors)
@@ -166,6 +172,13 @@ func generateVirtualRows(e *Engine, attr []Attribute, conn protocol.EngineConn,
 	return nil
 }
 
+// columnAlias returns the name of the column of a selected attribute. Like PostgreSQL,
+// it is the attribute name without its table name. Table names have no period, so
+// the alias of computed columns may have one (e.g. 1.5).
+func columnAlias(name string) string {
+	return name[strings.Index(name, ".")+1:]
+}
+
 // join recursive virtual row creation
 func join(row virtualRow, relations map[string]*Relation, predicates []joiner, predicateIndex int, selectPredicates []PredicateLinker, functors []selectFunctor) error {
 	// Skip directly to selectRows if there is no joiner to run
@@ -195,15 +208,7 @@ func join(row virtualRow, relations map[string]*Relation, predicates []joiner, p
 		}
 
 		// combine columns to existing virtual row
-		for index := range r.rows[i].Values {
-			v := Value{
-				v:      r.rows[i].Values[index],
-				valid:  true,
-				lexeme: r.table.attributes[index].name,
-				table:  r.table.name,
-			}
-			row[v.table+"."+v.lexeme] = v
-		}
+		row.addTuple(r, r.rows[i])
 
 		// if last predicate
 		if last {
@@ -218,34 +223,28 @@ func join(row virtualRow, relations map[string]*Relation, predicates []joiner, p
 	return nil
 }
 
-// joinExecutor
-func joinExecutor(decl *core.Decl) (joiner, error) {
-	decl.String(0)
-	j := &inner{}
-
-	// Table name
-	if decl.DeclList[0].TokenID != core.TokenIDString {
-		return nil, fmt.Errorf("join: expected table name, got %v", decl.DeclList[0])
+// joinExecutor returns the joiner of a joined table.
+func joinExecutor(j *core.Join) (joiner, error) {
+	on, ok := j.On.(*core.BinaryExpr)
+	if !ok || on.Op != core.OpEqual {
+		return nil, fmt.Errorf("join: only equality is supported in ON clause of %s", j.Table.Name)
 	}
-	j.table = decl.DeclList[0].Lexeme.String()
-
-	// Predicate should be ON
-	on := decl.DeclList[1]
-	if on.TokenID != core.TokenIDOn {
-		return nil, fmt.Errorf("join: expected ON, got %v", on)
+	t1, err := joinValue(on.Left)
+	if err != nil {
+		return nil, err
 	}
+	t2, err := joinValue(on.Right)
+	if err != nil {
+		return nil, err
+	}
+	return &inner{table: j.Table.Name, t1Value: t1, t2Value: t2}, nil
+}
 
-	// Set first value
-	j.t1Value.valid = true
-	j.t1Value.lexeme = on.DeclList[0].Lexeme.String()
-	j.t1Value.table = on.DeclList[0].DeclList[0].Lexeme.String()
-
-	// TODO: Skip operator here, expect '='
-
-	// Set second value
-	j.t2Value.valid = true
-	j.t2Value.lexeme = on.DeclList[2].Lexeme.String()
-	j.t2Value.table = on.DeclList[2].DeclList[0].Lexeme.String()
-
-	return j, nil
+// joinValue returns the value of an attribute of an ON clause, which must be qualified.
+func joinValue(expr core.Expr) (Value, error) {
+	column, ok := expr.(*core.ColumnRef)
+	if !ok || column.Table == "" {
+		return Value{}, fmt.Errorf("join: expected table.attribute in ON clause, got %T", expr)
+	}
+	return Value{valid: true, lexeme: column.Name, table: column.Table}, nil
 }
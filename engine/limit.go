@@ -47,7 +47,7 @@ func (l *limit) WriteRowHeader(header []string) error {
 }
 
 // WriteRow writes a row to the underlying connection.
-func (l *limit) WriteRow(row []string) error {
+func (l *limit) WriteRow(row []*string) error {
 	if l.current == l.limit {
 		// We are done here
 		return nil
@@ -103,7 +103,7 @@ func (l *offset) WriteRowHeader(header []string) error {
 }
 
 // WriteRow writes a row to the underlying connection.
-func (l *offset) WriteRow(row []string) error {
+func (l *offset) WriteRow(row []*string) error {
 	if l.current < l.offset {
 		// skip this line
 		l.current++
@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"strings"
+	"sync"
+
+	"github.com/nao1215/aiondb/engine/protocol"
+)
+
+// dsnScheme is the optional scheme of an AION DB DSN (e.g. aiondb://testdb).
+const dsnScheme = "aiondb://"
+
+// LocalEndpoint implements protocol.DriverEndpoint for engines running in the
+// same process. Each DSN names an Engine: the first connection to a DSN starts
+// it, the following ones reuse it, and the Engine is stopped (and its data
+// dropped) when the last connection is closed. No socket is involved, the
+// driver and the engine talk through channels.
+type LocalEndpoint struct {
+	// engines is the map of running engines, keyed by database name.
+	engines map[string]*localEngine
+	// mu is the mutex used to protect the engines map.
+	sync.Mutex
+}
+
+// localEngine is an Engine started by a LocalEndpoint.
+type localEngine struct {
+	// engine is the running engine.
+	engine *Engine
+	// endpoint creates connections to the engine.
+	endpoint protocol.DriverEndpoint
+	// conns is the number of open connections.
+	conns int
+}
+
+// NewLocalEndpoint returns a new LocalEndpoint without any running engine.
+func NewLocalEndpoint() *LocalEndpoint {
+	return &LocalEndpoint{
+		engines: make(map[string]*localEngine),
+	}
+}
+
+// New returns a new connection to the engine named by the DSN.
+// The engine is started if it is not running yet.
+func (l *LocalEndpoint) New(dsn string) (protocol.DriverConn, error) {
+	name := databaseName(dsn)
+
+	l.Lock()
+	defer l.Unlock()
+
+	le, ok := l.engines[name]
+	if !ok {
+		driverEndpoint, engineEndpoint := protocol.NewChannelEndpoints()
+		e, err := New(engineEndpoint)
+		if err != nil {
+			return nil, err
+		}
+		le = &localEngine{
+			engine:   e,
+			endpoint: driverEndpoint,
+		}
+		l.engines[name] = le
+	}
+
+	conn, err := le.endpoint.New(dsn)
+	if err != nil {
+		return nil, err
+	}
+	le.conns++
+
+	return &localConn{
+		DriverConn: conn,
+		release: func() {
+			l.release(name, le)
+		},
+	}, nil
+}
+
+// release is called when a connection is closed.
+// The engine is stopped when its last connection is closed.
+func (l *LocalEndpoint) release(name string, le *localEngine) {
+	l.Lock()
+	defer l.Unlock()
+
+	le.conns--
+	if le.conns > 0 {
+		return
+	}
+	if l.engines[name] == le {
+		delete(l.engines, name)
+	}
+	le.engine.Stop()
+}
+
+// localConn is a connection created by a LocalEndpoint.
+type localConn struct {
+	protocol.DriverConn
+	// release is called once when the connection is closed.
+	release func()
+	// once ensures release is called only once.
+	once sync.Once
+}
+
+// Close closes the connection and releases the engine.
+func (c *localConn) Close() {
+	c.once.Do(func() {
+		c.DriverConn.Close()
+		c.release()
+	})
+}
+
+// databaseName returns the database name of a DSN.
+// Both "testdb" and "aiondb://testdb?option=value" name the database "testdb".
+func databaseName(dsn string) string {
+	name := strings.TrimPrefix(dsn, dsnScheme)
+	if i := strings.IndexByte(name, '?'); i >= 0 {
+		name = name[:i]
+	}
+	return name
+}
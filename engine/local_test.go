@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"testing"
+)
+
+func TestLocalEndpoint(t *testing.T) {
+	t.Parallel()
+
+	l := NewLocalEndpoint()
+
+	conn1, err := l.New("aiondb://testdb?dialect=postgres")
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn2, err := l.New("testdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.engines) != 1 {
+		t.Fatalf("mismatch number of engines: want=1, got=%d", len(l.engines))
+	}
+
+	if err := conn2.WriteExec("GRANT ALL"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := conn2.ReadResult(); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := conn1.WriteExec("unknown statement"); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := conn1.ReadResult(); err == nil {
+		t.Error("expect error, however ReadResult() returned nil")
+	}
+
+	conn1.Close()
+	conn1.Close()
+	if len(l.engines) != 1 {
+		t.Fatalf("engine is stopped while a connection is open")
+	}
+	conn2.Close()
+	if len(l.engines) != 0 {
+		t.Fatalf("engine is not stopped after the last connection is closed")
+	}
+}
+
+func TestDatabaseName(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		dsn  string
+		want string
+	}{
+		{dsn: "testdb", want: "testdb"},
+		{dsn: "aiondb://testdb", want: "testdb"},
+		{dsn: "aiondb://testdb?dialect=mysql", want: "testdb"},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.dsn, func(t *testing.T) {
+			t.Parallel()
+
+			if got := databaseName(tt.dsn); got != tt.want {
+				t.Errorf("want=%s, got=%s", tt.want, got)
+			}
+		})
+	}
+}
@@ -3,6 +3,7 @@ package engine
 import (
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/nao1215/aiondb/engine/parser/core"
@@ -11,23 +12,23 @@ import (
 // Operator compares 2 values and return a boolean
 type Operator func(leftValue Value, rightValue Value) bool
 
-// NewOperator initializes the operator matching the Token number
-func NewOperator(token core.TokenID, lexeme string) (Operator, error) {
-	switch token {
-	case core.TokenIDEquality:
+// NewOperator initializes the operator of a comparison
+func NewOperator(op core.BinaryOp) (Operator, error) {
+	switch op {
+	case core.OpEqual:
 		return equalityOperator, nil
-	case core.TokenIDDistinctness:
+	case core.OpNotEqual:
 		return distinctnessOperator, nil
-	case core.TokenIDLeftDiple:
+	case core.OpLess:
 		return lessThanOperator, nil
-	case core.TokenIDRightDiple:
+	case core.OpGreater:
 		return greaterThanOperator, nil
-	case core.TokenIDLessOrEqual:
+	case core.OpLessOrEqual:
 		return lessOrEqualOperator, nil
-	case core.TokenIDGreaterOrEqual:
+	case core.OpGreaterOrEqual:
 		return greaterOrEqualOperator, nil
 	}
-	return nil, fmt.Errorf("operator '%s' does not exist", lexeme)
+	return nil, fmt.Errorf("operator '%s' does not exist", op)
 }
 
 // toDate converts a value to a date
@@ -145,14 +146,28 @@ func lessThanOperator(leftValue Value, rightValue Value) bool {
 	return leftDate.Before(rightDate)
 }
 
-// equalityOperator checks if given value are equal
+// equalText checks if given texts are equal, case-insensitively if nocase is true
+func equalText(left string, right string, nocase bool) bool {
+	if nocase {
+		return strings.EqualFold(left, right)
+	}
+	return left == right
+}
+
+// equalityOperator checks if given value are equal. Like SQL, NULL is not equal to any value.
 func equalityOperator(leftValue Value, rightValue Value) bool {
-	return fmt.Sprintf("%v", leftValue.v) == rightValue.lexeme
+	if leftValue.v == nil {
+		return false
+	}
+	return equalText(fmt.Sprintf("%v", leftValue.v), rightValue.lexeme, leftValue.nocase)
 }
 
-// distinctnessOperator checks if given value are distinct
+// distinctnessOperator checks if given value are distinct. Like SQL, NULL is not distinct from any value.
 func distinctnessOperator(leftValue Value, rightValue Value) bool {
-	return fmt.Sprintf("%v", leftValue.v) != rightValue.lexeme
+	if leftValue.v == nil {
+		return false
+	}
+	return !equalText(fmt.Sprintf("%v", leftValue.v), rightValue.lexeme, leftValue.nocase)
 }
 
 // TrueOperator always returns true
@@ -164,20 +179,20 @@ func trueOperator(_ Value, _ Value) bool {
 // Right value should be a slice of string
 func inOperator(leftValue Value, rightValue Value) bool {
 	values, ok := rightValue.v.([]string)
-	if !ok {
+	if !ok || leftValue.v == nil {
 		return false
 	}
 	for i := range values {
-		if fmt.Sprintf("%v", leftValue.v) == values[i] {
+		if equalText(fmt.Sprintf("%v", leftValue.v), values[i], leftValue.nocase) {
 			return true
 		}
 	}
 	return false
 }
 
-// notInOperator checks if the left value is not in the right value
+// notInOperator checks if the left value is not in the right value. NULL is never selected.
 func notInOperator(leftValue Value, rightValue Value) bool {
-	return !inOperator(leftValue, rightValue)
+	return leftValue.v != nil && !inOperator(leftValue, rightValue)
 }
 
 // isNullOperator checks if the left value is null
@@ -0,0 +1,157 @@
+package engine
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/nao1215/aiondb/engine/parser/core"
+	"github.com/nao1215/aiondb/engine/protocol"
+)
+
+// orderKey is an attribute rows are sorted by.
+type orderKey struct {
+	// attribute is the qualified attribute name.
+	attribute string
+	// desc is true if rows are sorted in descending order.
+	desc bool
+}
+
+// orderedRow is a selected row and the values it is sorted by.
+type orderedRow struct {
+	// values is the row to write, NULL values are nil.
+	values []*string
+	// keys is the values of the order keys.
+	keys []interface{}
+}
+
+// orderbyFunction buffers the selected rows and writes them sorted.
+type orderbyFunction struct {
+	// conn is the connection rows are written to.
+	conn protocol.EngineConn
+	// attributes is the list of qualified attributes to write.
+	attributes []string
+	// keys is the list of attributes rows are sorted by.
+	keys []orderKey
+	// rows is the list of buffered rows.
+	rows []orderedRow
+}
+
+// orderbyExecutor returns a functor sorting rows according to the ORDER BY expressions.
+func orderbyExecutor(e *Engine, items []*core.OrderItem, tables []string) (*orderbyFunction, error) {
+	f := &orderbyFunction{}
+
+	for _, item := range items {
+		column, ok := item.Expr.(*core.ColumnRef)
+		if !ok {
+			return nil, fmt.Errorf("cannot order by %T", item.Expr)
+		}
+		name, err := qualifyAttribute(e, column, tables)
+		if err != nil {
+			return nil, err
+		}
+		f.keys = append(f.keys, orderKey{attribute: name, desc: item.Desc})
+	}
+	return f, nil
+}
+
+// Init writes the row header.
+func (f *orderbyFunction) Init(_ *Engine, conn protocol.EngineConn, attr []string, alias []string) error {
+	f.conn = conn
+	f.attributes = attr
+	return f.conn.WriteRowHeader(alias)
+}
+
+// FeedVirtualRow buffers the row.
+func (f *orderbyFunction) FeedVirtualRow(row virtualRow) error {
+	r := orderedRow{
+		values: make([]*string, 0, len(f.attributes)),
+		keys:   make([]interface{}, 0, len(f.keys)),
+	}
+	for _, attr := range f.attributes {
+		val, ok := row[attr]
+		if !ok {
+			return fmt.Errorf("could not select attribute %s", attr)
+		}
+		r.values = append(r.values, rowValue(val.v))
+	}
+	for _, key := range f.keys {
+		val, ok := row[key.attribute]
+		if !ok {
+			return fmt.Errorf("could not order by attribute %s", key.attribute)
+		}
+		// Values compared case-insensitively are sorted case-insensitively as well
+		if s, ok := val.v.(string); ok && val.nocase {
+			r.keys = append(r.keys, strings.ToLower(s))
+			continue
+		}
+		r.keys = append(r.keys, val.v)
+	}
+	f.rows = append(f.rows, r)
+	return nil
+}
+
+// Done sorts and writes the buffered rows, then writes the end of the rows.
+func (f *orderbyFunction) Done() error {
+	sort.SliceStable(f.rows, func(i, j int) bool {
+		for k, key := range f.keys {
+			c := compareValues(f.rows[i].keys[k], f.rows[j].keys[k])
+			if c == 0 {
+				continue
+			}
+			if key.desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+
+	for _, r := range f.rows {
+		if err := f.conn.WriteRow(r.values); err != nil {
+			return err
+		}
+	}
+	return f.conn.WriteRowEnd()
+}
+
+// compareValues returns -1, 0 or +1 whether a is less than, equal to or greater than b.
+// Numbers and dates are compared by value, other values by their string representation.
+// Like PostgreSQL, NULL is greater than any other value.
+func compareValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+
+	if fa, err := toFloat(a); err == nil {
+		if fb, err := toFloat(b); err == nil {
+			switch {
+			case fa < fb:
+				return -1
+			case fa > fb:
+				return 1
+			default:
+				return 0
+			}
+		}
+	}
+
+	if da, err := toDate(fmt.Sprintf("%v", a)); err == nil {
+		if db, err := toDate(fmt.Sprintf("%v", b)); err == nil {
+			switch {
+			case da.Before(db):
+				return -1
+			case da.After(db):
+				return 1
+			default:
+				return 0
+			}
+		}
+	}
+	return strings.Compare(fmt.Sprintf("%v", a), fmt.Sprintf("%v", b))
+}
@@ -0,0 +1,370 @@
+package core
+
+// The typed AST describes a statement with named fields instead of the
+// positions of the declarations in a Decl tree. It is built from the Decl tree
+// of any SQL syntax mode by NewAST, so that executors and tools (e.g. a
+// formatter) can rely on a stable, documented structure.
+
+// Stmt is a statement of the typed AST.
+type Stmt interface {
+	// stmtNode marks the types of the package which are statements.
+	stmtNode()
+}
+
+// Expr is an expression of the typed AST.
+type Expr interface {
+	// exprNode marks the types of the package which are expressions.
+	exprNode()
+}
+
+// TableRef is a reference to a table, e.g. users or public.users.
+type TableRef struct {
+	// Schema is the schema of the table, empty if the table is not qualified.
+	Schema string
+	// Name is the name of the table.
+	Name string
+}
+
+// SelectStmt is a SELECT statement.
+type SelectStmt struct {
+	// Distinct is true for SELECT DISTINCT.
+	Distinct bool
+	// DistinctOn is the list of expressions of SELECT DISTINCT ON (...).
+	DistinctOn []Expr
+	// Columns is the select list: ColumnRef, Star, FuncCall, Literal or SequenceValue.
+	Columns []Expr
+	// From is the list of tables of the FROM clause.
+	From []*TableRef
+	// Joins is the list of joined tables.
+	Joins []*Join
+	// Where is the condition of the WHERE clause, nil if all rows are selected.
+	Where Expr
+	// OrderBy is the list of the ORDER BY expressions.
+	OrderBy []*OrderItem
+	// Limit is the maximum number of rows, nil if there is no limit.
+	Limit *int64
+	// Offset is the number of rows to skip, nil if there is no offset.
+	Offset *int64
+	// ForUpdate is true for SELECT ... FOR UPDATE.
+	ForUpdate bool
+}
+
+// Join is a table joined by a SELECT statement.
+type Join struct {
+	// Table is the joined table.
+	Table *TableRef
+	// On is the join condition.
+	On Expr
+}
+
+// OrderItem is an expression of an ORDER BY clause.
+type OrderItem struct {
+	// Expr is the sorted expression.
+	Expr Expr
+	// Desc is true if the rows are sorted in descending order.
+	Desc bool
+}
+
+// ConflictAction is the action of INSERT OR REPLACE and INSERT OR IGNORE.
+type ConflictAction int
+
+const (
+	// ConflictAbort fails the INSERT statement on conflicts, it is the default action.
+	ConflictAbort ConflictAction = iota
+	// ConflictReplace replaces the conflicting rows (INSERT OR REPLACE, REPLACE INTO).
+	ConflictReplace
+	// ConflictIgnore skips the conflicting rows (INSERT OR IGNORE).
+	ConflictIgnore
+)
+
+// InsertStmt is an INSERT statement.
+type InsertStmt struct {
+	// Table is the table rows are inserted into.
+	Table *TableRef
+	// Columns is the list of the inserted columns.
+	Columns []string
+	// Rows is the list of inserted rows, each one has a value per column.
+	Rows [][]Expr
+	// Or is the action on conflicts.
+	Or ConflictAction
+	// OnDuplicateKeyUpdate is the list of assignments of ON DUPLICATE KEY UPDATE.
+	OnDuplicateKeyUpdate []*Assignment
+	// Returning is the list of expressions of the RETURNING clause.
+	Returning []Expr
+}
+
+// Assignment is the assignment of a value to a column, e.g. name = 'alice'.
+type Assignment struct {
+	// Column is the assigned column.
+	Column string
+	// Value is the assigned value.
+	Value Expr
+}
+
+// UpdateStmt is an UPDATE statement.
+type UpdateStmt struct {
+	// Table is the updated table.
+	Table *TableRef
+	// Set is the list of assignments of the SET clause.
+	Set []*Assignment
+	// Where is the condition of the WHERE clause, nil if all rows are updated.
+	Where Expr
+}
+
+// DeleteStmt is a DELETE statement.
+type DeleteStmt struct {
+	// Table is the table rows are deleted from.
+	Table *TableRef
+	// Where is the condition of the WHERE clause, nil if all rows are deleted.
+	Where Expr
+}
+
+// TruncateStmt is a TRUNCATE statement.
+type TruncateStmt struct {
+	// Table is the truncated table.
+	Table *TableRef
+}
+
+// DropTableStmt is a DROP TABLE statement.
+type DropTableStmt struct {
+	// Table is the dropped table.
+	Table *TableRef
+}
+
+// DropSequenceStmt is a DROP SEQUENCE statement.
+type DropSequenceStmt struct {
+	// Name is the name of the dropped sequence.
+	Name string
+}
+
+// CreateTableStmt is a CREATE TABLE statement.
+type CreateTableStmt struct {
+	// Table is the created table.
+	Table *TableRef
+	// IfNotExists is true for CREATE TABLE IF NOT EXISTS.
+	IfNotExists bool
+	// Columns is the list of column definitions.
+	Columns []*ColumnDef
+	// PrimaryKey is the list of columns of the PRIMARY KEY (...) table constraint.
+	PrimaryKey []string
+}
+
+// ColumnDef is the definition of a column in a CREATE TABLE statement.
+type ColumnDef struct {
+	// Name is the name of the column.
+	Name string
+	// Type is the type of the column.
+	Type *TypeName
+	// NotNull is true if the column is NOT NULL.
+	NotNull bool
+	// PrimaryKey is true if the column is the PRIMARY KEY.
+	PrimaryKey bool
+	// Unique is true if the column is UNIQUE.
+	Unique bool
+	// AutoIncrement is true if the column is AUTO_INCREMENT or AUTOINCREMENT.
+	AutoIncrement bool
+	// Default is the DEFAULT value of the column, nil if there is none.
+	Default Expr
+	// Collate is the collation of the column (e.g. NOCASE), empty by default.
+	Collate string
+}
+
+// TypeName is the type of a column, e.g. VARCHAR(20) or TIMESTAMP WITH TIME ZONE.
+type TypeName struct {
+	// Name is the name of the type as written, e.g. varchar.
+	Name string
+	// Args is the list of the type arguments (e.g. the length and the scale).
+	Args []string
+	// WithTimeZone is true for the types WITH TIME ZONE.
+	WithTimeZone bool
+	// Affinity is the type affinity of the column in SQLite (INTEGER, TEXT, BLOB,
+	// REAL or NUMERIC), empty in the other SQL syntax modes.
+	Affinity string
+}
+
+// CreateIndexStmt is a CREATE INDEX statement.
+type CreateIndexStmt struct {
+	// Name is the name of the index.
+	Name string
+	// Table is the indexed table.
+	Table *TableRef
+	// IfNotExists is true for CREATE INDEX IF NOT EXISTS.
+	IfNotExists bool
+	// Unique is true for CREATE UNIQUE INDEX.
+	Unique bool
+	// Columns is the list of the indexed columns.
+	Columns []*IndexColumn
+}
+
+// IndexColumn is a column of a CREATE INDEX statement.
+type IndexColumn struct {
+	// Name is the name of the column.
+	Name string
+	// Collate is the collation of the column (e.g. NOCASE), empty by default.
+	Collate string
+}
+
+// CreateSequenceStmt is a CREATE SEQUENCE statement.
+type CreateSequenceStmt struct {
+	// Name is the name of the sequence.
+	Name string
+	// Start is the first value of the sequence, nil if it is not set.
+	Start *int64
+	// Increment is the increment of the sequence, nil if it is not set.
+	Increment *int64
+}
+
+// GrantStmt is a GRANT statement. Privileges are not checked, so it has no field.
+type GrantStmt struct{}
+
+// ColumnRef is a reference to a column, e.g. name or users.name.
+type ColumnRef struct {
+	// Table is the table of the column, empty if the column is not qualified.
+	Table string
+	// Name is the name of the column.
+	Name string
+}
+
+// Star is the * of a select list, or table.* for the columns of a table.
+type Star struct {
+	// Table is the table of the columns, empty for all the tables.
+	Table string
+}
+
+// LiteralKind is the kind of a literal value.
+type LiteralKind int
+
+const (
+	// LiteralString is a string, e.g. 'alice'.
+	LiteralString LiteralKind = iota
+	// LiteralNumber is a number, e.g. 42 or 1.5.
+	LiteralNumber
+	// LiteralDate is a date or timestamp string, e.g. '2023-01-02'.
+	LiteralDate
+	// LiteralNull is NULL.
+	LiteralNull
+	// LiteralTrue is TRUE.
+	LiteralTrue
+	// LiteralFalse is FALSE.
+	LiteralFalse
+	// LiteralNow is the current timestamp, e.g. NOW() or SYSDATE.
+	LiteralNow
+	// LiteralLocalTimestamp is LOCALTIMESTAMP.
+	LiteralLocalTimestamp
+	// LiteralDefault is the DEFAULT value of a column in INSERT and UPDATE statements.
+	LiteralDefault
+)
+
+// Literal is a literal value.
+type Literal struct {
+	// Kind is the kind of the value.
+	Kind LiteralKind
+	// Value is the value as written in the statement, without quotes.
+	Value string
+	// Number is the kind of a LiteralNumber (e.g. NumberKindDecimal for 1.5),
+	// NumberKindNone for the other kinds.
+	Number NumberKind
+}
+
+// Param is a parameter whose value is bound by the driver, e.g. ?1 or :name.
+type Param struct {
+	// Name is the parameter as written in the statement.
+	Name string
+}
+
+// FuncCall is a function call, e.g. COUNT(*) or NVL(name, 'none').
+type FuncCall struct {
+	// Name is the name of the function, in lower case.
+	Name string
+	// Args is the list of arguments.
+	Args []Expr
+}
+
+// SequenceValue is the NEXTVAL or CURRVAL pseudocolumn of a sequence, e.g. seq.NEXTVAL.
+type SequenceValue struct {
+	// Sequence is the name of the sequence.
+	Sequence string
+	// Next is true for NEXTVAL, false for CURRVAL.
+	Next bool
+}
+
+// BinaryOp is the operator of a binary expression.
+type BinaryOp string
+
+const (
+	// OpEqual is the = operator.
+	OpEqual BinaryOp = "="
+	// OpNotEqual is the <> operator.
+	OpNotEqual BinaryOp = "<>"
+	// OpLess is the < operator.
+	OpLess BinaryOp = "<"
+	// OpGreater is the > operator.
+	OpGreater BinaryOp = ">"
+	// OpLessOrEqual is the <= operator.
+	OpLessOrEqual BinaryOp = "<="
+	// OpGreaterOrEqual is the >= operator.
+	OpGreaterOrEqual BinaryOp = ">="
+	// OpAnd is the AND operator.
+	OpAnd BinaryOp = "AND"
+	// OpOr is the OR operator.
+	OpOr BinaryOp = "OR"
+)
+
+// BinaryExpr is a binary expression, e.g. id = 1 or a = 1 AND b = 2.
+type BinaryExpr struct {
+	// Op is the operator.
+	Op BinaryOp
+	// Left is the left operand.
+	Left Expr
+	// Right is the right operand.
+	Right Expr
+}
+
+// CollateExpr is an expression compared with a collation, e.g. 'alice' COLLATE NOCASE.
+type CollateExpr struct {
+	// Expr is the compared expression.
+	Expr Expr
+	// Collation is the name of the collation.
+	Collation string
+}
+
+// InExpr is an IN or NOT IN expression, e.g. id IN (1, 2).
+type InExpr struct {
+	// Expr is the tested expression.
+	Expr Expr
+	// Not is true for NOT IN.
+	Not bool
+	// List is the list of values.
+	List []Expr
+}
+
+// IsNullExpr is an IS NULL or IS NOT NULL expression.
+type IsNullExpr struct {
+	// Expr is the tested expression.
+	Expr Expr
+	// Not is true for IS NOT NULL.
+	Not bool
+}
+
+func (*SelectStmt) stmtNode()         {}
+func (*InsertStmt) stmtNode()         {}
+func (*UpdateStmt) stmtNode()         {}
+func (*DeleteStmt) stmtNode()         {}
+func (*TruncateStmt) stmtNode()       {}
+func (*DropTableStmt) stmtNode()      {}
+func (*DropSequenceStmt) stmtNode()   {}
+func (*CreateTableStmt) stmtNode()    {}
+func (*CreateIndexStmt) stmtNode()    {}
+func (*CreateSequenceStmt) stmtNode() {}
+func (*GrantStmt) stmtNode()          {}
+
+func (*ColumnRef) exprNode()     {}
+func (*Star) exprNode()          {}
+func (*Literal) exprNode()       {}
+func (*Param) exprNode()         {}
+func (*FuncCall) exprNode()      {}
+func (*SequenceValue) exprNode() {}
+func (*BinaryExpr) exprNode()    {}
+func (*CollateExpr) exprNode()   {}
+func (*InExpr) exprNode()        {}
+func (*IsNullExpr) exprNode()    {}
@@ -0,0 +1,748 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NewAST returns the typed AST of a statement parsed in any SQL syntax mode.
+// It returns an error wrapping ErrInvalidDecl if the declaration tree does not
+// have the structure produced by the parsers, instead of panicking.
+func NewAST(stmt Statement) (Stmt, error) {
+	if len(stmt.Decls) == 0 {
+		return nil, Wrap(ErrInvalidDecl, "empty statement")
+	}
+
+	d := stmt.Decls[0]
+	switch d.TokenID {
+	case TokenIDSelect:
+		return newSelectStmt(d)
+	case TokenIDInsert:
+		return newInsertStmt(d)
+	case TokenIDUpdate:
+		return newUpdateStmt(d)
+	case TokenIDDelete:
+		return newDeleteStmt(d)
+	case TokenIDTruncate:
+		table, err := child(d, 0, TokenIDString)
+		if err != nil {
+			return nil, err
+		}
+		return &TruncateStmt{Table: newTableRef(table)}, nil
+	case TokenIDDrop:
+		return newDropStmt(d)
+	case TokenIDCreate:
+		return newCreateStmt(d)
+	case TokenIDGrant:
+		return &GrantStmt{}, nil
+	default:
+		return nil, invalidDecl(d, "statement")
+	}
+}
+
+// NewASTs returns the typed AST of each statement, see NewAST.
+func NewASTs(stmts []Statement) ([]Stmt, error) {
+	asts := make([]Stmt, 0, len(stmts))
+	for _, s := range stmts {
+		ast, err := NewAST(s)
+		if err != nil {
+			return nil, err
+		}
+		asts = append(asts, ast)
+	}
+	return asts, nil
+}
+
+// invalidDecl returns the error for a declaration which is not the expected one.
+func invalidDecl(d *Decl, expected string) error {
+	return Wrap(ErrInvalidDecl, fmt.Sprintf("expected %s, got %q (%s)", expected, d.Lexeme, d.TokenID))
+}
+
+// child returns the i-th child of the declaration, which must have one of the token IDs
+// if any is given.
+func child(d *Decl, i int, ids ...TokenID) (*Decl, error) {
+	if i < 0 || i >= len(d.DeclList) {
+		return nil, Wrap(ErrInvalidDecl, fmt.Sprintf("missing declaration after %q (%s)", d.Lexeme, d.TokenID))
+	}
+	c := d.DeclList[i]
+	if len(ids) == 0 {
+		return c, nil
+	}
+	for _, id := range ids {
+		if c.TokenID == id {
+			return c, nil
+		}
+	}
+	return nil, invalidDecl(c, fmt.Sprintf("%v after %q", ids, d.Lexeme))
+}
+
+// newTableRef returns the table of a declaration of the form table or schema.table.
+func newTableRef(d *Decl) *TableRef {
+	t := &TableRef{Name: d.Lexeme.String()}
+	if len(d.DeclList) > 0 && d.DeclList[0].TokenID == TokenIDString {
+		t.Schema = d.DeclList[0].Lexeme.String()
+	}
+	return t
+}
+
+// newColumnRef returns the column of a declaration of the form column or table.column.
+// The other children (e.g. an operator in a WHERE condition) are ignored.
+func newColumnRef(d *Decl) (Expr, error) {
+	table := ""
+	if len(d.DeclList) > 0 && d.DeclList[0].TokenID == TokenIDString {
+		table = d.DeclList[0].Lexeme.String()
+	}
+	switch d.TokenID {
+	case TokenIDString:
+		return &ColumnRef{Table: table, Name: d.Lexeme.String()}, nil
+	case TokenIDStar:
+		return &Star{Table: table}, nil
+	default:
+		return nil, invalidDecl(d, "column")
+	}
+}
+
+// newValue returns the expression of a value (e.g. in VALUES, SET, DEFAULT or WHERE).
+func newValue(d *Decl) (Expr, error) {
+	kinds := map[TokenID]LiteralKind{
+		TokenIDString:         LiteralString,
+		TokenIDNumber:         LiteralNumber,
+		TokenIDDate:           LiteralDate,
+		TokenIDNull:           LiteralNull,
+		TokenIDTrue:           LiteralTrue,
+		TokenIDFalse:          LiteralFalse,
+		TokenIDNow:            LiteralNow,
+		TokenIDLocalTimestamp: LiteralLocalTimestamp,
+		TokenIDDefault:        LiteralDefault,
+	}
+	if kind, ok := kinds[d.TokenID]; ok {
+		return &Literal{Kind: kind, Value: d.Lexeme.String(), Number: d.Number}, nil
+	}
+
+	switch d.TokenID {
+	case TokenIDParameter:
+		return &Param{Name: d.Lexeme.String()}, nil
+	case TokenIDNextval, TokenIDCurrval:
+		seq, err := child(d, 0, TokenIDString)
+		if err != nil {
+			return nil, err
+		}
+		return &SequenceValue{Sequence: seq.Lexeme.String(), Next: d.TokenID == TokenIDNextval}, nil
+	case TokenIDValues:
+		// VALUES(column) of ON DUPLICATE KEY UPDATE
+		column, err := child(d, 0, TokenIDString)
+		if err != nil {
+			return nil, err
+		}
+		arg, err := newColumnRef(column)
+		if err != nil {
+			return nil, err
+		}
+		return &FuncCall{Name: "values", Args: []Expr{arg}}, nil
+	default:
+		return nil, invalidDecl(d, "value")
+	}
+}
+
+// newNumber returns the integer of the first child of the declaration (e.g. LIMIT 10).
+func newNumber(d *Decl) (*int64, error) {
+	n, err := child(d, 0, TokenIDNumber)
+	if err != nil {
+		return nil, err
+	}
+	v, err := strconv.ParseInt(n.Lexeme.String(), 10, 64)
+	if err != nil {
+		return nil, Wrap(ErrInvalidDecl, fmt.Sprintf("wrong %s value %q", d.Lexeme, n.Lexeme))
+	}
+	return &v, nil
+}
+
+// newSelectStmt returns the SELECT statement of a declaration.
+func newSelectStmt(d *Decl) (*SelectStmt, error) {
+	s := &SelectStmt{}
+	var err error
+	for _, c := range d.DeclList {
+		switch c.TokenID {
+		case TokenIDDistinct:
+			s.Distinct = true
+			for _, on := range c.DeclList {
+				expr, err := newColumnRef(on)
+				if err != nil {
+					return nil, err
+				}
+				s.DistinctOn = append(s.DistinctOn, expr)
+			}
+		case TokenIDFrom:
+			for _, t := range c.DeclList {
+				s.From = append(s.From, newTableRef(t))
+			}
+		case TokenIDJoin:
+			j, err := newJoin(c)
+			if err != nil {
+				return nil, err
+			}
+			s.Joins = append(s.Joins, j)
+		case TokenIDWhere:
+			if s.Where, err = newWhere(c); err != nil {
+				return nil, err
+			}
+		case TokenIDOrder:
+			for _, o := range c.DeclList {
+				expr, err := newColumnRef(o)
+				if err != nil {
+					return nil, err
+				}
+				desc := len(o.DeclList) > 0 && o.DeclList[len(o.DeclList)-1].TokenID == TokenIDDesc
+				s.OrderBy = append(s.OrderBy, &OrderItem{Expr: expr, Desc: desc})
+			}
+		case TokenIDLimit:
+			if s.Limit, err = newNumber(c); err != nil {
+				return nil, err
+			}
+		case TokenIDOffset:
+			if s.Offset, err = newNumber(c); err != nil {
+				return nil, err
+			}
+		case TokenIDFor:
+			s.ForUpdate = true
+		default:
+			expr, err := newSelectItem(c)
+			if err != nil {
+				return nil, err
+			}
+			s.Columns = append(s.Columns, expr)
+		}
+	}
+
+	// The expressions of DISTINCT ON are also the first ones of the select list
+	if len(s.DistinctOn) > len(s.Columns) {
+		return nil, Wrap(ErrInvalidDecl, "DISTINCT ON expressions are not selected")
+	}
+	s.Columns = s.Columns[len(s.DistinctOn):]
+	if len(s.Columns) == 0 {
+		return nil, Wrap(ErrInvalidDecl, "no column selected")
+	}
+	if len(s.From) == 0 {
+		return nil, Wrap(ErrInvalidDecl, "no table in FROM clause")
+	}
+	return s, nil
+}
+
+// newSelectItem returns the expression of an element of a select list.
+func newSelectItem(d *Decl) (Expr, error) {
+	switch d.TokenID {
+	case TokenIDString, TokenIDStar:
+		return newColumnRef(d)
+	case TokenIDNumber, TokenIDNextval, TokenIDCurrval:
+		return newValue(d)
+	case TokenIDCount:
+		arg, err := child(d, 0, TokenIDString, TokenIDStar)
+		if err != nil {
+			return nil, err
+		}
+		expr, err := newColumnRef(arg)
+		if err != nil {
+			return nil, err
+		}
+		return &FuncCall{Name: "count", Args: []Expr{expr}}, nil
+	case TokenIDCoalesce:
+		if len(d.DeclList) != 2 {
+			return nil, Wrap(ErrInvalidDecl, fmt.Sprintf("%s expects 2 arguments", d.Lexeme))
+		}
+		column, err := newColumnRef(d.DeclList[0])
+		if err != nil {
+			return nil, err
+		}
+		value, err := newValue(d.DeclList[1])
+		if err != nil {
+			return nil, err
+		}
+		return &FuncCall{Name: strings.ToLower(d.Lexeme.String()), Args: []Expr{column, value}}, nil
+	default:
+		return nil, invalidDecl(d, "column")
+	}
+}
+
+// newJoin returns the joined table of a JOIN declaration.
+func newJoin(d *Decl) (*Join, error) {
+	table, err := child(d, 0, TokenIDString)
+	if err != nil {
+		return nil, err
+	}
+	on, err := child(d, 1, TokenIDOn)
+	if err != nil {
+		return nil, err
+	}
+	if len(on.DeclList) != 3 {
+		return nil, invalidDecl(on, "join condition")
+	}
+	left, err := newColumnRef(on.DeclList[0])
+	if err != nil {
+		return nil, err
+	}
+	right, err := newColumnRef(on.DeclList[2])
+	if err != nil {
+		return nil, err
+	}
+	op, err := newBinaryOp(on.DeclList[1])
+	if err != nil {
+		return nil, err
+	}
+	return &Join{
+		Table: newTableRef(table),
+		On:    &BinaryExpr{Op: op, Left: left, Right: right},
+	}, nil
+}
+
+// newBinaryOp returns the operator of a declaration.
+func newBinaryOp(d *Decl) (BinaryOp, error) {
+	switch d.TokenID {
+	case TokenIDEquality:
+		return OpEqual, nil
+	case TokenIDDistinctness:
+		return OpNotEqual, nil
+	case TokenIDLeftDiple:
+		return OpLess, nil
+	case TokenIDRightDiple:
+		return OpGreater, nil
+	case TokenIDLessOrEqual:
+		return OpLessOrEqual, nil
+	case TokenIDGreaterOrEqual:
+		return OpGreaterOrEqual, nil
+	case TokenIDAnd:
+		return OpAnd, nil
+	case TokenIDOr:
+		return OpOr, nil
+	default:
+		return "", invalidDecl(d, "operator")
+	}
+}
+
+// newWhere returns the condition of a WHERE declaration, whose children are conditions
+// separated by AND or OR. The condition is nil for the implicit WHERE 1 of all rows.
+func newWhere(d *Decl) (Expr, error) {
+	if len(d.DeclList) == 1 && d.DeclList[0].TokenID == TokenIDNumber && d.DeclList[0].Lexeme == "1" {
+		return nil, nil
+	}
+
+	var where Expr
+	op := OpAnd
+	for i, c := range d.DeclList {
+		if i%2 == 1 {
+			var err error
+			if op, err = newBinaryOp(c); err != nil {
+				return nil, err
+			}
+			if op != OpAnd && op != OpOr {
+				return nil, invalidDecl(c, "AND or OR")
+			}
+			continue
+		}
+
+		cond, err := newCondition(c)
+		if err != nil {
+			return nil, err
+		}
+		if where == nil {
+			where = cond
+			continue
+		}
+		where = &BinaryExpr{Op: op, Left: where, Right: cond}
+	}
+	if where == nil || len(d.DeclList)%2 == 0 {
+		return nil, Wrap(ErrInvalidDecl, "incomplete WHERE clause")
+	}
+	return where, nil
+}
+
+// newCondition returns the expression of a condition of a WHERE clause.
+// The condition is a column whose children are the optional table of the column,
+// then either an operator and a value (optionally followed by COLLATE), IN, NOT IN or IS.
+func newCondition(d *Decl) (Expr, error) {
+	if d.TokenID == TokenIDNumber {
+		return newValue(d)
+	}
+	column, err := newColumnRef(d)
+	if err != nil {
+		return nil, err
+	}
+
+	rest := d.DeclList
+	if len(rest) > 0 && rest[0].TokenID == TokenIDString {
+		rest = rest[1:]
+	}
+	if len(rest) == 0 {
+		return nil, invalidDecl(d, "condition")
+	}
+
+	switch rest[0].TokenID {
+	case TokenIDIn:
+		return newIn(column, rest[0], false)
+	case TokenIDNot:
+		in, err := child(rest[0], 0, TokenIDIn)
+		if err != nil {
+			return nil, err
+		}
+		return newIn(column, in, true)
+	case TokenIDIs:
+		last, err := child(rest[0], len(rest[0].DeclList)-1, TokenIDNull)
+		if err != nil {
+			return nil, err
+		}
+		return &IsNullExpr{Expr: column, Not: last != rest[0].DeclList[0]}, nil
+	default:
+	}
+
+	op, err := newBinaryOp(rest[0])
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) < 2 {
+		return nil, invalidDecl(rest[0], "value")
+	}
+	value, err := newValue(rest[1])
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 2 && rest[2].TokenID == TokenIDCollate {
+		collation, err := child(rest[2], 0, TokenIDNocase)
+		if err != nil {
+			return nil, err
+		}
+		value = &CollateExpr{Expr: value, Collation: strings.ToUpper(collation.Lexeme.String())}
+	}
+	return &BinaryExpr{Op: op, Left: column, Right: value}, nil
+}
+
+// newIn returns the IN or NOT IN expression of an IN declaration, whose children are the values.
+func newIn(column Expr, d *Decl, not bool) (Expr, error) {
+	in := &InExpr{Expr: column, Not: not}
+	for _, v := range d.DeclList {
+		value, err := newValue(v)
+		if err != nil {
+			return nil, err
+		}
+		in.List = append(in.List, value)
+	}
+	if len(in.List) == 0 {
+		return nil, invalidDecl(d, "list of values")
+	}
+	return in, nil
+}
+
+// newAssignment returns the assignment of a declaration of the form column = value.
+func newAssignment(d *Decl) (*Assignment, error) {
+	if d.TokenID != TokenIDString || len(d.DeclList) == 0 {
+		return nil, invalidDecl(d, "assignment")
+	}
+	value, err := newValue(d.DeclList[len(d.DeclList)-1])
+	if err != nil {
+		return nil, err
+	}
+	return &Assignment{Column: d.Lexeme.String(), Value: value}, nil
+}
+
+// newInsertStmt returns the INSERT statement of a declaration.
+func newInsertStmt(d *Decl) (*InsertStmt, error) {
+	into, err := child(d, 0, TokenIDInto)
+	if err != nil {
+		return nil, err
+	}
+	table, err := child(into, 0, TokenIDString)
+	if err != nil {
+		return nil, err
+	}
+	s := &InsertStmt{Table: &TableRef{Name: table.Lexeme.String()}}
+	for _, c := range table.DeclList {
+		s.Columns = append(s.Columns, c.Lexeme.String())
+	}
+
+	values, err := child(d, 1, TokenIDValues)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range values.DeclList {
+		var exprs []Expr
+		for _, v := range row.DeclList {
+			expr, err := newValue(v)
+			if err != nil {
+				return nil, err
+			}
+			exprs = append(exprs, expr)
+		}
+		s.Rows = append(s.Rows, exprs)
+	}
+
+	for _, c := range d.DeclList[2:] {
+		switch c.TokenID {
+		case TokenIDReturning:
+			for _, r := range c.DeclList {
+				expr, err := newColumnRef(r)
+				if err != nil {
+					return nil, err
+				}
+				s.Returning = append(s.Returning, expr)
+			}
+		case TokenIDOn:
+			for _, a := range c.DeclList {
+				assignment, err := newAssignment(a)
+				if err != nil {
+					return nil, err
+				}
+				s.OnDuplicateKeyUpdate = append(s.OnDuplicateKeyUpdate, assignment)
+			}
+		case TokenIDOr:
+			action, err := child(c, 0, TokenIDReplace, TokenIDIgnore)
+			if err != nil {
+				return nil, err
+			}
+			s.Or = ConflictIgnore
+			if action.TokenID == TokenIDReplace {
+				s.Or = ConflictReplace
+			}
+		default:
+			return nil, invalidDecl(c, "RETURNING, ON DUPLICATE KEY UPDATE or OR")
+		}
+	}
+	return s, nil
+}
+
+// newUpdateStmt returns the UPDATE statement of a declaration.
+func newUpdateStmt(d *Decl) (*UpdateStmt, error) {
+	table, err := child(d, 0, TokenIDString)
+	if err != nil {
+		return nil, err
+	}
+	set, err := child(d, 1, TokenIDSet)
+	if err != nil {
+		return nil, err
+	}
+	s := &UpdateStmt{Table: &TableRef{Name: table.Lexeme.String()}}
+	for _, a := range set.DeclList {
+		assignment, err := newAssignment(a)
+		if err != nil {
+			return nil, err
+		}
+		s.Set = append(s.Set, assignment)
+	}
+
+	if len(d.DeclList) > 2 {
+		where, err := child(d, 2, TokenIDWhere)
+		if err != nil {
+			return nil, err
+		}
+		if s.Where, err = newWhere(where); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// newDeleteStmt returns the DELETE statement of a declaration.
+func newDeleteStmt(d *Decl) (*DeleteStmt, error) {
+	from, err := child(d, 0, TokenIDFrom)
+	if err != nil {
+		return nil, err
+	}
+	table, err := child(from, 0, TokenIDString)
+	if err != nil {
+		return nil, err
+	}
+	s := &DeleteStmt{Table: &TableRef{Name: table.Lexeme.String()}}
+
+	if len(d.DeclList) > 1 {
+		where, err := child(d, 1, TokenIDWhere)
+		if err != nil {
+			return nil, err
+		}
+		if s.Where, err = newWhere(where); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// newDropStmt returns the DROP TABLE or DROP SEQUENCE statement of a declaration.
+func newDropStmt(d *Decl) (Stmt, error) {
+	what, err := child(d, 0, TokenIDTable, TokenIDSequence)
+	if err != nil {
+		return nil, err
+	}
+	name, err := child(what, 0, TokenIDString)
+	if err != nil {
+		return nil, err
+	}
+	if what.TokenID == TokenIDSequence {
+		return &DropSequenceStmt{Name: name.Lexeme.String()}, nil
+	}
+	return &DropTableStmt{Table: &TableRef{Name: name.Lexeme.String()}}, nil
+}
+
+// newCreateStmt returns the CREATE TABLE, CREATE INDEX or CREATE SEQUENCE statement of a declaration.
+func newCreateStmt(d *Decl) (Stmt, error) {
+	what, err := child(d, 0, TokenIDTable, TokenIDIndex, TokenIDSequence)
+	if err != nil {
+		return nil, err
+	}
+	switch what.TokenID {
+	case TokenIDTable:
+		return newCreateTableStmt(what)
+	case TokenIDIndex:
+		return newCreateIndexStmt(what)
+	default:
+		return newCreateSequenceStmt(what)
+	}
+}
+
+// hasIfNotExists returns true if the first child of the declaration is IF NOT EXISTS.
+func hasIfNotExists(d *Decl) bool {
+	return len(d.DeclList) > 0 && d.DeclList[0].TokenID == TokenIDIf
+}
+
+// newCreateTableStmt returns the CREATE TABLE statement of a TABLE declaration.
+func newCreateTableStmt(d *Decl) (*CreateTableStmt, error) {
+	s := &CreateTableStmt{IfNotExists: hasIfNotExists(d)}
+	decls := d.DeclList
+	if s.IfNotExists {
+		decls = decls[1:]
+	}
+	if len(decls) == 0 || decls[0].TokenID != TokenIDString {
+		return nil, Wrap(ErrInvalidDecl, "missing table name")
+	}
+	s.Table = newTableRef(decls[0])
+
+	for _, c := range decls[1:] {
+		switch c.TokenID {
+		case TokenIDPrimary:
+			if s.PrimaryKey != nil {
+				return nil, Wrap(ErrInvalidDecl, fmt.Sprintf("multiple primary keys for table \"%s\" are not allowed", s.Table.Name))
+			}
+			key, err := child(c, 0, TokenIDKey)
+			if err != nil {
+				return nil, err
+			}
+			for _, column := range key.DeclList {
+				s.PrimaryKey = append(s.PrimaryKey, column.Lexeme.String())
+			}
+		case TokenIDString:
+			column, err := newColumnDef(c)
+			if err != nil {
+				return nil, err
+			}
+			s.Columns = append(s.Columns, column)
+		default:
+			return nil, invalidDecl(c, "column definition")
+		}
+	}
+	return s, nil
+}
+
+// newColumnDef returns the definition of a column, whose children are its type and constraints.
+func newColumnDef(d *Decl) (*ColumnDef, error) {
+	typeDecl, err := child(d, 0, TokenIDString)
+	if err != nil {
+		return nil, err
+	}
+	column := &ColumnDef{
+		Name: d.Lexeme.String(),
+		Type: &TypeName{Name: typeDecl.Lexeme.String()},
+	}
+	for _, arg := range typeDecl.DeclList {
+		switch arg.TokenID {
+		case TokenIDNumber:
+			column.Type.Args = append(column.Type.Args, arg.Lexeme.String())
+		case TokenIDWith:
+			column.Type.WithTimeZone = true
+		case TokenIDString:
+			column.Type.Affinity = arg.Lexeme.String()
+		default:
+			return nil, invalidDecl(arg, "type argument")
+		}
+	}
+
+	for _, c := range d.DeclList[1:] {
+		switch c.TokenID {
+		case TokenIDNot:
+			column.NotNull = true
+		case TokenIDPrimary:
+			column.PrimaryKey = true
+		case TokenIDUnique:
+			column.Unique = true
+		case TokenIDAutoincrement:
+			column.AutoIncrement = true
+		case TokenIDDefault:
+			value, err := child(c, 0)
+			if err != nil {
+				return nil, err
+			}
+			if column.Default, err = newValue(value); err != nil {
+				return nil, err
+			}
+		case TokenIDCollate:
+			collation, err := child(c, 0, TokenIDNocase)
+			if err != nil {
+				return nil, err
+			}
+			column.Collate = strings.ToUpper(collation.Lexeme.String())
+		default:
+			return nil, invalidDecl(c, "column constraint")
+		}
+	}
+	return column, nil
+}
+
+// newCreateIndexStmt returns the CREATE INDEX statement of an INDEX declaration.
+func newCreateIndexStmt(d *Decl) (*CreateIndexStmt, error) {
+	s := &CreateIndexStmt{IfNotExists: hasIfNotExists(d)}
+	decls := d.DeclList
+	if s.IfNotExists {
+		decls = decls[1:]
+	}
+	if len(decls) > 0 && decls[len(decls)-1].TokenID == TokenIDUnique {
+		s.Unique = true
+		decls = decls[:len(decls)-1]
+	}
+	if len(decls) < 3 {
+		return nil, Wrap(ErrInvalidDecl, "CREATE INDEX expects a name, a table and columns")
+	}
+	s.Name = decls[0].Lexeme.String()
+	s.Table = newTableRef(decls[1])
+
+	for _, c := range decls[2:] {
+		column := &IndexColumn{Name: c.Lexeme.String()}
+		if len(c.DeclList) > 0 {
+			collation, err := child(c.DeclList[0], 0, TokenIDNocase)
+			if err != nil {
+				return nil, err
+			}
+			column.Collate = strings.ToUpper(collation.Lexeme.String())
+		}
+		s.Columns = append(s.Columns, column)
+	}
+	return s, nil
+}
+
+// newCreateSequenceStmt returns the CREATE SEQUENCE statement of a SEQUENCE declaration.
+func newCreateSequenceStmt(d *Decl) (*CreateSequenceStmt, error) {
+	name, err := child(d, 0, TokenIDString)
+	if err != nil {
+		return nil, err
+	}
+	s := &CreateSequenceStmt{Name: name.Lexeme.String()}
+	for _, c := range d.DeclList[1:] {
+		switch c.TokenID {
+		case TokenIDWith:
+			if s.Start, err = newNumber(c); err != nil {
+				return nil, err
+			}
+		case TokenIDBy:
+			if s.Increment, err = newNumber(c); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, invalidDecl(c, "START WITH or INCREMENT BY")
+		}
+	}
+	return s, nil
+}
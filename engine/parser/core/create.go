@@ -0,0 +1,432 @@
+package core
+
+import (
+	"errors"
+	"strings"
+)
+
+// parseCreate parses the CREATE statement.
+func (p *Parser) parseCreate(tokens []Token) (*Statement, error) {
+	stmt := &Statement{}
+
+	// Set CREATE decl
+	createDecl := NewDecl(tokens[p.index])
+	stmt.Decls = append(stmt.Decls, createDecl)
+
+	// After create token, should be either TABLE, INDEX, ...
+	if !p.HasNext() {
+		return nil, ErrParseAfterCreateToken
+	}
+	p.index++
+
+	switch tokens[p.index].ID {
+	case TokenIDTable:
+		d, err := p.parseTable(tokens)
+		if err != nil {
+			return nil, err
+		}
+		createDecl.Append(d)
+	case TokenIDIndex:
+		d, err := p.parseIndex(tokens)
+		if err != nil {
+			return nil, err
+		}
+		createDecl.Append(d)
+	case TokenIDUnique:
+		u, err := p.ConsumeToken(TokenIDUnique)
+		if err != nil {
+			return nil, err
+		}
+		// should have index after unique here
+		if !p.HasNext() || tokens[p.index].ID != TokenIDIndex {
+			return nil, p.SyntaxError(TokenIDIndex)
+		}
+		d, err := p.parseIndex(tokens)
+		if err != nil {
+			return nil, err
+		}
+		d.Append(u)
+		createDecl.Append(d)
+	default:
+		parse, ok := p.dialect.ParseCreate[tokens[p.index].ID]
+		if !ok {
+			return nil, p.SyntaxError()
+		}
+		d, err := parse(p)
+		if err != nil {
+			return nil, err
+		}
+		createDecl.Append(d)
+	}
+	return stmt, nil
+}
+
+// parseTable parses the CREATE TABLE statement.
+func (p *Parser) parseTable(tokens []Token) (*Decl, error) {
+	var err error
+	tableDecl := NewDecl(tokens[p.index])
+	p.index++
+
+	if tableDecl, err = p.parseIf(tableDecl); err != nil {
+		return nil, err
+	}
+
+	// Now we should found table name
+	nameTable, err := p.ParseAttribute()
+	if err != nil {
+		return nil, p.SyntaxError()
+	}
+	tableDecl.Append(nameTable)
+
+	// Now we should found brackets
+	if !p.HasNext() || tokens[p.index].ID != TokenIDBracketOpening {
+		return nil, errors.New("table name token must be followed by table definition")
+	}
+	p.index++
+
+	for p.index < len(tokens) {
+		switch p.Current().ID {
+		case TokenIDPrimary:
+			primaryDecl, err := p.parsePrimaryKey()
+			if err != nil {
+				return nil, err
+			}
+			tableDecl.Append(primaryDecl)
+			if p.Is(TokenIDComma) {
+				if _, err := p.ConsumeToken(TokenIDComma); err != nil {
+					return nil, err
+				}
+			}
+			continue
+		default:
+		}
+
+		// Closing bracket ?
+		if tokens[p.index].ID == TokenIDBracketClosing {
+			if _, err := p.ConsumeToken(TokenIDBracketClosing); err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		// New attribute name
+		newAttribute, err := p.ParseQuotedToken()
+		if err != nil {
+			return nil, err
+		}
+		tableDecl.Append(newAttribute)
+
+		newAttributeType, err := p.parseType()
+		if err != nil {
+			return nil, err
+		}
+		newAttribute.Append(newAttributeType)
+
+		// All the following tokens until bracket or comma are column constraints.
+		// Column constraints can be listed in any order.
+		for p.IsNot(TokenIDBracketClosing, TokenIDComma) {
+			switch p.Current().ID {
+			case TokenIDUnique:
+				uniqueDecl, err := p.ConsumeToken(TokenIDUnique)
+				if err != nil {
+					return nil, err
+				}
+				newAttribute.Append(uniqueDecl)
+				// UNIQUE KEY is the same as UNIQUE
+				if p.Is(TokenIDKey) {
+					if err := p.Next(); err != nil {
+						return nil, err
+					}
+				}
+			case TokenIDNot:
+				notDecl, err := p.ConsumeToken(TokenIDNot)
+				if err != nil {
+					return nil, err
+				}
+				newAttribute.Append(notDecl)
+
+				nullDecl, err := p.ConsumeToken(TokenIDNull)
+				if err != nil {
+					return nil, err
+				}
+				notDecl.Append(nullDecl)
+			case TokenIDPrimary:
+				primaryDecl, err := p.ConsumeToken(TokenIDPrimary)
+				if err != nil {
+					return nil, err
+				}
+				newAttribute.Append(primaryDecl)
+
+				keyDecl, err := p.ConsumeToken(TokenIDKey)
+				if err != nil {
+					return nil, err
+				}
+				primaryDecl.Append(keyDecl)
+
+				// The sort order of the primary key does not matter here
+				if p.Is(TokenIDAsc, TokenIDDesc) {
+					if err := p.Next(); err != nil {
+						return nil, err
+					}
+				}
+			case TokenIDAutoincrement:
+				autoincDecl, err := p.ConsumeToken(TokenIDAutoincrement)
+				if err != nil {
+					return nil, err
+				}
+				newAttribute.Append(autoincDecl)
+			case TokenIDWith:
+				if strings.ToLower(newAttributeType.Lexeme.String()) == "timestamp" {
+					withDecl, err := p.ConsumeToken(TokenIDWith)
+					if err != nil {
+						return nil, err
+					}
+
+					timeDecl, err := p.ConsumeToken(TokenIDTime)
+					if err != nil {
+						return nil, err
+					}
+
+					zoneDecl, err := p.ConsumeToken(TokenIDZone)
+					if err != nil {
+						return nil, err
+					}
+					newAttributeType.Append(withDecl)
+					withDecl.Append(timeDecl)
+					timeDecl.Append(zoneDecl)
+				}
+			case TokenIDNull:
+				// Columns are nullable by default
+				if err := p.Next(); err != nil {
+					return nil, err
+				}
+			case TokenIDCollate:
+				collateDecl, err := p.parseCollate()
+				if err != nil {
+					return nil, err
+				}
+				if collateDecl != nil {
+					newAttribute.Append(collateDecl)
+				}
+			case TokenIDDefault:
+				dDecl, err := p.parseDefaultClause()
+				if err != nil {
+					return nil, err
+				}
+				newAttribute.Append(dDecl)
+			default:
+				ok, err := p.parseColumnOption(newAttribute)
+				if err != nil {
+					return nil, err
+				}
+				if !ok { // Unknown column constraint
+					return nil, p.SyntaxError()
+				}
+			}
+		}
+
+		if p.dialect.CompleteColumn != nil {
+			p.dialect.CompleteColumn(newAttribute)
+		}
+
+		// The current token is either closing bracked or comma.
+		// Closing bracket means table parsing stops.
+		if tokens[p.index].ID == TokenIDBracketClosing {
+			p.index++
+			break
+		}
+		// Comma means continue on next table column.
+		p.index++
+	}
+
+	if p.dialect.ParseTableOptions != nil {
+		if err := p.dialect.ParseTableOptions(p); err != nil {
+			return nil, err
+		}
+	}
+	return tableDecl, nil
+}
+
+// parseColumnOption parses a column constraint of the dialect, see Dialect.ParseColumnOption.
+func (p *Parser) parseColumnOption(columnDecl *Decl) (bool, error) {
+	if p.dialect.ParseColumnOption == nil {
+		return false, nil
+	}
+	return p.dialect.ParseColumnOption(p, columnDecl)
+}
+
+// parseIndex parses 'index' tokens.
+// INDEX index_name ON table_name (col1, col2)
+func (p *Parser) parseIndex(tokens []Token) (*Decl, error) {
+	var err error
+	indexDecl := NewDecl(tokens[p.index])
+	p.index++
+
+	// Maybe have "IF NOT EXISTS" here
+	if indexDecl, err = p.parseIf(indexDecl); err != nil {
+		return nil, err
+	}
+
+	// Now we should found index name
+	nameIndex, err := p.ParseAttribute()
+	if err != nil {
+		return nil, p.SyntaxError()
+	}
+	indexDecl.Append(nameIndex)
+
+	// ON
+	if !p.HasNext() || tokens[p.index].ID != TokenIDOn {
+		return nil, p.SyntaxError(TokenIDOn)
+	}
+	p.index++
+
+	// Now we should found table name
+	nameTable, err := p.ParseAttribute()
+	if err != nil {
+		return nil, p.SyntaxError()
+	}
+	indexDecl.Append(nameTable)
+
+	// Now we should found brackets
+	if !p.HasNext() || tokens[p.index].ID != TokenIDBracketOpening {
+		return nil, errors.New("table name token must be followed by table definition")
+	}
+	p.index++
+
+	for p.index < len(tokens) {
+		// New attribute name
+		newAttribute, err := p.ParseQuotedToken()
+		if err != nil {
+			return nil, err
+		}
+		indexDecl.Append(newAttribute)
+
+		// Closing bracket ?
+		if tokens[p.index].ID == TokenIDBracketClosing {
+			if _, err := p.ConsumeToken(TokenIDBracketClosing); err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		// All the following tokens until bracket or comma are column constraints.
+		// Column constraints can be listed in any order.
+		for p.IsNot(TokenIDBracketClosing, TokenIDComma) {
+			switch p.Current().ID {
+			case TokenIDCollate:
+				collateDecl, err := p.parseCollate()
+				if err != nil {
+					return nil, err
+				}
+				if collateDecl != nil {
+					newAttribute.Append(collateDecl)
+				}
+			default:
+				// Unknown column constraint
+				return nil, p.SyntaxError()
+			}
+		}
+		// The current token is either closing bracked or comma.
+		// Closing bracket means table parsing stops.
+		if tokens[p.index].ID == TokenIDBracketClosing {
+			p.index++
+			break
+		}
+		// Comma means continue on next table column.
+		p.index++
+	}
+	return indexDecl, nil
+}
+
+// parseIf parses 'if not exists' tokens. The given declaration is returned
+// unchanged if there is no 'if' token.
+func (p *Parser) parseIf(decl *Decl) (*Decl, error) {
+	if !p.Is(TokenIDIf) {
+		return decl, nil
+	}
+
+	ifDecl, err := p.ConsumeToken(TokenIDIf)
+	if err != nil {
+		return nil, err
+	}
+	decl.Append(ifDecl)
+
+	if !p.Is(TokenIDNot) {
+		return nil, p.SyntaxError()
+	}
+
+	notDecl, err := p.ConsumeToken(TokenIDNot)
+	if err != nil {
+		return nil, err
+	}
+	ifDecl.Append(notDecl)
+
+	if !p.Is(TokenIDExists) {
+		return nil, p.SyntaxError()
+	}
+
+	existsDecl, err := p.ConsumeToken(TokenIDExists)
+	if err != nil {
+		return nil, err
+	}
+	notDecl.Append(existsDecl)
+
+	return decl, nil
+}
+
+// parsePrimaryKey parses 'primary key (column, ...)' table constraint.
+// The column names are appended to the 'key' declaration.
+func (p *Parser) parsePrimaryKey() (*Decl, error) {
+	primaryDecl, err := p.ConsumeToken(TokenIDPrimary)
+	if err != nil {
+		return nil, err
+	}
+
+	keyDecl, err := p.ConsumeToken(TokenIDKey)
+	if err != nil {
+		return nil, err
+	}
+	primaryDecl.Append(keyDecl)
+
+	if _, err = p.ConsumeToken(TokenIDBracketOpening); err != nil {
+		return nil, err
+	}
+
+	for {
+		columnDecl, err := p.ParseQuotedToken()
+		if err != nil {
+			return nil, err
+		}
+		keyDecl.Append(columnDecl)
+
+		d, err := p.ConsumeToken(TokenIDComma, TokenIDBracketClosing)
+		if err != nil {
+			return nil, err
+		}
+		if d.TokenID == TokenIDBracketClosing {
+			break
+		}
+	}
+	return primaryDecl, nil
+}
+
+// parseDefaultClause parses 'default' tokens.
+func (p *Parser) parseDefaultClause() (*Decl, error) {
+	dDecl, err := p.ConsumeToken(TokenIDDefault)
+	if err != nil {
+		return nil, err
+	}
+
+	var vDecl *Decl
+	if p.Is(TokenIDSingleQuote) || p.Is(TokenIDDoubleQuote) {
+		vDecl, err = p.ParseStringLiteral()
+	} else {
+		vDecl, err = p.ConsumeToken(TokenIDFalse, TokenIDTrue, TokenIDNull, TokenIDNumber, TokenIDLocalTimestamp, TokenIDNow)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	dDecl.Append(vDecl)
+	return dDecl, nil
+}
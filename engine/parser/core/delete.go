@@ -1,34 +1,32 @@
-package postgres
-
-import "github.com/nao1215/aiondb/engine/parser/core"
+package core
 
 // parseDelete parses a DELETE statement.
-func (p *Parser) parseDelete() (*core.Statement, error) {
-	stmt := &core.Statement{}
+func (p *Parser) parseDelete() (*Statement, error) {
+	stmt := &Statement{}
 
 	// Set DELETE decl
-	deleteDecl, err := p.consumeToken(core.TokenIDDelete)
+	deleteDecl, err := p.ConsumeToken(TokenIDDelete)
 	if err != nil {
 		return nil, err
 	}
 	stmt.Decls = append(stmt.Decls, deleteDecl)
 
 	// should be From
-	fromDecl, err := p.consumeToken(core.TokenIDFrom)
+	fromDecl, err := p.ConsumeToken(TokenIDFrom)
 	if err != nil {
 		return nil, err
 	}
 	deleteDecl.Append(fromDecl)
 
 	// Should be a table name
-	nameDecl, err := p.parseQuotedToken()
+	nameDecl, err := p.ParseQuotedToken()
 	if err != nil {
 		return nil, err
 	}
 	fromDecl.Append(nameDecl)
 
 	// MAY be WHERE  here
-	if !p.hasNext() {
+	if !p.HasNext() {
 		return stmt, nil
 	}
 
@@ -0,0 +1,65 @@
+package core
+
+// Dialect is what an SQL dialect (e.g. MySQL) adds to the grammar shared by the parsers.
+// The lexer of the dialect only appends the tokens of its keywords, so the shared grammar
+// may accept keywords of other dialects (e.g. RETURNING). The hooks parse the clauses
+// which only exist in the dialect, a nil hook keeps the shared grammar.
+type Dialect struct {
+	// Matchers returns the matchers of the lexer, see NewLexer.
+	Matchers func(lex *Lex) Matchers
+	// KeywordCategories maps the keywords which can be used as names to their category.
+	// The other keywords are reserved.
+	KeywordCategories map[TokenID]KeywordCategory
+	// DistinctOn is true if SELECT DISTINCT ON (attribute, ...) is supported.
+	DistinctOn bool
+	// LimitOffsetCount is true if LIMIT offset, count is supported.
+	LimitOffsetCount bool
+	// ParseType parses a column type instead of Parser.ParseType.
+	ParseType func(p *Parser) (*Decl, error)
+	// ParseCollate parses a COLLATE clause instead of Parser.ParseCollate.
+	ParseCollate func(p *Parser) (*Decl, error)
+	// ParseCreate parses the CREATE statements which are not shared by the token following
+	// CREATE (e.g. SEQUENCE).
+	ParseCreate map[TokenID]func(p *Parser) (*Decl, error)
+	// ParseColumnOption parses a column constraint which is not shared (e.g. COMMENT 'a')
+	// and appends it to the column declaration. It returns false if the current token
+	// does not start a column constraint of the dialect.
+	ParseColumnOption func(p *Parser, columnDecl *Decl) (bool, error)
+	// CompleteColumn completes the declaration of a column of CREATE TABLE once its
+	// type and its constraints are parsed (e.g. replaces the type by its affinity).
+	CompleteColumn func(columnDecl *Decl)
+	// ParseTableOptions parses the table options following the columns of CREATE TABLE.
+	ParseTableOptions func(p *Parser) error
+	// ParseStmtCondition parses a condition of the WHERE clause which is not a condition
+	// on the rows (e.g. ROWNUM <= 10) and appends its declaration to the statement
+	// declaration. It returns false if the current token does not start such a condition.
+	ParseStmtCondition func(p *Parser, stmtDecl *Decl) (bool, error)
+	// ParseInsertOn parses the ON clause following the values of an INSERT statement
+	// (e.g. ON DUPLICATE KEY UPDATE).
+	ParseInsertOn func(p *Parser) (*Decl, error)
+}
+
+// KeywordCategory is the category of a keyword, which decides where the keyword can be
+// used as a name. See the "SQL Key Words" appendix of the PostgreSQL manual.
+type KeywordCategory int
+
+const (
+	// ReservedKeyword can not be used as a name, e.g. SELECT or DEFAULT.
+	ReservedKeyword KeywordCategory = iota
+	// UnreservedKeyword can be used as any name, e.g. KEY or INDEX.
+	UnreservedKeyword
+	// ColNameKeyword can be used as a table or column name, but not as a type name, e.g. TIME.
+	ColNameKeyword
+	// TypeFuncNameKeyword can be used as a type name, but not as a table or column name, e.g. JOIN.
+	TypeFuncNameKeyword
+)
+
+// IsColumnName returns true if the keywords of the category can be table and column names.
+func (c KeywordCategory) IsColumnName() bool {
+	return c == UnreservedKeyword || c == ColNameKeyword
+}
+
+// IsTypeName returns true if the keywords of the category can be type names.
+func (c KeywordCategory) IsTypeName() bool {
+	return c == UnreservedKeyword || c == TypeFuncNameKeyword
+}
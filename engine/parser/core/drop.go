@@ -0,0 +1,27 @@
+package core
+
+// parseDrop parses the DROP TABLE and DROP SEQUENCE statements.
+func (p *Parser) parseDrop() (*Statement, error) {
+	stmt := &Statement{}
+
+	trDecl, err := p.ConsumeToken(TokenIDDrop)
+	if err != nil {
+		return nil, err
+	}
+	stmt.Decls = append(stmt.Decls, trDecl)
+
+	tableDecl, err := p.ConsumeToken(TokenIDTable, TokenIDSequence)
+	if err != nil {
+		return nil, err
+	}
+	trDecl.Append(tableDecl)
+
+	// Should be a table or sequence name
+	nameDecl, err := p.ParseQuotedToken()
+	if err != nil {
+		return nil, err
+	}
+	tableDecl.Append(nameDecl)
+
+	return stmt, nil
+}
@@ -3,6 +3,8 @@ package core
 import (
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 )
 
 var (
@@ -18,8 +20,114 @@ var (
 	ErrParseAfterSelectToken = errors.New("'select' token must be followed by attributes to select")
 	// ErrNotDateFormat means input data is "not a date format"
 	ErrNotDateFormat = errors.New("not a date format")
+	// ErrInvalidDecl means "the declaration tree does not have the structure of a statement"
+	ErrInvalidDecl = errors.New("invalid declaration tree")
+	// ErrUnsupportedSyntax means "the statement cannot be written in the SQL syntax mode"
+	ErrUnsupportedSyntax = errors.New("not supported by the SQL syntax mode")
 )
 
+// SyntaxError is a syntax error in a SQL statement, with its position in the statement.
+type SyntaxError struct {
+	// Pos is the byte offset of the error in the statement, starting at 0.
+	Pos int
+	// Line is the line of the error, starting at 1.
+	Line int
+	// Col is the column of the error in its line, in characters, starting at 1.
+	Col int
+	// Near is the text at the position of the error, empty at the end of the input.
+	Near string
+	// AtEnd is true if the error is at the end of the input, after the last token.
+	AtEnd bool
+	// Expected is the list of the tokens expected at the position, empty if unknown.
+	Expected []TokenID
+	// Err is ErrLexerSyntax or ErrParserSyntax.
+	Err error
+}
+
+// NewSyntaxError returns the syntax error of the i-th token, which is not one of the
+// expected tokens. The error is at the end of the input, right after the last token
+// written in the statement, if i is out of range or the i-th token is the end of the
+// statement added by the parser (see IsStatementEnd).
+func NewSyntaxError(tokens []Token, i int, expected ...TokenID) *SyntaxError {
+	e := &SyntaxError{Line: 1, Col: 1, Expected: expected, Err: ErrParserSyntax}
+	switch {
+	case len(tokens) == 0:
+		e.AtEnd = true
+		return e
+	case i < 0:
+		i = 0
+	case i >= len(tokens):
+		end := tokens[len(tokens)-1]
+		if !IsStatementEnd(end) {
+			end = TokenAfter(end, TokenIDSemicolon, "")
+		}
+		e.Pos, e.Line, e.Col, e.AtEnd = end.Pos, end.Line, end.Col, true
+		return e
+	}
+	t := tokens[i]
+	e.Pos, e.Line, e.Col, e.Near = t.Pos, t.Line, t.Col, t.Lexeme.String()
+	e.AtEnd = IsStatementEnd(t)
+	if e.Line == 0 {
+		// The token was not produced by a lexer
+		e.Line, e.Col = 1, 1
+	}
+	return e
+}
+
+// Error returns the message of the error, e.g.
+// `syntax error at line 1, column 10 near "FORM", expected "FROM"` or
+// `syntax error at line 1, column 7 at end of input, expected "("`.
+func (e *SyntaxError) Error() string {
+	msg := fmt.Sprintf("syntax error at line %d, column %d", e.Line, e.Col)
+	switch {
+	case e.AtEnd:
+		msg += " at end of input"
+	case e.Near != "":
+		msg += fmt.Sprintf(" near %q", e.Near)
+	}
+	if len(e.Expected) > 0 {
+		texts := make([]string, 0, len(e.Expected))
+		for _, id := range e.Expected {
+			texts = append(texts, expectedText(id))
+		}
+		msg += ", expected " + strings.Join(texts, " or ")
+	}
+	return msg
+}
+
+// expectedText returns the SQL text of an expected token, e.g. "(" for
+// TokenIDBracketOpening or "FROM" for TokenIDFrom. Tokens without a fixed text
+// are described, e.g. a name for TokenIDString.
+func expectedText(id TokenID) string {
+	for _, o := range operators {
+		if o.id == id {
+			return strconv.Quote(o.symbol)
+		}
+	}
+	if quote, ok := quoteLexemes[id]; ok {
+		return strconv.Quote(quote.String())
+	}
+	switch id {
+	case TokenIDString:
+		return "a name"
+	case TokenIDNumber:
+		return "a number"
+	case TokenIDDate:
+		return "a date"
+	case TokenIDParameter:
+		return "a parameter"
+	case TokenIDNow:
+		return `"NOW()"`
+	default:
+		return strconv.Quote(strings.ToUpper(id.String()))
+	}
+}
+
+// Unwrap returns ErrLexerSyntax or ErrParserSyntax.
+func (e *SyntaxError) Unwrap() error {
+	return e.Err
+}
+
 // Wrap return wrapping error with message.
 // If e is nil, return new error with msg. If msg is empty string, return e.
 func Wrap(e error, message string) error {
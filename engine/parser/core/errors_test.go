@@ -58,3 +58,52 @@ func TestWrap(t *testing.T) {
 		})
 	}
 }
+
+func TestNewSyntaxError(t *testing.T) {
+	t.Parallel()
+
+	tokens := []Token{
+		{ID: TokenIDSelect, Lexeme: "SELECT", Pos: 0, Line: 1, Col: 1},
+		{ID: TokenIDStar, Lexeme: "*", Pos: 7, Line: 1, Col: 8},
+	}
+	tests := []struct {
+		name     string
+		index    int
+		expected []TokenID
+		wantMsg  string
+	}{
+		{
+			name:     "unexpected token",
+			index:    1,
+			expected: []TokenID{TokenIDFrom, TokenIDComma},
+			wantMsg:  `syntax error at line 1, column 8 near "*", expected "FROM" or ","`,
+		},
+		{
+			name:     "expected symbol and name",
+			index:    1,
+			expected: []TokenID{TokenIDBracketOpening, TokenIDString},
+			wantMsg:  `syntax error at line 1, column 8 near "*", expected "(" or a name`,
+		},
+		{
+			name:     "unexpected end of the statement",
+			index:    2,
+			expected: []TokenID{TokenIDFrom},
+			wantMsg:  `syntax error at line 1, column 9 at end of input, expected "FROM"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := NewSyntaxError(tokens, tt.index, tt.expected...)
+			if err.Error() != tt.wantMsg {
+				t.Errorf("want=%s, got=%s", tt.wantMsg, err.Error())
+			}
+			if !errors.Is(err, ErrParserSyntax) {
+				t.Errorf("expect ErrParserSyntax, got %v", err.Err)
+			}
+		})
+	}
+}
@@ -0,0 +1,665 @@
+package core
+
+import (
+	"fmt"
+	"strings"
+)
+
+// keywords maps the SQL syntax modes to the keywords of their lexer, see RegisterKeywords.
+var keywords = map[SQLSyntaxMode]map[string]TokenID{}
+
+// RegisterKeywords registers the keywords of the lexer of a SQL syntax mode, in lower
+// case as given to Lex.MatchWord. Identifiers which are keywords of the mode are quoted
+// when they are formatted, so they are parsed again as names. The package of each SQL
+// syntax mode registers its keywords when it is initialized.
+func RegisterKeywords(mode SQLSyntaxMode, words map[string]TokenID) {
+	keywords[mode] = words
+}
+
+// Format returns the SQL text of a parsed statement in the syntax of a SQL syntax mode.
+// The text is normalized: keywords are in upper case, identifiers are quoted only if
+// needed, and tokens are separated by a single space. Parsing the text again returns
+// the same statement, so Format can translate a statement from a dialect to another.
+// Syntax which the mode does not have returns ErrUnsupportedSyntax, except for types with
+// an equivalent (e.g. TIMESTAMP WITH TIME ZONE is DATETIME in MySQL).
+func Format(stmt Statement, mode SQLSyntaxMode) (string, error) {
+	ast, err := NewAST(stmt)
+	if err != nil {
+		return "", err
+	}
+	return FormatAST(ast, mode)
+}
+
+// FormatIndent is like Format, but writes the clauses of the statement on separate
+// lines. The selected columns, the assignments, the inserted rows and the columns of
+// CREATE TABLE are written one per line, indented by indent, and so are the AND and OR
+// operands of the WHERE clause.
+func FormatIndent(stmt Statement, mode SQLSyntaxMode, indent string) (string, error) {
+	ast, err := NewAST(stmt)
+	if err != nil {
+		return "", err
+	}
+	return format(ast, mode, indent)
+}
+
+// FormatAST returns the SQL text of a statement of the typed AST in the syntax of a
+// SQL syntax mode. See Format. Statements are written in the PostgreSQL syntax in the
+// default mode, as they are parsed.
+func FormatAST(stmt Stmt, mode SQLSyntaxMode) (string, error) {
+	return format(stmt, mode, "")
+}
+
+// format returns the SQL text of a statement, on a single line if indent is empty.
+func format(stmt Stmt, mode SQLSyntaxMode, indent string) (string, error) {
+	if mode == SQLSyntaxModeDefault {
+		mode = SQLSyntaxModePostgreSQL
+	}
+	f := &formatter{mode: mode, indent: indent}
+	if err := f.stmt(stmt); err != nil {
+		return "", err
+	}
+	return f.String(), nil
+}
+
+// formatter writes the SQL text of the typed AST.
+type formatter struct {
+	strings.Builder
+	// mode is the SQL syntax mode of the text.
+	mode SQLSyntaxMode
+	// indent is the indentation of the lines, the text is written on a single line if it is empty.
+	indent string
+}
+
+// unsupported returns the error of a syntax which cannot be written in the SQL syntax mode.
+func (f *formatter) unsupported(syntax string) error {
+	return Wrap(ErrUnsupportedSyntax, fmt.Sprintf("%s in %s", syntax, f.mode))
+}
+
+// write writes strings.
+func (f *formatter) write(s ...string) {
+	for _, v := range s {
+		f.WriteString(v)
+	}
+}
+
+// clause writes the keyword of a clause, on a new line if the text is indented.
+func (f *formatter) clause(keyword string) {
+	if f.indent != "" {
+		f.write("\n", keyword)
+		return
+	}
+	f.write(" ", keyword)
+}
+
+// items writes a list of n items separated by commas. If the text is indented, each
+// item is written on its own indented line, otherwise the list starts with first.
+func (f *formatter) items(first string, n int, item func(i int) error) error {
+	for i := 0; i < n; i++ {
+		switch {
+		case f.indent != "":
+			if i > 0 {
+				f.write(",")
+			}
+			f.write("\n", f.indent)
+		case i > 0:
+			f.write(", ")
+		default:
+			f.write(first)
+		}
+		if err := item(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ident writes an identifier, quoted if it is a keyword or is not a plain word.
+func (f *formatter) ident(name string) {
+	_, keyword := keywords[f.mode][strings.ToLower(name)]
+	plain := name != "" && !keyword && !(name[0] >= '0' && name[0] <= '9')
+	for _, r := range name {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '_') {
+			plain = false
+			break
+		}
+	}
+	switch {
+	case plain:
+		f.write(name)
+	case f.mode == SQLSyntaxModeMySQL:
+		f.write("`", strings.ReplaceAll(name, "`", "``"), "`")
+	default:
+		f.write(`"`, strings.ReplaceAll(name, `"`, `""`), `"`)
+	}
+}
+
+// identList writes a comma separated list of identifiers.
+func (f *formatter) identList(names []string) {
+	for i, name := range names {
+		if i > 0 {
+			f.write(", ")
+		}
+		f.ident(name)
+	}
+}
+
+// table writes a table reference.
+func (f *formatter) table(t *TableRef) {
+	if t.Schema != "" {
+		f.ident(t.Schema)
+		f.write(".")
+	}
+	f.ident(t.Name)
+}
+
+// str writes a string literal.
+func (f *formatter) str(s string) {
+	switch {
+	case f.mode == SQLSyntaxModeMySQL:
+		s = strings.ReplaceAll(s, `\`, `\\`)
+		f.write("'", strings.ReplaceAll(s, "'", `\'`), "'")
+	default:
+		f.write("'", strings.ReplaceAll(s, "'", "''"), "'")
+	}
+}
+
+// stmt writes a statement.
+func (f *formatter) stmt(stmt Stmt) error {
+	switch s := stmt.(type) {
+	case *SelectStmt:
+		return f.selectStmt(s)
+	case *InsertStmt:
+		return f.insertStmt(s)
+	case *UpdateStmt:
+		return f.updateStmt(s)
+	case *DeleteStmt:
+		f.write("DELETE FROM ")
+		f.table(s.Table)
+		return f.where(s.Where)
+	case *TruncateStmt:
+		f.write("TRUNCATE ")
+		f.table(s.Table)
+	case *DropTableStmt:
+		f.write("DROP TABLE ")
+		f.table(s.Table)
+	case *DropSequenceStmt:
+		f.write("DROP SEQUENCE ")
+		f.ident(s.Name)
+	case *CreateTableStmt:
+		return f.createTableStmt(s)
+	case *CreateIndexStmt:
+		f.createIndexStmt(s)
+	case *CreateSequenceStmt:
+		f.write("CREATE SEQUENCE ")
+		f.ident(s.Name)
+		if s.Start != nil {
+			f.write(fmt.Sprintf(" START WITH %d", *s.Start))
+		}
+		if s.Increment != nil {
+			f.write(fmt.Sprintf(" INCREMENT BY %d", *s.Increment))
+		}
+	default:
+		// GRANT statements do not keep their privileges
+		return Wrap(ErrUnsupportedSyntax, fmt.Sprintf("cannot format %T", stmt))
+	}
+	return nil
+}
+
+// selectStmt writes a SELECT statement.
+func (f *formatter) selectStmt(s *SelectStmt) error {
+	f.write("SELECT")
+	if s.Distinct {
+		f.write(" DISTINCT")
+	}
+	if len(s.DistinctOn) > 0 {
+		if f.mode != SQLSyntaxModePostgreSQL {
+			return f.unsupported("DISTINCT ON")
+		}
+		f.write(" ON (")
+		if err := f.exprList(s.DistinctOn); err != nil {
+			return err
+		}
+		f.write(")")
+	}
+	err := f.items(" ", len(s.Columns), func(i int) error {
+		return f.expr(s.Columns[i])
+	})
+	if err != nil {
+		return err
+	}
+
+	f.clause("FROM ")
+	for i, t := range s.From {
+		if i > 0 {
+			f.write(", ")
+		}
+		f.table(t)
+	}
+	for _, j := range s.Joins {
+		f.clause("JOIN ")
+		f.table(j.Table)
+		f.write(" ON ")
+		if err := f.expr(j.On); err != nil {
+			return err
+		}
+	}
+	if err := f.where(s.Where); err != nil {
+		return err
+	}
+
+	for i, o := range s.OrderBy {
+		if i == 0 {
+			f.clause("ORDER BY ")
+		} else {
+			f.write(", ")
+		}
+		if err := f.expr(o.Expr); err != nil {
+			return err
+		}
+		if o.Desc {
+			f.write(" DESC")
+		}
+	}
+
+	if f.mode == SQLSyntaxModeOracle {
+		if s.Offset != nil {
+			f.clause(fmt.Sprintf("OFFSET %d ROWS", *s.Offset))
+		}
+		if s.Limit != nil {
+			f.clause(fmt.Sprintf("FETCH FIRST %d ROWS ONLY", *s.Limit))
+		}
+	} else {
+		if s.Limit != nil {
+			f.clause(fmt.Sprintf("LIMIT %d", *s.Limit))
+		}
+		if s.Offset != nil {
+			f.clause(fmt.Sprintf("OFFSET %d", *s.Offset))
+		}
+	}
+
+	if s.ForUpdate {
+		f.clause("FOR UPDATE")
+	}
+	return nil
+}
+
+// where writes the WHERE clause of a condition, if any.
+func (f *formatter) where(cond Expr) error {
+	if cond == nil {
+		return nil
+	}
+	f.clause("WHERE ")
+	return f.condition(cond)
+}
+
+// condition writes a condition. If the text is indented, each AND and OR operand of
+// the condition is written on its own indented line.
+func (f *formatter) condition(cond Expr) error {
+	b, ok := cond.(*BinaryExpr)
+	if !ok || f.indent == "" || (b.Op != OpAnd && b.Op != OpOr) {
+		return f.expr(cond)
+	}
+	if err := f.operand(b.Left, b, f.condition); err != nil {
+		return err
+	}
+	f.write("\n", f.indent, string(b.Op), " ")
+	return f.operand(b.Right, b, f.expr)
+}
+
+// insertStmt writes an INSERT statement.
+func (f *formatter) insertStmt(s *InsertStmt) error {
+	switch {
+	case s.Or == ConflictAbort:
+		f.write("INSERT INTO ")
+	case f.mode == SQLSyntaxModeSQLite && s.Or == ConflictReplace:
+		f.write("INSERT OR REPLACE INTO ")
+	case f.mode == SQLSyntaxModeSQLite && s.Or == ConflictIgnore:
+		f.write("INSERT OR IGNORE INTO ")
+	case f.mode == SQLSyntaxModeMySQL && s.Or == ConflictReplace:
+		f.write("REPLACE INTO ")
+	case f.mode == SQLSyntaxModeMySQL && s.Or == ConflictIgnore:
+		f.write("INSERT IGNORE INTO ")
+	case f.mode == SQLSyntaxModePostgreSQL && s.Or == ConflictIgnore:
+		// ON CONFLICT DO NOTHING is written after the values
+		f.write("INSERT INTO ")
+	case s.Or == ConflictReplace:
+		return f.unsupported("INSERT OR REPLACE")
+	default:
+		return f.unsupported("INSERT OR IGNORE")
+	}
+	f.table(s.Table)
+	f.write(" (")
+	f.identList(s.Columns)
+	f.write(")")
+	f.clause("VALUES")
+	err := f.items(" ", len(s.Rows), func(i int) error {
+		f.write("(")
+		if err := f.exprList(s.Rows[i]); err != nil {
+			return err
+		}
+		f.write(")")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if f.mode == SQLSyntaxModePostgreSQL && s.Or == ConflictIgnore {
+		f.clause("ON CONFLICT DO NOTHING")
+	}
+	if len(s.OnDuplicateKeyUpdate) > 0 {
+		if f.mode != SQLSyntaxModeMySQL {
+			return f.unsupported("ON DUPLICATE KEY UPDATE")
+		}
+		f.clause("ON DUPLICATE KEY UPDATE")
+		if err := f.assignments(s.OnDuplicateKeyUpdate); err != nil {
+			return err
+		}
+	}
+	if len(s.Returning) > 0 {
+		if f.mode != SQLSyntaxModePostgreSQL && f.mode != SQLSyntaxModeSQLite {
+			return f.unsupported("RETURNING")
+		}
+		f.clause("RETURNING ")
+		if err := f.exprList(s.Returning); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// updateStmt writes an UPDATE statement.
+func (f *formatter) updateStmt(s *UpdateStmt) error {
+	f.write("UPDATE ")
+	f.table(s.Table)
+	f.clause("SET")
+	if err := f.assignments(s.Set); err != nil {
+		return err
+	}
+	return f.where(s.Where)
+}
+
+// assignments writes a list of assignments.
+func (f *formatter) assignments(list []*Assignment) error {
+	return f.items(" ", len(list), func(i int) error {
+		f.ident(list[i].Column)
+		f.write(" = ")
+		return f.expr(list[i].Value)
+	})
+}
+
+// createTableStmt writes a CREATE TABLE statement.
+func (f *formatter) createTableStmt(s *CreateTableStmt) error {
+	f.write("CREATE TABLE ")
+	if s.IfNotExists {
+		f.write("IF NOT EXISTS ")
+	}
+	f.table(s.Table)
+	f.write(" (")
+	n := len(s.Columns)
+	if len(s.PrimaryKey) > 0 {
+		n++
+	}
+	err := f.items("", n, func(i int) error {
+		if i < len(s.Columns) {
+			return f.columnDef(s.Columns[i])
+		}
+		f.write("PRIMARY KEY (")
+		f.identList(s.PrimaryKey)
+		f.write(")")
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if f.indent != "" {
+		f.write("\n")
+	}
+	f.write(")")
+	return nil
+}
+
+// columnDef writes the definition of a column.
+func (f *formatter) columnDef(c *ColumnDef) error {
+	f.ident(c.Name)
+	typeName := strings.ToUpper(c.Type.Name)
+	if c.Type.WithTimeZone {
+		// Only PostgreSQL has TIMESTAMP WITH TIME ZONE, DATETIME is the MySQL equivalent
+		switch f.mode {
+		case SQLSyntaxModePostgreSQL:
+		case SQLSyntaxModeMySQL:
+			typeName = "DATETIME"
+		default:
+			return f.unsupported(typeName + " WITH TIME ZONE")
+		}
+	}
+	f.write(" ", typeName)
+	if len(c.Type.Args) > 0 {
+		f.write("(", strings.Join(c.Type.Args, ", "), ")")
+	}
+	if c.Type.WithTimeZone && f.mode == SQLSyntaxModePostgreSQL {
+		f.write(" WITH TIME ZONE")
+	}
+
+	if c.Default != nil {
+		f.write(" DEFAULT ")
+		if err := f.expr(c.Default); err != nil {
+			return err
+		}
+	}
+	if c.NotNull {
+		f.write(" NOT NULL")
+	}
+	if c.PrimaryKey {
+		f.write(" PRIMARY KEY")
+	}
+	if c.AutoIncrement {
+		switch f.mode {
+		case SQLSyntaxModeMySQL:
+			f.write(" AUTO_INCREMENT")
+		case SQLSyntaxModeOracle:
+			f.write(" GENERATED BY DEFAULT AS IDENTITY")
+		default:
+			f.write(" AUTOINCREMENT")
+		}
+	}
+	if c.Unique {
+		f.write(" UNIQUE")
+	}
+	if c.Collate != "" {
+		f.write(" COLLATE ", c.Collate)
+	}
+	return nil
+}
+
+// createIndexStmt writes a CREATE INDEX statement.
+func (f *formatter) createIndexStmt(s *CreateIndexStmt) {
+	f.write("CREATE ")
+	if s.Unique {
+		f.write("UNIQUE ")
+	}
+	f.write("INDEX ")
+	if s.IfNotExists {
+		f.write("IF NOT EXISTS ")
+	}
+	f.ident(s.Name)
+	f.write(" ON ")
+	f.table(s.Table)
+	f.write(" (")
+	for i, c := range s.Columns {
+		if i > 0 {
+			f.write(", ")
+		}
+		f.ident(c.Name)
+		if c.Collate != "" {
+			f.write(" COLLATE ", c.Collate)
+		}
+	}
+	f.write(")")
+}
+
+// exprList writes a comma separated list of expressions.
+func (f *formatter) exprList(list []Expr) error {
+	for i, e := range list {
+		if i > 0 {
+			f.write(", ")
+		}
+		if err := f.expr(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// precedence returns the precedence of an expression, the higher binds tighter.
+func precedence(e Expr) int {
+	if b, ok := e.(*BinaryExpr); ok {
+		switch b.Op {
+		case OpOr:
+			return 1
+		case OpAnd:
+			return 2
+		default:
+		}
+	}
+	return 3
+}
+
+// operand writes an operand of a binary expression with write, or between parentheses
+// on a single line if the operator of the operand has a lower precedence.
+func (f *formatter) operand(operand Expr, parent *BinaryExpr, write func(Expr) error) error {
+	if precedence(operand) >= precedence(parent) {
+		return write(operand)
+	}
+	f.write("(")
+	if err := f.expr(operand); err != nil {
+		return err
+	}
+	f.write(")")
+	return nil
+}
+
+// expr writes an expression.
+func (f *formatter) expr(e Expr) error {
+	switch e := e.(type) {
+	case *ColumnRef:
+		if e.Table != "" {
+			f.ident(e.Table)
+			f.write(".")
+		}
+		f.ident(e.Name)
+	case *Star:
+		if e.Table != "" {
+			f.ident(e.Table)
+			f.write(".")
+		}
+		f.write("*")
+	case *Literal:
+		f.literal(e)
+	case *Param:
+		f.write(e.Name)
+	case *FuncCall:
+		return f.funcCall(e)
+	case *SequenceValue:
+		return f.sequenceValue(e)
+	case *BinaryExpr:
+		if err := f.operand(e.Left, e, f.expr); err != nil {
+			return err
+		}
+		f.write(" ", string(e.Op), " ")
+		return f.operand(e.Right, e, f.expr)
+	case *CollateExpr:
+		if err := f.expr(e.Expr); err != nil {
+			return err
+		}
+		f.write(" COLLATE ", e.Collation)
+	case *InExpr:
+		if err := f.expr(e.Expr); err != nil {
+			return err
+		}
+		if e.Not {
+			f.write(" NOT")
+		}
+		f.write(" IN (")
+		if err := f.exprList(e.List); err != nil {
+			return err
+		}
+		f.write(")")
+	case *IsNullExpr:
+		if err := f.expr(e.Expr); err != nil {
+			return err
+		}
+		if e.Not {
+			f.write(" IS NOT NULL")
+		} else {
+			f.write(" IS NULL")
+		}
+	default:
+		return Wrap(ErrUnsupportedSyntax, fmt.Sprintf("cannot format %T", e))
+	}
+	return nil
+}
+
+// literal writes a literal value.
+func (f *formatter) literal(l *Literal) {
+	switch l.Kind {
+	case LiteralString, LiteralDate:
+		f.str(l.Value)
+	case LiteralNumber:
+		f.write(l.Value)
+	case LiteralNull:
+		f.write("NULL")
+	case LiteralTrue:
+		f.write("TRUE")
+	case LiteralFalse:
+		f.write("FALSE")
+	case LiteralNow:
+		if f.mode == SQLSyntaxModeOracle {
+			f.write("SYSDATE")
+		} else {
+			f.write("NOW()")
+		}
+	case LiteralLocalTimestamp:
+		f.write("LOCALTIMESTAMP")
+	case LiteralDefault:
+		f.write("DEFAULT")
+	default:
+	}
+}
+
+// funcCall writes a function call.
+func (f *formatter) funcCall(c *FuncCall) error {
+	name := strings.ToUpper(c.Name)
+	if name == "NVL" || name == "COALESCE" {
+		name = "COALESCE"
+		if f.mode == SQLSyntaxModeOracle {
+			name = "NVL"
+		}
+	}
+	f.write(name, "(")
+	if err := f.exprList(c.Args); err != nil {
+		return err
+	}
+	f.write(")")
+	return nil
+}
+
+// sequenceValue writes the NEXTVAL or CURRVAL value of a sequence.
+func (f *formatter) sequenceValue(s *SequenceValue) error {
+	function := "CURRVAL"
+	if s.Next {
+		function = "NEXTVAL"
+	}
+	switch f.mode {
+	case SQLSyntaxModeOracle:
+		f.ident(s.Sequence)
+		f.write(".", function)
+	case SQLSyntaxModePostgreSQL:
+		f.write(strings.ToLower(function), "(")
+		f.str(s.Sequence)
+		f.write(")")
+	default:
+		return f.unsupported("sequences")
+	}
+	return nil
+}
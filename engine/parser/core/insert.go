@@ -0,0 +1,179 @@
+package core
+
+// parseInsert parses an INSERT statement.
+//
+// The generated AST is as follows:
+//
+//	|-> "INSERT" (InsertToken)
+//	    |-> "INTO" (IntoToken)
+//	        |-> table name
+//	            |-> column name
+//	            |-> (...)
+//	    |-> "VALUES" (ValuesToken)
+//	        |-> "(" (BracketOpeningToken)
+//	            |-> value
+//	            |-> (...)
+//	        |-> (...)
+//	    |-> "OR" (OrToken) (optional, also declared by a dialect hook for ON CONFLICT DO NOTHING)
+//	        |-> "REPLACE" (ReplaceToken) or "IGNORE" (IgnoreToken)
+//	    |-> "ON" (OnToken) (optional, e.g. ON DUPLICATE KEY UPDATE)
+//	        |-> column name
+//	            |-> "=" (EqualityToken)
+//	            |-> value or "VALUES" (ValuesToken)
+//	                         |-> column name
+//	        |-> (...)
+//	    |-> "RETURNING" (ReturningToken) (optional)
+//	        |-> column name
+//
+// REPLACE INTO is parsed as INSERT OR REPLACE INTO, and INSERT IGNORE INTO as
+// INSERT OR IGNORE INTO.
+func (p *Parser) parseInsert() (*Statement, error) {
+	stmt := &Statement{}
+
+	// Set INSERT decl
+	var insertDecl, orDecl *Decl
+	if p.Is(TokenIDReplace) {
+		replaceDecl, err := p.ConsumeToken(TokenIDReplace)
+		if err != nil {
+			return nil, err
+		}
+		insertDecl = &Decl{TokenID: TokenIDInsert, Lexeme: "insert"}
+		orDecl = &Decl{TokenID: TokenIDOr, Lexeme: "or"}
+		orDecl.Append(replaceDecl)
+	} else {
+		var err error
+		if insertDecl, err = p.ConsumeToken(TokenIDInsert); err != nil {
+			return nil, err
+		}
+		// we may have `or replace` or `or ignore` here
+		if p.Is(TokenIDOr) {
+			if orDecl, err = p.ConsumeToken(TokenIDOr); err != nil {
+				return nil, err
+			}
+			conflictDecl, err := p.ConsumeToken(TokenIDReplace, TokenIDIgnore)
+			if err != nil {
+				return nil, err
+			}
+			orDecl.Append(conflictDecl)
+		} else if p.Is(TokenIDIgnore) {
+			ignoreDecl, err := p.ConsumeToken(TokenIDIgnore)
+			if err != nil {
+				return nil, err
+			}
+			orDecl = &Decl{TokenID: TokenIDOr, Lexeme: "or"}
+			orDecl.Append(ignoreDecl)
+		}
+	}
+	stmt.Decls = append(stmt.Decls, insertDecl)
+
+	// should be INTO
+	intoDecl, err := p.ConsumeToken(TokenIDInto)
+	if err != nil {
+		return nil, err
+	}
+	insertDecl.Append(intoDecl)
+
+	// should be table Name
+	tableDecl, err := p.ParseQuotedToken()
+	if err != nil {
+		return nil, err
+	}
+	intoDecl.Append(tableDecl)
+
+	_, err = p.ConsumeToken(TokenIDBracketOpening)
+	if err != nil {
+		return nil, err
+	}
+
+	// concerned attribute
+	for {
+		decl, err := p.ParseQuotedToken()
+		if err != nil {
+			return nil, err
+		}
+		tableDecl.Append(decl)
+
+		if p.Is(TokenIDBracketClosing) {
+			if _, err = p.ConsumeToken(TokenIDBracketClosing); err != nil {
+				return nil, err
+			}
+
+			break
+		}
+
+		_, err = p.ConsumeToken(TokenIDComma)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// should be VALUES
+	valuesDecl, err := p.ConsumeToken(TokenIDValues)
+	if err != nil {
+		return nil, err
+	}
+	insertDecl.Append(valuesDecl)
+
+	for {
+		openingBracketDecl, err := p.ConsumeToken(TokenIDBracketOpening)
+		if err != nil {
+			return nil, err
+		}
+		valuesDecl.Append(openingBracketDecl)
+
+		// should be a list of values for specified attributes
+		for {
+			decl, err := p.ParseListElement()
+			if err != nil {
+				return nil, err
+			}
+			openingBracketDecl.Append(decl)
+
+			if p.Is(TokenIDBracketClosing) {
+				if _, err := p.ConsumeToken(TokenIDBracketClosing); err != nil {
+					return nil, err
+				}
+				break
+			}
+
+			_, err = p.ConsumeToken(TokenIDComma)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if p.Is(TokenIDComma) {
+			if _, err := p.ConsumeToken(TokenIDComma); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		break
+	}
+
+	if orDecl != nil {
+		insertDecl.Append(orDecl)
+	}
+
+	// we may have `on duplicate key update a = b, ...` here
+	if p.Is(TokenIDOn) && p.dialect.ParseInsertOn != nil {
+		onDecl, err := p.dialect.ParseInsertOn(p)
+		if err != nil {
+			return nil, err
+		}
+		insertDecl.Append(onDecl)
+	}
+
+	// we may have `returning "something"` here
+	if retDecl, err := p.ConsumeToken(TokenIDReturning); err == nil {
+		insertDecl.Append(retDecl)
+
+		// returned attribute
+		attrDecl, err := p.ParseAttribute()
+		if err != nil {
+			return nil, err
+		}
+		retDecl.Append(attrDecl)
+	}
+	return stmt, nil
+}
@@ -1,5 +1,12 @@
 package core
 
+import (
+	"bytes"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
 // Instruction is a single Instruction in the query.
 type Instruction struct {
 	// Content is the Content of the instruction.
@@ -26,10 +33,9 @@ type Position struct {
 
 // IsSyntaxErr is whether a syntax error occurred during lexical analysis.
 // Call after reading all matcher processes.
+// The position did not move since the last match, including at the
+// beginning of the instruction, if no matcher matched.
 func (p *Position) IsSyntaxErr() bool {
-	if p.Current == 0 && p.Security == 0 {
-		return false
-	}
 	return p.Current == p.Security
 }
 
@@ -49,6 +55,12 @@ type Lex struct {
 	Instruction *Instruction
 	// Position is the Position of the instruction.
 	Position *Position
+	// line is the line of the current position, starting at 1.
+	line int
+	// lineStart is the byte offset of the first character of the line.
+	lineStart int
+	// scanned is the byte offset up to which line and lineStart are computed.
+	scanned int
 }
 
 // NewLex creates a new lexer.
@@ -57,6 +69,95 @@ func NewLex(input string) *Lex {
 		Tokens:      []Token{},
 		Instruction: newInstruction(input),
 		Position:    newPosition(),
+		line:        1,
+	}
+}
+
+// Append appends a token found at the current position, and sets its position.
+func (l *Lex) Append(t Token) {
+	t.Pos = int(l.Position.Current)
+	t.Line, t.Col = l.lineCol(t.Pos)
+	l.Tokens = append(l.Tokens, t)
+}
+
+// MatchWord appends the keyword or the identifier starting at the current position.
+// The word is scanned once and looked up in the keywords of the SQL syntax mode, which
+// are lower case (e.g. "select"). Keywords such as now() include the empty argument
+// list. The lexeme of a keyword is the keyword in lower case, an identifier is a string
+// token as written. It returns false if no word starts at the current position.
+func (l *Lex) MatchWord(keywords map[string]TokenID) bool {
+	content := l.Instruction.Content
+	start := int(l.Position.Current)
+	if !isWordStart(content[start]) {
+		return false
+	}
+	i := start + 1
+	for i < len(content) && isWordPart(content[i]) {
+		i++
+	}
+	word := strings.ToLower(string(content[start:i]))
+
+	if bytes.HasPrefix(content[i:], []byte("()")) {
+		if id, ok := keywords[word+"()"]; ok {
+			l.Append(Token{ID: id, Lexeme: Lexeme(word + "()")})
+			l.Position.Current = uint64(i + 2)
+			return true
+		}
+	}
+
+	t := Token{ID: TokenIDString, Lexeme: Lexeme(content[start:i])}
+	if id, ok := keywords[word]; ok {
+		t = Token{ID: id, Lexeme: Lexeme(word)}
+	}
+	l.Append(t)
+	l.Position.Current = uint64(i)
+	return true
+}
+
+// isWordStart returns true if a keyword or an identifier may start with the character.
+func isWordStart(c byte) bool {
+	return unicode.IsLetter(rune(c)) || c == '_'
+}
+
+// isWordPart returns true if the character may be part of a keyword or an identifier.
+func isWordPart(c byte) bool {
+	return unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c)) || c == '_' || c == '@'
+}
+
+// lineCol returns the line and the column of a byte offset of the instruction.
+// The offsets must not decrease from a call to the next one.
+func (l *Lex) lineCol(pos int) (int, int) {
+	content := l.Instruction.Content
+	if pos > len(content) {
+		pos = len(content)
+	}
+	for ; l.scanned < pos; l.scanned++ {
+		if content[l.scanned] == '\n' {
+			l.line++
+			l.lineStart = l.scanned + 1
+		}
+	}
+	return l.line, utf8.RuneCount(content[l.lineStart:pos]) + 1
+}
+
+// SyntaxError returns the error of a lexer stuck at the current position.
+func (l *Lex) SyntaxError() *SyntaxError {
+	pos := int(l.Position.Current)
+	for pos > 0 && pos < len(l.Instruction.Content) && !utf8.RuneStart(l.Instruction.Content[pos]) {
+		// The lexer is stuck in the middle of a multibyte character
+		pos--
+	}
+	line, col := l.lineCol(pos)
+	near := l.Instruction.Content[pos:]
+	if i := bytes.IndexByte(near, '\n'); i >= 0 {
+		near = near[:i]
+	}
+	return &SyntaxError{
+		Pos:  pos,
+		Line: line,
+		Col:  col,
+		Near: string(near),
+		Err:  ErrLexerSyntax,
 	}
 }
 
@@ -65,3 +166,45 @@ type Matcher func() bool
 
 // Matchers is a list of matchers
 type Matchers []Matcher
+
+// Lexer performs the lexical analysis of a query with the matchers of an SQL dialect.
+// It satisfies the Lexer interface of the parser package.
+type Lexer struct {
+	// lex is information used during lexical analysis.
+	lex *Lex
+	// matchers are tried in order at each position of the query.
+	matchers Matchers
+}
+
+// NewLexer returns a new Lexer. The argument matchers returns the matchers of the
+// SQL dialect, which match the tokens of lex (e.g. lex.MatchSpace).
+func NewLexer(input string, matchers func(lex *Lex) Matchers) *Lexer {
+	lex := NewLex(input)
+	return &Lexer{
+		lex:      lex,
+		matchers: matchers(lex),
+	}
+}
+
+// Lex performs lexical analysis.
+func (l *Lexer) Lex() ([]Token, error) {
+	for l.lex.Position.Current < l.lex.Instruction.Length {
+		isMatch := false
+		for _, m := range l.matchers {
+			if isMatch = m(); isMatch {
+				l.lex.Position.Security = l.lex.Position.Current
+				break
+			}
+		}
+
+		if isMatch {
+			continue
+		}
+
+		if l.lex.Position.IsSyntaxErr() {
+			return nil, l.lex.SyntaxError()
+		}
+		l.lex.Position.Security = l.lex.Position.Current
+	}
+	return l.lex.Tokens, nil
+}
@@ -0,0 +1,318 @@
+package core
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+)
+
+// This file contains the matchers shared by the lexers of the SQL dialects.
+// A matcher appends the token found at the current position and moves the
+// position after it, or returns false without moving the position.
+
+// operators are the punctuation and the operator tokens. Operators of two characters
+// come first, so that <= is not lexed as < followed by =.
+var operators = []struct {
+	symbol string
+	id     TokenID
+}{
+	{symbol: "<>", id: TokenIDDistinctness},
+	{symbol: "<=", id: TokenIDLessOrEqual},
+	{symbol: ">=", id: TokenIDGreaterOrEqual},
+	{symbol: ";", id: TokenIDSemicolon},
+	{symbol: ".", id: TokenIDPeriod},
+	{symbol: "(", id: TokenIDBracketOpening},
+	{symbol: ")", id: TokenIDBracketClosing},
+	{symbol: ",", id: TokenIDComma},
+	{symbol: "*", id: TokenIDStar},
+	{symbol: "=", id: TokenIDEquality},
+	{symbol: "<", id: TokenIDLeftDiple},
+	{symbol: ">", id: TokenIDRightDiple},
+}
+
+// quoteLexemes maps the token IDs of the quotes to their lexeme.
+var quoteLexemes = map[TokenID]Lexeme{
+	TokenIDSingleQuote: "'",
+	TokenIDDoubleQuote: `"`,
+	TokenIDBacktick:    "`",
+}
+
+// appendToken appends a token found at the current position and moves the position
+// after its lexeme.
+func (l *Lex) appendToken(t Token) {
+	l.Append(t)
+	l.Position.Current += uint64(len(t.Lexeme))
+}
+
+// MatchSymbol checks whether the current position starts with the symbol, and appends
+// it as a token of the given ID.
+func (l *Lex) MatchSymbol(symbol string, id TokenID) bool {
+	if !bytes.HasPrefix(l.Instruction.Content[l.Position.Current:], []byte(symbol)) {
+		return false
+	}
+	l.appendToken(Token{ID: id, Lexeme: Lexeme(symbol)})
+	return true
+}
+
+// MatchOperator checks whether it matches a punctuation or an operator token,
+// e.g. a semicolon, a bracket or <=.
+func (l *Lex) MatchOperator() bool {
+	for _, o := range operators {
+		if l.MatchSymbol(o.symbol, o.id) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchSpace checks whether it matches the space(e.g. " ") token.
+func (l *Lex) MatchSpace() bool {
+	if !unicode.IsSpace(rune(l.Instruction.Content[l.Position.Current])) {
+		return false
+	}
+	l.appendToken(Token{ID: TokenIDSpace, Lexeme: " "})
+	return true
+}
+
+// MatchComment checks whether it matches a comment, from -- to the end of the
+// line or between /* and */. Block comments may be nested. Comments are skipped,
+// they do not append a token.
+func (l *Lex) MatchComment() bool {
+	content := l.Instruction.Content
+	i := int(l.Position.Current)
+	switch {
+	case bytes.HasPrefix(content[i:], []byte("--")):
+		end := bytes.IndexByte(content[i:], '\n')
+		if end < 0 {
+			l.Position.Current = l.Instruction.Length
+			return true
+		}
+		l.Position.Current = uint64(i + end)
+		return true
+	case bytes.HasPrefix(content[i:], []byte("/*")):
+		depth := 0
+		for ; i+1 < len(content); i++ {
+			switch {
+			case content[i] == '/' && content[i+1] == '*':
+				depth++
+				i++
+			case content[i] == '*' && content[i+1] == '/':
+				depth--
+				i++
+				if depth == 0 {
+					l.Position.Current = uint64(i + 1)
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
+
+// MatchString checks whether it matches the string token, a word which is not a
+// keyword (e.g. @name).
+func (l *Lex) MatchString() bool {
+	content := l.Instruction.Content
+	start := l.Position.Current
+	i := start
+	for i < l.Instruction.Length && isWordPart(content[i]) {
+		i++
+	}
+	if i == start {
+		return false
+	}
+
+	l.Append(Token{ID: TokenIDString, Lexeme: Lexeme(content[start:i])})
+	l.Position.Current = i
+	return true
+}
+
+// MatchQuoted checks whether it matches a token quoted by opening and closing (e.g. 'a'
+// or [a]). It appends the opening quote, the unquoted content as a string token and
+// the closing quote, if any, as tokens of the quote ID. A quote is written as two
+// quotes in the content if opening and closing are the same character. If unescape
+// is not nil, a backslash escapes the next character, which is replaced by unescape.
+func (l *Lex) MatchQuoted(opening, closing byte, id TokenID, unescape func(c byte) []byte) bool {
+	content := l.Instruction.Content
+	if content[l.Position.Current] != opening {
+		return false
+	}
+	quote := quoteLexemes[id]
+	l.Append(Token{ID: id, Lexeme: quote})
+	l.Position.Current++
+
+	value := []byte{}
+	closed := false
+	i := l.Position.Current
+	for ; i < l.Instruction.Length; i++ {
+		c := content[i]
+		if c == '\\' && unescape != nil && i+1 < l.Instruction.Length {
+			i++
+			value = append(value, unescape(content[i])...)
+			continue
+		}
+		if c == closing {
+			if opening == closing && i+1 < l.Instruction.Length && content[i+1] == closing {
+				i++
+				value = append(value, closing)
+				continue
+			}
+			closed = true
+			break
+		}
+		value = append(value, c)
+	}
+
+	l.Append(Token{ID: TokenIDString, Lexeme: Lexeme(value)})
+	l.Position.Current = i
+	if closed {
+		l.Append(Token{ID: id, Lexeme: quote})
+		l.Position.Current++
+	}
+	return true
+}
+
+// MatchNumber checks whether it matches the number token: an integer (e.g. 42, -7,
+// 0x1F, 0o17, 0b101 or 1_000), a decimal (e.g. 1.5) or a float (e.g. 1.5e-3).
+func (l *Lex) MatchNumber() bool {
+	content := l.Instruction.Content
+	start := int(l.Position.Current)
+	i := start
+	if content[i] == '-' {
+		i++
+	}
+
+	// A number starts with a digit, a single period is not a number
+	if i == len(content) || !IsDigit(content[i], 10) {
+		return false
+	}
+
+	isDecimalDigit := func(c byte) bool { return IsDigit(c, 10) }
+	kind := NumberKindInteger
+	if end := scanPrefixedInteger(content, i); end > i {
+		i = end
+	} else {
+		i = scanDigits(content, i, isDecimalDigit)
+		if i < len(content) && content[i] == '.' {
+			kind = NumberKindDecimal
+			i = scanDigits(content, i+1, isDecimalDigit)
+		}
+		if i < len(content) && (content[i] == 'e' || content[i] == 'E') {
+			j := i + 1
+			if j < len(content) && (content[j] == '+' || content[j] == '-') {
+				j++
+			}
+			if end := scanDigits(content, j, isDecimalDigit); end > j {
+				kind = NumberKindFloat
+				i = end
+			}
+		}
+	}
+
+	l.Append(Token{
+		ID:     TokenIDNumber,
+		Lexeme: Lexeme(content[start:i]),
+		Number: kind,
+	})
+	l.Position.Current = uint64(i)
+	return true
+}
+
+// scanPrefixedInteger returns the index following the hexadecimal (0x), octal (0o)
+// or binary (0b) integer at the index i, or i if there is none.
+func scanPrefixedInteger(content []byte, i int) int {
+	if content[i] != '0' || i+1 == len(content) {
+		return i
+	}
+	var base int
+	switch content[i+1] {
+	case 'x', 'X':
+		base = 16
+	case 'o', 'O':
+		base = 8
+	case 'b', 'B':
+		base = 2
+	default:
+		return i
+	}
+	if end := scanDigits(content, i+2, func(c byte) bool { return IsDigit(c, base) }); end > i+2 {
+		return end
+	}
+	return i
+}
+
+// scanDigits returns the index following the digits at the index i.
+// Digits may be separated by single underscores (e.g. 1_000).
+func scanDigits(content []byte, i int, isDigit func(c byte) bool) int {
+	start := i
+	for i < len(content) {
+		switch {
+		case isDigit(content[i]):
+		case content[i] == '_' && i > start && i+1 < len(content) && isDigit(content[i+1]):
+		default:
+			return i
+		}
+		i++
+	}
+	return i
+}
+
+// IsDigit returns true if c is a digit in the base 2, 8, 10 or 16.
+func IsDigit(c byte, base int) bool {
+	switch base {
+	case 2:
+		return c == '0' || c == '1'
+	case 8:
+		return '0' <= c && c <= '7'
+	case 16:
+		return ('0' <= c && c <= '9') || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+	default:
+		return '0' <= c && c <= '9'
+	}
+}
+
+// MatchDate checks whether it matches the date token, which ends before a comma or
+// a closing bracket, e.g. 2015-09-10 14:03:09.444695269 +0200 CEST
+func (l *Lex) MatchDate() bool {
+	content := l.Instruction.Content
+	i := l.Position.Current
+	for i < l.Instruction.Length && content[i] != ',' && content[i] != ')' {
+		i++
+	}
+
+	data := string(content[l.Position.Current:i])
+	if _, err := ParseDate(data); err != nil {
+		return false
+	}
+	l.Append(Token{ID: TokenIDDate, Lexeme: Lexeme(data)})
+	l.Position.Current = i
+	return true
+}
+
+// MatchParameter checks whether it matches a parameter token starting with one of the
+// prefixes. A ? is followed by an optional number (e.g. ? or ?1), the other prefixes
+// by a name or a number (e.g. :name or :1). The lexeme keeps the prefix.
+func (l *Lex) MatchParameter(prefixes string) bool {
+	content := l.Instruction.Content
+	start := l.Position.Current
+	if strings.IndexByte(prefixes, content[start]) < 0 {
+		return false
+	}
+	i := start + 1
+	if content[start] == '?' {
+		for i < l.Instruction.Length && IsDigit(content[i], 10) {
+			i++
+		}
+	} else {
+		for i < l.Instruction.Length && isWordPart(content[i]) && content[i] != '@' {
+			i++
+		}
+		if i == start+1 {
+			return false
+		}
+	}
+
+	l.Append(Token{ID: TokenIDParameter, Lexeme: Lexeme(content[start:i])})
+	l.Position.Current = i
+	return true
+}
@@ -1,5 +1,10 @@
 package core
 
+import (
+	"fmt"
+	"strings"
+)
+
 // SQLSyntaxMode is the SQL syntax mode.
 type SQLSyntaxMode uint64
 
@@ -15,3 +20,36 @@ const (
 	// SQLSyntaxModeSQLite is the SQLite SQL syntax mode.
 	SQLSyntaxModeSQLite SQLSyntaxMode = 4
 )
+
+// String returns the name of the SQL syntax mode, as used in DSN options (e.g. "?dialect=mysql").
+func (m SQLSyntaxMode) String() string {
+	switch m {
+	case SQLSyntaxModeMySQL:
+		return "mysql"
+	case SQLSyntaxModePostgreSQL:
+		return "postgres"
+	case SQLSyntaxModeOracle:
+		return "oracle"
+	case SQLSyntaxModeSQLite:
+		return "sqlite"
+	default:
+		return "default"
+	}
+}
+
+// ParseSQLSyntaxMode returns the SQL syntax mode of a dialect name (e.g. "mysql", "postgres").
+// The name is case-insensitive.
+func ParseSQLSyntaxMode(name string) (SQLSyntaxMode, error) {
+	switch strings.ToLower(name) {
+	case "mysql", "mariadb":
+		return SQLSyntaxModeMySQL, nil
+	case "postgres", "postgresql", "pg":
+		return SQLSyntaxModePostgreSQL, nil
+	case "oracle":
+		return SQLSyntaxModeOracle, nil
+	case "sqlite", "sqlite3":
+		return SQLSyntaxModeSQLite, nil
+	default:
+		return SQLSyntaxModeDefault, fmt.Errorf("unknown dialect %q, allowed dialects are postgres, mysql, sqlite and oracle", name)
+	}
+}
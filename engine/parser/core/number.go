@@ -0,0 +1,89 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// NumberKind is the kind of a numeric literal.
+type NumberKind int
+
+const (
+	// NumberKindNone is the kind of the tokens which are not numeric literals.
+	NumberKindNone NumberKind = iota
+	// NumberKindInteger is an integer, e.g. 42, -7, 0x1F or 1_000.
+	NumberKindInteger
+	// NumberKindDecimal is a number with a fractional part, e.g. 1.5.
+	NumberKindDecimal
+	// NumberKindFloat is a number with an exponent, e.g. 1.5e-3.
+	NumberKindFloat
+)
+
+// String returns the name of the numeric kind.
+func (k NumberKind) String() string {
+	switch k {
+	case NumberKindInteger:
+		return "integer"
+	case NumberKindDecimal:
+		return "decimal"
+	case NumberKindFloat:
+		return "float"
+	default:
+		return "none"
+	}
+}
+
+// MarshalText returns the name of the numeric kind, so that tokens and declarations
+// are printed as JSON with readable kinds.
+func (k NumberKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// ParseInt returns the value of an integer literal, e.g. -7, 0x1F, 0o17, 0b101 or 1_000.
+// Unlike Go, a leading zero does not make an octal integer (e.g. 010 is 10).
+func ParseInt(lexeme Lexeme) (int64, error) {
+	s := lexeme.String()
+	digits := strings.TrimPrefix(s, "-")
+	if len(digits) > 2 && digits[0] == '0' && strings.ContainsRune("xXoObB", rune(digits[1])) {
+		return strconv.ParseInt(s, 0, 64)
+	}
+	return strconv.ParseInt(strings.ReplaceAll(s, "_", ""), 10, 64)
+}
+
+// ParseFloat returns the value of a numeric literal, e.g. 1.5, 1.5e-3 or 1_000.5.
+func ParseFloat(lexeme Lexeme) (float64, error) {
+	return strconv.ParseFloat(strings.ReplaceAll(lexeme.String(), "_", ""), 64)
+}
+
+// NumberValue returns the value of a numeric literal of the kind: an int64 for integers
+// and a float64 for decimals and floats.
+func NumberValue(kind NumberKind, lexeme Lexeme) (interface{}, error) {
+	var v interface{}
+	var err error
+	switch kind {
+	case NumberKindInteger:
+		v, err = ParseInt(lexeme)
+	case NumberKindDecimal, NumberKindFloat:
+		v, err = ParseFloat(lexeme)
+	default:
+		return nil, fmt.Errorf("%s is not a numeric literal", lexeme)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// NumberKindOf returns the kind of a text which is a whole numeric literal (e.g. the
+// content '42' of a quoted literal), or NumberKindNone if it is not a number.
+func NumberKindOf(text string) NumberKind {
+	if text == "" {
+		return NumberKindNone
+	}
+	l := NewLex(text)
+	if !l.MatchNumber() || int(l.Position.Current) != len(text) {
+		return NumberKindNone
+	}
+	return l.Tokens[0].Number
+}
@@ -0,0 +1,73 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNumberValue(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		kind    NumberKind
+		lexeme  Lexeme
+		want    interface{}
+		wantErr bool
+	}{
+		{kind: NumberKindInteger, lexeme: "42", want: int64(42)},
+		{kind: NumberKindInteger, lexeme: "010", want: int64(10)},
+		{kind: NumberKindInteger, lexeme: "-1_000", want: int64(-1000)},
+		{kind: NumberKindInteger, lexeme: "0x1F", want: int64(31)},
+		{kind: NumberKindInteger, lexeme: "-0o17", want: int64(-15)},
+		{kind: NumberKindInteger, lexeme: "0B101", want: int64(5)},
+		{kind: NumberKindDecimal, lexeme: "1_000.5", want: 1000.5},
+		{kind: NumberKindFloat, lexeme: "1.5e-3", want: 0.0015},
+		{kind: NumberKindInteger, lexeme: "9223372036854775808", wantErr: true},
+		{kind: NumberKindNone, lexeme: "abc", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.lexeme.String(), func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NumberValue(tt.kind, tt.lexeme)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNumberKindOf(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		text string
+		want NumberKind
+	}{
+		{text: "42", want: NumberKindInteger},
+		{text: "-0x1F", want: NumberKindInteger},
+		{text: "1.5", want: NumberKindDecimal},
+		{text: "1.5e-3", want: NumberKindFloat},
+		{text: "", want: NumberKindNone},
+		{text: "abc", want: NumberKindNone},
+		{text: "42abc", want: NumberKindNone},
+		{text: "1.5.2", want: NumberKindNone},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.text, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.want, NumberKindOf(tt.text)); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,709 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Parser parses SQL queries into declaration trees with the grammar shared by the SQL
+// dialects and what the dialect adds to it. The parsers of the dialect packages (e.g.
+// postgres) are Parsers, they satisfy the Parser interface of the parser package.
+type Parser struct {
+	// dialect is the SQL dialect of the queries.
+	dialect Dialect
+	stmt    []Statement
+	index   int
+	tokens  []Token
+}
+
+// NewParser returns a new Parser for the SQL dialect.
+func NewParser(dialect Dialect) *Parser {
+	return &Parser{dialect: dialect}
+}
+
+// Parse parses the string (e.g., SQL query).
+func (p *Parser) Parse(input string) ([]Statement, error) {
+	lexer := NewLexer(input, p.dialect.Matchers)
+	tokens, err := lexer.Lex()
+	if err != nil {
+		return nil, err
+	}
+
+	stmt, err := p.parse(tokens)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(stmt) == 0 {
+		return nil, Wrap(ErrParserSyntax, input)
+	}
+	return stmt, nil
+}
+
+// ParseAST parses the string (e.g., SQL query) and returns the typed AST of its statements.
+// The parser builds declaration trees, they are converted to the typed AST by NewASTs.
+func (p *Parser) ParseAST(input string) ([]Stmt, error) {
+	stmts, err := p.Parse(input)
+	if err != nil {
+		return nil, err
+	}
+	return NewASTs(stmts)
+}
+
+// parse parses the tokens. It is called by the Parse method.
+func (p *Parser) parse(tokens []Token) ([]Statement, error) {
+	tokens = StripSpaces(tokens)
+
+	// A statement may omit the final semicolon. Add it, so that the last
+	// token of a statement is always followed by another one. See IsStatementEnd.
+	if len(tokens) > 0 && tokens[len(tokens)-1].ID != TokenIDSemicolon {
+		tokens = append(tokens, TokenAfter(tokens[len(tokens)-1], TokenIDSemicolon, ""))
+	}
+
+	p.stmt = nil
+	p.tokens = tokens
+	p.index = 0
+
+	for p.HasNext() {
+		// Found a new instruction
+		if tokens[p.index].ID == TokenIDSemicolon {
+			p.index++
+			continue
+		}
+
+		// Ignore space token, not needed anymore
+		if tokens[p.index].ID == TokenIDSpace {
+			p.index++
+			continue
+		}
+		// Now,
+		// Create a logical tree of all tokens
+		// We start with first order query
+		// CREATE, SELECT, INSERT, UPDATE, DELETE, TRUNCATE, DROP, EXPLAIN
+		switch tokens[p.index].ID {
+		case TokenIDCreate:
+			stmt, err := p.parseCreate(tokens)
+			if err != nil {
+				return nil, err
+			}
+			p.stmt = append(p.stmt, *stmt)
+		case TokenIDSelect:
+			stmt, err := p.parseSelect(tokens)
+			if err != nil {
+				return nil, err
+			}
+			p.stmt = append(p.stmt, *stmt)
+		case TokenIDInsert, TokenIDReplace:
+			stmt, err := p.parseInsert()
+			if err != nil {
+				return nil, err
+			}
+			p.stmt = append(p.stmt, *stmt)
+		case TokenIDUpdate:
+			stmt, err := p.parseUpdate()
+			if err != nil {
+				return nil, err
+			}
+			p.stmt = append(p.stmt, *stmt)
+		case TokenIDDelete:
+			stmt, err := p.parseDelete()
+			if err != nil {
+				return nil, err
+			}
+			p.stmt = append(p.stmt, *stmt)
+		case TokenIDTruncate:
+			stmt, err := p.parseTruncate()
+			if err != nil {
+				return nil, err
+			}
+			p.stmt = append(p.stmt, *stmt)
+		case TokenIDDrop:
+			stmt, err := p.parseDrop()
+			if err != nil {
+				return nil, err
+			}
+			p.stmt = append(p.stmt, *stmt)
+		case TokenIDExplain:
+			// No check for explain, it is a single token
+		case TokenIDGrant:
+			stmt := &Statement{}
+			stmt.Decls = append(stmt.Decls, NewDecl(Token{ID: TokenIDGrant, Lexeme: Lexeme("grant")}))
+			p.stmt = append(p.stmt, *stmt)
+			return p.stmt, nil
+		default:
+			return nil, p.SyntaxError()
+		}
+	}
+	return p.stmt, nil
+}
+
+// Next moves to the next token.
+func (p *Parser) Next() error {
+	if p.HasNext() {
+		p.index++
+		return nil
+	}
+	return ErrEndOfStatement
+}
+
+// HasNext returns true if the next token exists.
+func (p *Parser) HasNext() bool {
+	return p.index+1 < len(p.tokens)
+}
+
+// mustHaveNext returns the next token if it exists.
+func (p *Parser) mustHaveNext(tokenTypes ...TokenID) (Token, error) {
+	t := Token{}
+	if !p.HasNext() {
+		return t, NewSyntaxError(p.tokens, p.index+1, tokenTypes...)
+	}
+	if err := p.Next(); err != nil {
+		return t, err
+	}
+
+	for _, tokenType := range tokenTypes {
+		if p.Is(tokenType) {
+			return p.tokens[p.index], nil
+		}
+	}
+	return t, p.SyntaxError(tokenTypes...)
+}
+
+// Is returns true if the current token is one of the specified tokens.
+func (p *Parser) Is(tokenTypes ...TokenID) bool {
+	for _, tokenType := range tokenTypes {
+		if p.Current().ID == tokenType {
+			return true
+		}
+	}
+	return false
+}
+
+// IsNot returns true if the current token is not one of the specified tokens.
+func (p *Parser) IsNot(tokenTypes ...TokenID) bool {
+	return !p.Is(tokenTypes...)
+}
+
+// IsNext returns the next token if it is one of the specified tokens.
+func (p *Parser) IsNext(tokenTypes ...TokenID) (Token, error) {
+	t := Token{}
+	for _, tokenType := range tokenTypes {
+		if p.HasNext() && p.tokens[p.index+1].ID == tokenType {
+			return p.tokens[p.index+1], nil
+		}
+	}
+	return t, NewSyntaxError(p.tokens, p.index+1, tokenTypes...)
+}
+
+// Current returns the current token.
+func (p *Parser) Current() Token {
+	return p.tokens[p.index]
+}
+
+// ConsumeToken consumes the current token and returns its declaration.
+func (p *Parser) ConsumeToken(tokenTypes ...TokenID) (*Decl, error) {
+	if !p.Is(tokenTypes...) {
+		return nil, p.SyntaxError(tokenTypes...)
+	}
+	decl := NewDecl(p.tokens[p.index])
+	if err := p.Next(); err != nil {
+		return nil, err
+	}
+	return decl, nil
+}
+
+// IsWord returns true if the current token is a string token matching one of the
+// given words, case-insensitively. It is used for the keywords which are not
+// reserved in a dialect, so that they can still be used as identifiers.
+func (p *Parser) IsWord(words ...string) bool {
+	if !p.Is(TokenIDString) {
+		return false
+	}
+	for _, word := range words {
+		if strings.EqualFold(p.Current().Lexeme.String(), word) {
+			return true
+		}
+	}
+	return false
+}
+
+// ConsumeWord consumes the current token if it is a string token matching one of the
+// given words, case-insensitively, and returns it.
+func (p *Parser) ConsumeWord(words ...string) (*Decl, error) {
+	if !p.IsWord(words...) {
+		return nil, p.SyntaxError()
+	}
+	return p.ConsumeToken(TokenIDString)
+}
+
+// SyntaxError returns the syntax error of the current token, which is not one of the
+// expected tokens.
+func (p *Parser) SyntaxError(expected ...TokenID) error {
+	return NewSyntaxError(p.tokens, p.index, expected...)
+}
+
+// isName returns true if the current token is a name: a string, or a keyword of a
+// category accepted by the argument accept (e.g. KEY as a column name).
+func (p *Parser) isName(accept func(KeywordCategory) bool) bool {
+	if p.Is(TokenIDString) {
+		return true
+	}
+	category, ok := p.dialect.KeywordCategories[p.Current().ID]
+	return ok && accept(category)
+}
+
+// nameDecl returns the declaration of the current token. A keyword used as a name is
+// declared as a string, so that it is handled like any other identifier.
+func (p *Parser) nameDecl() *Decl {
+	t := p.Current()
+	if _, ok := p.dialect.KeywordCategories[t.ID]; ok {
+		t.ID = TokenIDString
+	}
+	return NewDecl(t)
+}
+
+// ParseAttribute parse an attribute of the form
+// table.foo
+// table.*
+// "table".foo
+// "table"."foo"
+// foo
+func (p *Parser) ParseAttribute() (*Decl, error) {
+	quoted := false
+	quoteToken := TokenIDDoubleQuote
+
+	if p.Is(TokenIDDoubleQuote) || p.Is(TokenIDBacktick) {
+		quoteToken = p.Current().ID
+		quoted = true
+		if err := p.Next(); err != nil {
+			return nil, err
+		}
+	}
+
+	// should be a StringToken here
+	// If there is a point after, it's a table name,
+	// if not, it's the attribute
+	if !p.Is(TokenIDStar) && !p.isName(KeywordCategory.IsColumnName) {
+		return nil, p.SyntaxError()
+	}
+	decl := p.nameDecl()
+
+	if quoted {
+		// Check there is a closing quote
+		if _, err := p.mustHaveNext(quoteToken); err != nil {
+			return nil, err
+		}
+	}
+	quoted = false
+
+	// If no next token, and not quoted, then is was the attribute name
+	if err := p.Next(); err != nil {
+		return decl, nil
+	}
+
+	if !p.Is(TokenIDPeriod) {
+		return decl, nil
+	}
+	if _, err := p.ConsumeToken(TokenIDPeriod); err != nil {
+		return nil, err
+	}
+
+	// mayby attribute is quoted as well
+	if p.Is(TokenIDDoubleQuote) || p.Is(TokenIDBacktick) {
+		quoteToken = p.Current().ID
+		quoted = true
+		if err := p.Next(); err != nil {
+			return nil, err
+		}
+	}
+
+	// if so, next must be the attribute name, a star or the NEXTVAL and CURRVAL
+	// pseudocolumns of a sequence (e.g. seq.NEXTVAL)
+	if !p.Is(TokenIDStar, TokenIDNextval, TokenIDCurrval) && !p.isName(KeywordCategory.IsColumnName) {
+		return nil, p.SyntaxError(TokenIDString, TokenIDStar)
+	}
+	attributeDecl := p.nameDecl()
+	if err := p.Next(); err != nil {
+		return nil, err
+	}
+	attributeDecl.Append(decl)
+
+	if quoted {
+		// Check there is a closing quote
+		if _, err := p.ConsumeToken(quoteToken); err != nil {
+			return nil, fmt.Errorf("expected closing quote: %w", err)
+		}
+	}
+	return attributeDecl, nil
+}
+
+// ParseQuotedToken parse a token of the form
+// table
+// "table"
+func (p *Parser) ParseQuotedToken() (*Decl, error) {
+	quoted := false
+	quoteToken := TokenIDDoubleQuote
+
+	if p.Is(TokenIDDoubleQuote) || p.Is(TokenIDBacktick) {
+		quoted = true
+		quoteToken = p.Current().ID
+		if err := p.Next(); err != nil {
+			return nil, err
+		}
+	}
+
+	// shoud be a StringToken here
+	if !p.isName(KeywordCategory.IsColumnName) {
+		return nil, p.SyntaxError()
+	}
+	decl := p.nameDecl()
+
+	if quoted {
+		// Check there is a closing quote
+		if _, err := p.mustHaveNext(quoteToken); err != nil {
+			return nil, err
+		}
+	}
+	if err := p.Next(); err != nil {
+		return nil, err
+	}
+	return decl, nil
+}
+
+// parseType parses a column type with the grammar of the dialect.
+func (p *Parser) parseType() (*Decl, error) {
+	if p.dialect.ParseType != nil {
+		return p.dialect.ParseType(p)
+	}
+	return p.ParseType()
+}
+
+// ParseType parse a type of the form
+// int
+// varchar(255)
+// decimal(10, 2)
+// The sizes are appended to the type declaration.
+func (p *Parser) ParseType() (*Decl, error) {
+	// TIME is not a type name keyword, but a type of its own.
+	if !p.Is(TokenIDTime) && !p.isName(KeywordCategory.IsTypeName) {
+		return nil, p.SyntaxError(TokenIDString)
+	}
+	typeDecl := p.nameDecl()
+	if err := p.Next(); err != nil {
+		return nil, err
+	}
+
+	// Maybe a complex type
+	if !p.Is(TokenIDBracketOpening) {
+		return typeDecl, nil
+	}
+
+	if _, err := p.ConsumeToken(TokenIDBracketOpening); err != nil {
+		return nil, err
+	}
+
+	for {
+		sizeDecl, err := p.ConsumeToken(TokenIDNumber)
+		if err != nil {
+			return nil, err
+		}
+		typeDecl.Append(sizeDecl)
+
+		d, err := p.ConsumeToken(TokenIDComma, TokenIDBracketClosing)
+		if err != nil {
+			return nil, err
+		}
+		if d.TokenID == TokenIDBracketClosing {
+			break
+		}
+	}
+	return typeDecl, nil
+}
+
+// parseCollate parses a COLLATE clause with the grammar of the dialect.
+func (p *Parser) parseCollate() (*Decl, error) {
+	if p.dialect.ParseCollate != nil {
+		return p.dialect.ParseCollate(p)
+	}
+	return p.ParseCollate()
+}
+
+// ParseCollate parses 'collate name' tokens. NOCASE returns a 'collate' declaration
+// with a 'nocase' declaration. BINARY is the default collating sequence, it returns
+// nil. Other collating sequences are not supported.
+func (p *Parser) ParseCollate() (*Decl, error) {
+	collateDecl, err := p.ConsumeToken(TokenIDCollate)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.IsWord("binary") {
+		if err := p.Next(); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+	if !p.Is(TokenIDNocase) {
+		return nil, fmt.Errorf("unsupported collating sequence %s", p.Current().Lexeme)
+	}
+	nocaseDecl, err := p.ConsumeToken(TokenIDNocase)
+	if err != nil {
+		return nil, err
+	}
+	collateDecl.Append(nocaseDecl)
+	return collateDecl, nil
+}
+
+// ParseStringLiteral parse a string literal of the form.
+func (p *Parser) ParseStringLiteral() (*Decl, error) {
+	singleQuoted := p.Is(TokenIDSingleQuote)
+	_, err := p.ConsumeToken(TokenIDSingleQuote, TokenIDDoubleQuote)
+	if err != nil {
+		return nil, err
+	}
+
+	valueDecl, err := p.ConsumeToken(TokenIDString)
+	if err != nil {
+		return nil, err
+	}
+
+	if (singleQuoted && p.Is(TokenIDDoubleQuote)) || (!singleQuoted && p.Is(TokenIDSingleQuote)) {
+		return nil, errors.New("quotation marks do not match")
+	}
+	if _, err = p.ConsumeToken(TokenIDSingleQuote, TokenIDDoubleQuote); err != nil {
+		return nil, err
+	}
+	return valueDecl, nil
+}
+
+// isBuiltinFunc returns true if the current token is a builtin function followed by its
+// opening bracket. Without bracket, COUNT is a name (e.g. a column named count).
+func (p *Parser) isBuiltinFunc() bool {
+	if !p.Is(TokenIDCount) {
+		return false
+	}
+	_, err := p.IsNext(TokenIDBracketOpening)
+	return err == nil
+}
+
+// parseBuiltinFunc parse a builtin function(COUNT, MAX, MIN) of the form.
+func (p *Parser) parseBuiltinFunc() (*Decl, error) {
+	// COUNT(attribute)
+	if !p.Is(TokenIDCount) {
+		return &Decl{}, nil
+	}
+
+	d, err := p.ConsumeToken(TokenIDCount)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bracket
+	_, err = p.ConsumeToken(TokenIDBracketOpening)
+	if err != nil {
+		return nil, err
+	}
+
+	// Attribute
+	attr, err := p.ParseAttribute()
+	if err != nil {
+		return nil, err
+	}
+	d.Append(attr)
+
+	// Bracket
+	_, err = p.ConsumeToken(TokenIDBracketClosing)
+	if err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// parseJoin parses the JOIN keywords and all its condition
+// JOIN user_addresses ON address.id=user_addresses.address_id
+func (p *Parser) parseJoin() (*Decl, error) {
+	joinDecl, err := p.ConsumeToken(TokenIDJoin)
+	if err != nil {
+		return nil, err
+	}
+
+	// TABLE NAME
+	tableDecl, err := p.ParseAttribute()
+	if err != nil {
+		return nil, err
+	}
+	joinDecl.Append(tableDecl)
+
+	// ON
+	onDecl, err := p.ConsumeToken(TokenIDOn)
+	if err != nil {
+		return nil, err
+	}
+	joinDecl.Append(onDecl)
+
+	// ATTRIBUTE
+	leftAttributeDecl, err := p.ParseAttribute()
+	if err != nil {
+		return nil, err
+	}
+	onDecl.Append(leftAttributeDecl)
+
+	// EQUAL
+	equalAttr, err := p.ConsumeToken(TokenIDEquality)
+	if err != nil {
+		return nil, err
+	}
+	onDecl.Append(equalAttr)
+
+	// ATTRIBUTE
+	rightAttributeDecl, err := p.ParseAttribute()
+	if err != nil {
+		return nil, err
+	}
+	onDecl.Append(rightAttributeDecl)
+
+	return joinDecl, nil
+}
+
+// parseIn parses the IN keywords and all its condition
+func (p *Parser) parseIn() (*Decl, error) {
+	inDecl, err := p.ConsumeToken(TokenIDIn)
+	if err != nil {
+		return nil, err
+	}
+
+	// bracket opening
+	_, err = p.ConsumeToken(TokenIDBracketOpening)
+	if err != nil {
+		return nil, err
+	}
+
+	// list of value
+	for {
+		v, err := p.ParseValue()
+		if err != nil {
+			return nil, err
+		}
+		inDecl.Append(v)
+		gotList := true
+
+		if p.Is(TokenIDBracketClosing) {
+			if !gotList {
+				return nil, errors.New("in clause: empty list of value")
+			}
+			if _, err := p.ConsumeToken(TokenIDBracketClosing); err != nil {
+				return nil, err
+			}
+			break
+		}
+
+		_, err = p.ConsumeToken(TokenIDComma)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return inDecl, nil
+}
+
+// ParseValue parses a value of the form.
+func (p *Parser) ParseValue() (*Decl, error) {
+	quoted := false
+
+	if p.Is(TokenIDSingleQuote) || p.Is(TokenIDDoubleQuote) {
+		quoted = true
+		_, err := p.ConsumeToken(TokenIDSingleQuote, TokenIDDoubleQuote)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	valueDecl, err := p.ConsumeToken(TokenIDString, TokenIDNumber, TokenIDDate, TokenIDNow, TokenIDParameter)
+	if err != nil {
+		return nil, err
+	}
+	if quoted {
+		_, err := p.ConsumeToken(TokenIDSingleQuote, TokenIDDoubleQuote)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return valueDecl, nil
+}
+
+// ParseListElement parses a list element of the form.
+func (p *Parser) ParseListElement() (*Decl, error) {
+	quoted := false
+
+	// In case of INSERT, can be DEFAULT here
+	if p.Is(TokenIDDefault) {
+		v, err := p.ConsumeToken(TokenIDDefault)
+		if err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+
+	// seq.NEXTVAL or seq.CURRVAL
+	if _, err := p.IsNext(TokenIDPeriod); err == nil && p.Is(TokenIDString) {
+		return p.parseSequenceValue()
+	}
+
+	if p.Is(TokenIDSingleQuote) || p.Is(TokenIDDoubleQuote) {
+		quoted = true
+		if err := p.Next(); err != nil {
+			return nil, err
+		}
+	}
+
+	var valueDecl *Decl
+	valueDecl, err := p.ConsumeToken(TokenIDString, TokenIDNumber, TokenIDNull, TokenIDDate, TokenIDNow, TokenIDParameter)
+	if err != nil {
+		return nil, err
+	}
+	if quoted {
+		if _, err := p.ConsumeToken(TokenIDSingleQuote, TokenIDDoubleQuote); err != nil {
+			return nil, err
+		}
+	}
+	return valueDecl, nil
+}
+
+// parseSequenceValue parses the NEXTVAL or CURRVAL pseudocolumn of a sequence of the form
+// seq.NEXTVAL. The declaration is NEXTVAL (or CURRVAL) with the sequence name as child.
+func (p *Parser) parseSequenceValue() (*Decl, error) {
+	decl, err := p.ParseAttribute()
+	if err != nil {
+		return nil, err
+	}
+	if decl.TokenID != TokenIDNextval && decl.TokenID != TokenIDCurrval {
+		return nil, fmt.Errorf("expected NEXTVAL or CURRVAL after %s", decl.DeclList[0].Lexeme)
+	}
+	return decl, nil
+}
+
+// parseAttribution parses an attribution of the form `attribute = value`.
+func (p *Parser) parseAttribution() (*Decl, error) {
+	// Attribute
+	attributeDecl, err := p.ParseAttribute()
+	if err != nil {
+		return nil, err
+	}
+
+	// Equals operator
+	if p.Current().ID == TokenIDEquality {
+		decl, err := p.ConsumeToken(p.Current().ID)
+		if err != nil {
+			return nil, err
+		}
+		attributeDecl.Append(decl)
+	}
+
+	// Value, it can be NULL or DEFAULT
+	valueDecl, err := p.ParseListElement()
+	if err != nil {
+		return nil, err
+	}
+	attributeDecl.Append(valueDecl)
+	return attributeDecl, nil
+}
@@ -2,17 +2,21 @@ package core
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strings"
 )
 
 // Decl structure is the node to statement declaration tree
 type Decl struct {
 	// TokenID is token id
-	TokenID TokenID
+	TokenID TokenID `json:"token"`
 	// Lexeme is token lexeme
-	Lexeme Lexeme
+	Lexeme Lexeme `json:"lexeme"`
+	// Number is the kind of the numeric literal, NumberKindNone for other tokens.
+	Number NumberKind `json:"number,omitempty"`
 	// DeclList is the list of declaration
-	DeclList []*Decl
+	DeclList []*Decl `json:"decls,omitempty"`
 }
 
 // NewDecl initialize a Decl struct from a given token
@@ -20,6 +24,7 @@ func NewDecl(t Token) *Decl {
 	return &Decl{
 		TokenID: t.ID,
 		Lexeme:  t.Lexeme,
+		Number:  t.Number,
 	}
 }
 
@@ -30,26 +35,34 @@ func (d *Decl) Append(subDecl *Decl) {
 
 // String prints the declaration tree in console
 func (d *Decl) String(depth int) {
-	indent := ""
-	for i := 0; i < depth; i++ {
-		indent = fmt.Sprintf("%s    ", indent)
-	}
+	d.Fprint(os.Stdout, depth)
+}
+
+// Fprint writes the declaration tree to w with indentation, one declaration
+// per line followed by its token name.
+func (d *Decl) Fprint(w io.Writer, depth int) {
+	indent := strings.Repeat("    ", depth)
 
-	fmt.Fprintf(os.Stdout, "%s|-> %s\n", indent, d.Lexeme)
+	fmt.Fprintf(w, "%s|-> %s (%s)\n", indent, d.Lexeme, d.TokenID)
 	for _, v := range d.DeclList {
-		v.String(depth + 1)
+		v.Fprint(w, depth+1)
 	}
 }
 
 // Statement define a valid SQL statement
 type Statement struct {
 	// Decls is the list of declaration
-	Decls []*Decl
+	Decls []*Decl `json:"decls"`
 }
 
 // PrettyPrint prints statement's declarations on console with indentation
 func (s *Statement) PrettyPrint() {
+	s.Fprint(os.Stdout)
+}
+
+// Fprint writes statement's declarations to w with indentation
+func (s *Statement) Fprint(w io.Writer) {
 	for _, d := range s.Decls {
-		d.String(0)
+		d.Fprint(w, 0)
 	}
 }
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// newTestStatement returns the statement of "SELECT id FROM users".
+func newTestStatement() Statement {
+	selectDecl := NewDecl(Token{ID: TokenIDSelect, Lexeme: "select"})
+	selectDecl.Append(NewDecl(Token{ID: TokenIDString, Lexeme: "id"}))
+	fromDecl := NewDecl(Token{ID: TokenIDFrom, Lexeme: "from"})
+	fromDecl.Append(NewDecl(Token{ID: TokenIDString, Lexeme: "users"}))
+	selectDecl.Append(fromDecl)
+	return Statement{Decls: []*Decl{selectDecl}}
+}
+
+func TestStatementFprint(t *testing.T) {
+	t.Parallel()
+
+	stmt := newTestStatement()
+	var got bytes.Buffer
+	stmt.Fprint(&got)
+
+	want := `|-> select (Select)
+    |-> id (String)
+    |-> from (From)
+        |-> users (String)
+`
+	if diff := cmp.Diff(want, got.String()); diff != "" {
+		t.Errorf("value is mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestStatementJSON(t *testing.T) {
+	t.Parallel()
+
+	got, err := json.Marshal(newTestStatement())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := `{"decls":[{"token":"Select","lexeme":"select","decls":[{"token":"String","lexeme":"id"},{"token":"From","lexeme":"from","decls":[{"token":"String","lexeme":"users"}]}]}]}`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("value is mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestTokenIDString(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		id   TokenID
+		want string
+	}{
+		{id: TokenIDSpace, want: "Space"},
+		{id: TokenIDGreaterOrEqual, want: "GreaterOrEqual"},
+		{id: TokenIDLocalTimestamp, want: "LocalTimestamp"},
+		{id: TokenIDDate, want: "Date"},
+		{id: TokenID(9999), want: "TokenID(9999)"},
+	}
+	for _, tt := range tests {
+		if got := tt.id.String(); got != tt.want {
+			t.Errorf("want=%s, got=%s", tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,365 @@
+package core
+
+// parseSelect parses the SELECT statement.
+func (p *Parser) parseSelect(tokens []Token) (*Statement, error) {
+	stmt := &Statement{}
+	var err error
+
+	// Create select decl
+	selectDecl := NewDecl(tokens[p.index])
+	stmt.Decls = append(stmt.Decls, selectDecl)
+
+	// After select token, should be either
+	// a StarToken
+	// a list of table names + (StarToken Or Attribute)
+	// a builtin func (COUNT, MAX, ...)
+	if err = p.Next(); err != nil {
+		return nil, ErrParseAfterSelectToken
+	}
+
+	distinctDecl, distinctOpen, err := p.parseDistinct(selectDecl)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = p.parseColumnBeforeFromToken(selectDecl, distinctDecl, distinctOpen); err != nil {
+		return nil, err
+	}
+
+	// Must be from now
+	if tokens[p.index].ID != TokenIDFrom {
+		return nil, p.SyntaxError(TokenIDFrom)
+	}
+	fromDecl := NewDecl(tokens[p.index])
+	selectDecl.Append(fromDecl)
+
+	// Now must be a list of table
+	for {
+		// string
+		if err = p.Next(); err != nil {
+			return nil, NewSyntaxError(tokens, p.index+1, TokenIDString)
+		}
+		tableNameDecl, err := p.ParseAttribute()
+		if err != nil {
+			return nil, err
+		}
+		fromDecl.Append(tableNameDecl)
+
+		// If no next, then it's implicit where
+		if !p.HasNext() {
+			appendImplicitWhereAll(selectDecl)
+			return stmt, nil
+		}
+		// if not comma, break
+		if tokens[p.index].ID != TokenIDComma {
+			break // No more table
+		}
+	}
+
+	// JOIN OR ...?
+	for p.Is(TokenIDJoin) {
+		joinDecl, err := p.parseJoin()
+		if err != nil {
+			return nil, err
+		}
+		selectDecl.Append(joinDecl)
+	}
+
+	hazWhereClause := false
+	for {
+		switch p.Current().ID {
+		case TokenIDWhere:
+			err := p.parseWhere(selectDecl)
+			if err != nil {
+				return nil, err
+			}
+			hazWhereClause = true
+		case TokenIDOrder:
+			if !hazWhereClause {
+				// WHERE clause is implicit
+				appendImplicitWhereAll(selectDecl)
+			}
+			err := p.parseOrderBy(selectDecl)
+			if err != nil {
+				return nil, err
+			}
+		case TokenIDLimit:
+			limitDecl, err := p.ConsumeToken(TokenIDLimit)
+			if err != nil {
+				return nil, err
+			}
+			selectDecl.Append(limitDecl)
+
+			numDecl, err := p.ConsumeToken(TokenIDNumber)
+			if err != nil {
+				return nil, err
+			}
+
+			// LIMIT offset, count is the same as LIMIT count OFFSET offset
+			if p.dialect.LimitOffsetCount && p.Is(TokenIDComma) {
+				if _, err := p.ConsumeToken(TokenIDComma); err != nil {
+					return nil, err
+				}
+				offsetDecl := NewDecl(Token{ID: TokenIDOffset, Lexeme: "offset"})
+				offsetDecl.Append(numDecl)
+				if numDecl, err = p.ConsumeToken(TokenIDNumber); err != nil {
+					return nil, err
+				}
+				selectDecl.Append(offsetDecl)
+			}
+			limitDecl.Append(numDecl)
+		case TokenIDOffset:
+			offsetDecl, err := p.ConsumeToken(TokenIDOffset)
+			if err != nil {
+				return nil, err
+			}
+			selectDecl.Append(offsetDecl)
+
+			offsetValue, err := p.ConsumeToken(TokenIDNumber)
+			if err != nil {
+				return nil, err
+			}
+			offsetDecl.Append(offsetValue)
+
+			// OFFSET n ROWS
+			if p.IsWord("row", "rows") {
+				if err := p.Next(); err != nil {
+					return nil, err
+				}
+			}
+		case TokenIDFetch:
+			limitDecl, err := p.parseFetch()
+			if err != nil {
+				return nil, err
+			}
+			selectDecl.Append(limitDecl)
+		case TokenIDFor:
+			err := p.parseForUpdate(selectDecl)
+			if err != nil {
+				return nil, err
+			}
+		default:
+			return stmt, nil
+		}
+	}
+}
+
+// parseDistinct parse 'distinct' clause. The returned boolean is true if the
+// attributes of DISTINCT ON follow, it is always false if the dialect has no DISTINCT ON.
+func (p *Parser) parseDistinct(selectDecl *Decl) (*Decl, bool, error) {
+	if !p.Is(TokenIDDistinct) {
+		return nil, false, nil
+	}
+
+	var distinctDecl *Decl
+	distinctDecl, err := p.ConsumeToken(TokenIDDistinct)
+	if err != nil {
+		return distinctDecl, false, err
+	}
+
+	distinctOpen := false
+	if p.dialect.DistinctOn && p.Is(TokenIDOn) {
+		if err := p.Next(); err != nil {
+			return distinctDecl, false, err
+		}
+		if !p.Is(TokenIDBracketOpening) {
+			return distinctDecl, false, p.SyntaxError(TokenIDBracketOpening)
+		}
+		if err := p.Next(); err != nil {
+			return distinctDecl, false, err
+		}
+		distinctOpen = true
+	}
+	selectDecl.Append(distinctDecl)
+
+	return distinctDecl, distinctOpen, nil
+}
+
+// parseColumnBeforeFromToken parses the column before FROM token.
+func (p *Parser) parseColumnBeforeFromToken(selectDecl, distinctDecl *Decl, distinctOpen bool) error {
+	for {
+		switch {
+		case p.isBuiltinFunc():
+			attrDecl, err := p.parseBuiltinFunc()
+			if err != nil {
+				return err
+			}
+			selectDecl.Append(attrDecl)
+		case p.Is(TokenIDCoalesce):
+			nvlDecl, err := p.parseNvl()
+			if err != nil {
+				return err
+			}
+			selectDecl.Append(nvlDecl)
+		case p.Is(TokenIDNumber):
+			// A constant, e.g. SELECT 1 FROM DUAL
+			numDecl, err := p.ConsumeToken(TokenIDNumber)
+			if err != nil {
+				return err
+			}
+			selectDecl.Append(numDecl)
+		default:
+			attrDecl, err := p.ParseAttribute()
+			if err != nil {
+				return err
+			}
+			if distinctOpen {
+				distinctDecl.Append(attrDecl)
+			}
+			selectDecl.Append(attrDecl)
+		}
+
+		switch {
+		case distinctOpen && p.Is(TokenIDBracketClosing):
+			if err := p.Next(); err != nil {
+				return err
+			}
+			distinctOpen = false
+			continue
+		case p.Is(TokenIDComma):
+			if err := p.Next(); err != nil {
+				return err
+			}
+			continue
+		}
+		break
+	}
+	return nil
+}
+
+// appendImplicitWhereAll appends implicit where clause.
+func appendImplicitWhereAll(decl *Decl) {
+	whereDecl := NewDecl(Token{
+		ID:     TokenIDWhere,
+		Lexeme: "where",
+	})
+
+	whereDecl.Append(NewDecl(Token{
+		ID:     TokenIDNumber,
+		Lexeme: "1",
+	}))
+	decl.Append(whereDecl)
+}
+
+// parseOrderBy parses 'order by' clause.
+func (p *Parser) parseOrderBy(selectDecl *Decl) error {
+	orderDecl, err := p.ConsumeToken(TokenIDOrder)
+	if err != nil {
+		return err
+	}
+	selectDecl.Append(orderDecl)
+
+	_, err = p.ConsumeToken(TokenIDBy)
+	if err != nil {
+		return err
+	}
+
+	for {
+		// parse attribute now
+		attrDecl, err := p.ParseAttribute()
+		if err != nil {
+			return err
+		}
+		orderDecl.Append(attrDecl)
+
+		if p.Is(TokenIDAsc, TokenIDDesc) {
+			decl, err := p.ConsumeToken(TokenIDAsc, TokenIDDesc)
+			if err != nil {
+				return err
+			}
+			attrDecl.Append(decl)
+		}
+
+		if !p.Is(TokenIDComma) {
+			break
+		}
+
+		if _, err = p.ConsumeToken(TokenIDComma); err != nil {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (p *Parser) parseForUpdate(decl *Decl) error {
+	// Optionnal
+	if !p.Is(TokenIDFor) {
+		return nil
+	}
+	d, err := p.ConsumeToken(TokenIDFor)
+	if err != nil {
+		return err
+	}
+
+	u, err := p.ConsumeToken(TokenIDUpdate)
+	if err != nil {
+		return err
+	}
+
+	d.Append(u)
+	decl.Append(d)
+	return nil
+}
+
+// parseFetch parses 'fetch {first|next} [n] {row|rows} only' clause.
+// It is returned as a LIMIT declaration, n is 1 if it is omitted.
+func (p *Parser) parseFetch() (*Decl, error) {
+	if _, err := p.ConsumeToken(TokenIDFetch); err != nil {
+		return nil, err
+	}
+	if _, err := p.ConsumeWord("first", "next"); err != nil {
+		return nil, err
+	}
+
+	countDecl := NewDecl(Token{ID: TokenIDNumber, Lexeme: "1", Number: NumberKindInteger})
+	if p.Is(TokenIDNumber) {
+		var err error
+		if countDecl, err = p.ConsumeToken(TokenIDNumber); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := p.ConsumeWord("row", "rows"); err != nil {
+		return nil, err
+	}
+	if _, err := p.ConsumeWord("only"); err != nil {
+		return nil, err
+	}
+
+	limitDecl := NewDecl(Token{ID: TokenIDLimit, Lexeme: "limit"})
+	limitDecl.Append(countDecl)
+	return limitDecl, nil
+}
+
+// parseNvl parses 'nvl(attribute, value)' function. The value is returned
+// when the attribute is NULL.
+func (p *Parser) parseNvl() (*Decl, error) {
+	nvlDecl, err := p.ConsumeToken(TokenIDCoalesce)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = p.ConsumeToken(TokenIDBracketOpening); err != nil {
+		return nil, err
+	}
+
+	attrDecl, err := p.ParseAttribute()
+	if err != nil {
+		return nil, err
+	}
+	nvlDecl.Append(attrDecl)
+
+	if _, err = p.ConsumeToken(TokenIDComma); err != nil {
+		return nil, err
+	}
+
+	valueDecl, err := p.ParseValue()
+	if err != nil {
+		return nil, err
+	}
+	nvlDecl.Append(valueDecl)
+
+	if _, err = p.ConsumeToken(TokenIDBracketClosing); err != nil {
+		return nil, err
+	}
+	return nvlDecl, nil
+}
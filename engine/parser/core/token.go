@@ -1,5 +1,10 @@
 package core
 
+import (
+	"fmt"
+	"unicode/utf8"
+)
+
 // TokenID is the type of token ID.
 type TokenID uint64
 
@@ -159,6 +164,20 @@ const (
 	TokenIDCollate TokenID = 336
 	// TokenIDNocase is the token ID for nocase.
 	TokenIDNocase TokenID = 337
+	// TokenIDReplace is the token ID for replace.
+	TokenIDReplace TokenID = 338
+	// TokenIDIgnore is the token ID for ignore.
+	TokenIDIgnore TokenID = 339
+	// TokenIDFetch is the token ID for fetch.
+	TokenIDFetch TokenID = 340
+	// TokenIDSequence is the token ID for sequence.
+	TokenIDSequence TokenID = 341
+	// TokenIDNextval is the token ID for nextval.
+	TokenIDNextval TokenID = 342
+	// TokenIDCurrval is the token ID for currval.
+	TokenIDCurrval TokenID = 343
+	// TokenIDCoalesce is the token ID for coalesce (e.g. COALESCE, NVL).
+	TokenIDCoalesce TokenID = 344
 
 	//=======================
 	//  Type token
@@ -178,15 +197,216 @@ const (
 	TokenIDNumber TokenID = 405
 	// TokenIDDate is the token ID for date.
 	TokenIDDate TokenID = 406
+	// TokenIDParameter is the token ID for a parameter (e.g. ?, ?1, :name) to be bound before execution.
+	TokenIDParameter TokenID = 407
 )
 
+// String returns the name of the token ID (e.g. "Select").
+func (id TokenID) String() string {
+	switch id {
+	case TokenIDSpace:
+		return "Space"
+	case TokenIDSemicolon:
+		return "Semicolon"
+	case TokenIDComma:
+		return "Comma"
+	case TokenIDBracketOpening:
+		return "BracketOpening"
+	case TokenIDBracketClosing:
+		return "BracketClosing"
+	case TokenIDLeftDiple:
+		return "LeftDiple"
+	case TokenIDRightDiple:
+		return "RightDiple"
+	case TokenIDLessOrEqual:
+		return "LessOrEqual"
+	case TokenIDGreaterOrEqual:
+		return "GreaterOrEqual"
+	case TokenIDBacktick:
+		return "Backtick"
+	case TokenIDDoubleQuote:
+		return "DoubleQuote"
+	case TokenIDSingleQuote:
+		return "SingleQuote"
+	case TokenIDStar:
+		return "Star"
+	case TokenIDEquality:
+		return "Equality"
+	case TokenIDDistinctness:
+		return "Distinctness"
+	case TokenIDPeriod:
+		return "Period"
+	case TokenIDCreate:
+		return "Create"
+	case TokenIDSelect:
+		return "Select"
+	case TokenIDInsert:
+		return "Insert"
+	case TokenIDUpdate:
+		return "Update"
+	case TokenIDDelete:
+		return "Delete"
+	case TokenIDExplain:
+		return "Explain"
+	case TokenIDTruncate:
+		return "Truncate"
+	case TokenIDDrop:
+		return "Drop"
+	case TokenIDGrant:
+		return "Grant"
+	case TokenIDDistinct:
+		return "Distinct"
+	case TokenIDFrom:
+		return "From"
+	case TokenIDWhere:
+		return "Where"
+	case TokenIDTable:
+		return "Table"
+	case TokenIDInto:
+		return "Into"
+	case TokenIDValues:
+		return "Values"
+	case TokenIDJoin:
+		return "Join"
+	case TokenIDOn:
+		return "On"
+	case TokenIDIf:
+		return "If"
+	case TokenIDNot:
+		return "Not"
+	case TokenIDExists:
+		return "Exists"
+	case TokenIDNull:
+		return "Null"
+	case TokenIDAutoincrement:
+		return "Autoincrement"
+	case TokenIDCount:
+		return "Count"
+	case TokenIDSet:
+		return "Set"
+	case TokenIDOrder:
+		return "Order"
+	case TokenIDBy:
+		return "By"
+	case TokenIDWith:
+		return "With"
+	case TokenIDTime:
+		return "Time"
+	case TokenIDZone:
+		return "Zone"
+	case TokenIDReturning:
+		return "Returning"
+	case TokenIDIn:
+		return "In"
+	case TokenIDAnd:
+		return "And"
+	case TokenIDOr:
+		return "Or"
+	case TokenIDAsc:
+		return "Asc"
+	case TokenIDDesc:
+		return "Desc"
+	case TokenIDLimit:
+		return "Limit"
+	case TokenIDIs:
+		return "Is"
+	case TokenIDFor:
+		return "For"
+	case TokenIDDefault:
+		return "Default"
+	case TokenIDLocalTimestamp:
+		return "LocalTimestamp"
+	case TokenIDTrue:
+		return "True"
+	case TokenIDFalse:
+		return "False"
+	case TokenIDUnique:
+		return "Unique"
+	case TokenIDNow:
+		return "Now"
+	case TokenIDOffset:
+		return "Offset"
+	case TokenIDIndex:
+		return "Index"
+	case TokenIDCollate:
+		return "Collate"
+	case TokenIDNocase:
+		return "Nocase"
+	case TokenIDReplace:
+		return "Replace"
+	case TokenIDIgnore:
+		return "Ignore"
+	case TokenIDFetch:
+		return "Fetch"
+	case TokenIDSequence:
+		return "Sequence"
+	case TokenIDNextval:
+		return "Nextval"
+	case TokenIDCurrval:
+		return "Currval"
+	case TokenIDCoalesce:
+		return "Coalesce"
+	case TokenIDText:
+		return "Text"
+	case TokenIDInt:
+		return "Int"
+	case TokenIDPrimary:
+		return "Primary"
+	case TokenIDKey:
+		return "Key"
+	case TokenIDString:
+		return "String"
+	case TokenIDNumber:
+		return "Number"
+	case TokenIDDate:
+		return "Date"
+	case TokenIDParameter:
+		return "Parameter"
+	default:
+		return fmt.Sprintf("TokenID(%d)", uint64(id))
+	}
+}
+
+// MarshalText returns the name of the token ID, so that tokens and declarations
+// are encoded with readable token names.
+func (id TokenID) MarshalText() ([]byte, error) {
+	return []byte(id.String()), nil
+}
+
 // Token in lexical analysis is the smallest unit
 // of meaning in a language.
 type Token struct {
 	// ID is the token ID.
-	ID TokenID
+	ID TokenID `json:"token"`
 	// Lexeme is the token lexeme.
-	Lexeme Lexeme
+	Lexeme Lexeme `json:"lexeme"`
+	// Pos is the byte offset of the token in the input, starting at 0.
+	Pos int `json:"pos"`
+	// Line is the line of the token in the input, starting at 1.
+	Line int `json:"line"`
+	// Col is the column of the token in its line, in characters, starting at 1.
+	Col int `json:"col"`
+	// Number is the kind of the numeric literal, NumberKindNone for other tokens.
+	Number NumberKind `json:"number,omitempty"`
+}
+
+// IsStatementEnd returns true if the token is the semicolon added by the parser after
+// the last token of a statement which omits it. It has no lexeme, unlike a semicolon
+// written in the statement.
+func IsStatementEnd(t Token) bool {
+	return t.ID == TokenIDSemicolon && t.Lexeme == ""
+}
+
+// TokenAfter returns a token located right after t, e.g. the implicit semicolon
+// terminating a statement.
+func TokenAfter(t Token, id TokenID, lexeme Lexeme) Token {
+	line, col := t.Line, t.Col
+	if line == 0 {
+		line, col = 1, 1
+	} else {
+		col += utf8.RuneCountInString(t.Lexeme.String())
+	}
+	return Token{ID: id, Lexeme: lexeme, Pos: t.Pos + len(t.Lexeme), Line: line, Col: col}
 }
 
 // StripSpaces strips spaces from tokens.
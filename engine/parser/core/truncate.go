@@ -1,20 +1,18 @@
-package postgres
-
-import "github.com/nao1215/aiondb/engine/parser/core"
+package core
 
 // parseTruncate parses a TRUNCATE statement.
-func (p *Parser) parseTruncate() (*core.Statement, error) {
-	stmt := &core.Statement{}
+func (p *Parser) parseTruncate() (*Statement, error) {
+	stmt := &Statement{}
 
 	// Set TRUNCATE decl
-	trDecl, err := p.consumeToken(core.TokenIDTruncate)
+	trDecl, err := p.ConsumeToken(TokenIDTruncate)
 	if err != nil {
 		return nil, err
 	}
 	stmt.Decls = append(stmt.Decls, trDecl)
 
 	// Should be a table name
-	nameDecl, err := p.parseQuotedToken()
+	nameDecl, err := p.ParseQuotedToken()
 	if err != nil {
 		return nil, err
 	}
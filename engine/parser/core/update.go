@@ -0,0 +1,57 @@
+package core
+
+// parseUpdate parses an UPDATE statement.
+func (p *Parser) parseUpdate() (*Statement, error) {
+	stmt := &Statement{}
+
+	// Set UPDATE decl
+	updateDecl, err := p.ConsumeToken(TokenIDUpdate)
+	if err != nil {
+		return nil, err
+	}
+	stmt.Decls = append(stmt.Decls, updateDecl)
+
+	// should be table name
+	nameDecl, err := p.ParseQuotedToken()
+	if err != nil {
+		return nil, err
+	}
+	updateDecl.Append(nameDecl)
+
+	// should be SET
+	setDecl, err := p.ConsumeToken(TokenIDSet)
+	if err != nil {
+		return nil, err
+	}
+	updateDecl.Append(setDecl)
+
+	// should be a list of equality separated by commas
+	for {
+		attributeDecl, err := p.parseAttribution()
+		if err != nil {
+			return nil, err
+		}
+		setDecl.Append(attributeDecl)
+
+		if !p.Is(TokenIDComma) {
+			break
+		}
+		if _, err := p.ConsumeToken(TokenIDComma); err != nil {
+			return nil, err
+		}
+	}
+
+	// MAY be WHERE here, otherwise all rows are updated
+	if !p.Is(TokenIDWhere) {
+		if p.HasNext() {
+			return nil, p.SyntaxError()
+		}
+		return stmt, nil
+	}
+
+	err = p.parseWhere(updateDecl)
+	if err != nil {
+		return nil, err
+	}
+	return stmt, nil
+}
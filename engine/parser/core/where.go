@@ -0,0 +1,192 @@
+package core
+
+// parseWhere parses the WHERE clause. The conditions of the dialect which are not
+// conditions of the WHERE clause (e.g. ROWNUM <= 10) are appended to the statement.
+func (p *Parser) parseWhere(stmtDecl *Decl) error {
+	// May be WHERE  here
+	// Can be ORDER BY if WHERE cause if implicit
+	whereDecl, err := p.ConsumeToken(TokenIDWhere)
+	if err != nil {
+		return err
+	}
+	stmtDecl.Append(whereDecl)
+
+	// Now should be a list of: Attribute and Operator and Value
+	gotClause, gotStmtCondition := false, false
+	for {
+		if !p.HasNext() && gotClause {
+			break
+		}
+		if p.Is(TokenIDOrder, TokenIDLimit, TokenIDOffset, TokenIDFetch, TokenIDFor, TokenIDSemicolon) {
+			break
+		}
+		ok, err := p.parseStmtCondition(stmtDecl)
+		if err != nil {
+			return err
+		}
+		if ok {
+			gotStmtCondition = true
+		} else {
+			attributeDecl, err := p.parseCondition()
+			if err != nil {
+				return err
+			}
+			whereDecl.Append(attributeDecl)
+		}
+
+		if p.Is(TokenIDAnd) {
+			linkDecl, err := p.ConsumeToken(p.Current().ID)
+			if err != nil {
+				return err
+			}
+			// There is no link around a condition appended to the statement
+			if n := len(whereDecl.DeclList); n > 0 && whereDecl.DeclList[n-1].TokenID != TokenIDAnd {
+				whereDecl.Append(linkDecl)
+			}
+		}
+		// Got at least one clause
+		gotClause = true
+	}
+
+	if !gotStmtCondition {
+		return nil
+	}
+	if n := len(whereDecl.DeclList); n > 0 && whereDecl.DeclList[n-1].TokenID == TokenIDAnd {
+		whereDecl.DeclList = whereDecl.DeclList[:n-1]
+	}
+	// Only conditions appended to the statement, all rows match
+	if len(whereDecl.DeclList) == 0 {
+		whereDecl.Append(NewDecl(Token{ID: TokenIDNumber, Lexeme: "1", Number: NumberKindInteger}))
+	}
+	return nil
+}
+
+// parseStmtCondition parses a condition of the dialect, see Dialect.ParseStmtCondition.
+func (p *Parser) parseStmtCondition(stmtDecl *Decl) (bool, error) {
+	if p.dialect.ParseStmtCondition == nil {
+		return false, nil
+	}
+	return p.dialect.ParseStmtCondition(p, stmtDecl)
+}
+
+// parseCondition
+func (p *Parser) parseCondition() (*Decl, error) {
+	// Optionnaly, brackets
+
+	// We may have the WHERE 1 condition
+	if t := p.Current(); t.ID == TokenIDNumber && t.Lexeme == "1" {
+		attributeDecl := NewDecl(t)
+		if err := p.Next(); err != nil {
+			return nil, err
+		}
+
+		// in case of 1 = 1
+		if p.Current().ID == TokenIDEquality {
+			t, err := p.IsNext(TokenIDNumber)
+			if err == nil && t.Lexeme == "1" {
+				if _, err := p.ConsumeToken(TokenIDEquality); err != nil {
+					return nil, err
+				}
+				if _, err := p.ConsumeToken(TokenIDNumber); err != nil {
+					return nil, err
+				}
+			}
+		}
+		return attributeDecl, nil
+	}
+
+	// do we have brackets ?
+	hasBracket := false
+	if p.Is(TokenIDBracketOpening) {
+		if _, err := p.ConsumeToken(TokenIDBracketOpening); err != nil {
+			return nil, err
+		}
+		hasBracket = true
+	}
+
+	// Attribute
+	attributeDecl, err := p.ParseAttribute()
+	if err != nil {
+		return nil, err
+	}
+
+	switch p.Current().ID {
+	case TokenIDEquality, TokenIDDistinctness, TokenIDLeftDiple, TokenIDRightDiple, TokenIDLessOrEqual, TokenIDGreaterOrEqual:
+		decl, err := p.ConsumeToken(p.Current().ID)
+		if err != nil {
+			return nil, err
+		}
+		attributeDecl.Append(decl)
+	case TokenIDIn:
+		inDecl, err := p.parseIn()
+		if err != nil {
+			return nil, err
+		}
+		attributeDecl.Append(inDecl)
+		return attributeDecl, nil
+	case TokenIDNot:
+		notDecl, err := p.ConsumeToken(p.Current().ID)
+		if err != nil {
+			return nil, err
+		}
+
+		if p.Current().ID != TokenIDIn {
+			return nil, p.SyntaxError(TokenIDIn)
+		}
+
+		inDecl, err := p.parseIn()
+		if err != nil {
+			return nil, err
+		}
+		notDecl.Append(inDecl)
+		attributeDecl.Append(notDecl)
+		return attributeDecl, nil
+	case TokenIDIs:
+		decl, err := p.ConsumeToken(TokenIDIs)
+		if err != nil {
+			return nil, err
+		}
+		attributeDecl.Append(decl)
+		if p.Current().ID == TokenIDNot {
+			notDecl, err := p.ConsumeToken(TokenIDNot)
+			if err != nil {
+				return nil, err
+			}
+			decl.Append(notDecl)
+		}
+		if p.Current().ID == TokenIDNull {
+			nullDecl, err := p.ConsumeToken(TokenIDNull)
+			if err != nil {
+				return nil, err
+			}
+			decl.Append(nullDecl)
+		}
+		return attributeDecl, nil
+	default:
+	}
+
+	// Value
+	valueDecl, err := p.ParseValue()
+	if err != nil {
+		return nil, err
+	}
+	attributeDecl.Append(valueDecl)
+
+	// The value may be compared with a collating sequence, e.g. name = 'a' COLLATE NOCASE
+	if p.Is(TokenIDCollate) {
+		collateDecl, err := p.parseCollate()
+		if err != nil {
+			return nil, err
+		}
+		if collateDecl != nil {
+			attributeDecl.Append(collateDecl)
+		}
+	}
+
+	if hasBracket {
+		if _, err = p.ConsumeToken(TokenIDBracketClosing); err != nil {
+			return nil, err
+		}
+	}
+	return attributeDecl, nil
+}
@@ -2,7 +2,10 @@ package parser
 
 import (
 	"github.com/nao1215/aiondb/engine/parser/core"
+	"github.com/nao1215/aiondb/engine/parser/mysql"
+	"github.com/nao1215/aiondb/engine/parser/oracle"
 	"github.com/nao1215/aiondb/engine/parser/postgres"
+	"github.com/nao1215/aiondb/engine/parser/sqlite"
 )
 
 // Lexer is an lex interface.
@@ -13,6 +16,12 @@ type Lexer interface {
 // NewLexer returns a new Lexer.
 func NewLexer(input string, mode core.SQLSyntaxMode) Lexer {
 	switch mode {
+	case core.SQLSyntaxModeMySQL:
+		return mysql.NewLexer(input)
+	case core.SQLSyntaxModeOracle:
+		return oracle.NewLexer(input)
+	case core.SQLSyntaxModeSQLite:
+		return sqlite.NewLexer(input)
 	case core.SQLSyntaxModePostgreSQL:
 		return postgres.NewLexer(input)
 	}
@@ -0,0 +1,75 @@
+package mysql
+
+import (
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// parseTableOptions parses the table options following the table definition, e.g.
+// ENGINE=InnoDB AUTO_INCREMENT=1 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin COMMENT='users'
+// Table options are accepted and ignored, the engine has a single storage.
+func parseTableOptions(p *core.Parser) error {
+	for p.IsNot(core.TokenIDSemicolon) {
+		if p.Is(core.TokenIDDefault) {
+			if err := p.Next(); err != nil {
+				return err
+			}
+		}
+
+		// CHARACTER SET is the only option name made of two words
+		if p.IsWord("character") {
+			if _, err := p.IsNext(core.TokenIDSet); err != nil {
+				return err
+			}
+			if err := p.Next(); err != nil {
+				return err
+			}
+		}
+		if _, err := p.ConsumeToken(core.TokenIDString, core.TokenIDAutoincrement, core.TokenIDCollate, core.TokenIDSet); err != nil {
+			return err
+		}
+
+		if p.Is(core.TokenIDEquality) {
+			if err := p.Next(); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		if p.Is(core.TokenIDSingleQuote, core.TokenIDDoubleQuote) {
+			_, err = p.ParseStringLiteral()
+		} else {
+			_, err = p.ConsumeToken(core.TokenIDString, core.TokenIDNumber, core.TokenIDDefault)
+		}
+		if err != nil {
+			return err
+		}
+
+		if p.Is(core.TokenIDComma) {
+			if err := p.Next(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// parseComment parses a 'comment' column option. Comments are accepted and ignored.
+func parseComment(p *core.Parser, _ *core.Decl) (bool, error) {
+	if _, err := p.ConsumeWord("comment"); err != nil {
+		return false, nil
+	}
+	_, err := p.ParseStringLiteral()
+	return true, err
+}
+
+// parseCollate parses a 'collate' column option. Collations are ignored, strings are
+// compared byte by byte, so no declaration is returned.
+func parseCollate(p *core.Parser) (*core.Decl, error) {
+	if _, err := p.ConsumeToken(core.TokenIDCollate); err != nil {
+		return nil, err
+	}
+	if _, err := p.ConsumeToken(core.TokenIDString, core.TokenIDNocase); err != nil {
+		return nil, err
+	}
+	return nil, nil
+}
@@ -0,0 +1,77 @@
+package mysql
+
+import (
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// parseOnDuplicateKeyUpdate parses the ON DUPLICATE KEY UPDATE clause of an INSERT statement.
+// The assignments have the same form as the SET clause of an UPDATE statement, and
+// VALUES(column) refers to the value the INSERT statement would have inserted.
+func parseOnDuplicateKeyUpdate(p *core.Parser) (*core.Decl, error) {
+	onDecl, err := p.ConsumeToken(core.TokenIDOn)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.ConsumeWord("duplicate"); err != nil {
+		return nil, err
+	}
+	if _, err := p.ConsumeToken(core.TokenIDKey); err != nil {
+		return nil, err
+	}
+	if _, err := p.ConsumeToken(core.TokenIDUpdate); err != nil {
+		return nil, err
+	}
+
+	for {
+		attributeDecl, err := p.ParseAttribute()
+		if err != nil {
+			return nil, err
+		}
+		onDecl.Append(attributeDecl)
+
+		equalityDecl, err := p.ConsumeToken(core.TokenIDEquality)
+		if err != nil {
+			return nil, err
+		}
+		attributeDecl.Append(equalityDecl)
+
+		var valueDecl *core.Decl
+		if p.Is(core.TokenIDValues) {
+			valueDecl, err = parseValuesFunc(p)
+		} else {
+			valueDecl, err = p.ParseListElement()
+		}
+		if err != nil {
+			return nil, err
+		}
+		attributeDecl.Append(valueDecl)
+
+		if !p.Is(core.TokenIDComma) {
+			break
+		}
+		if _, err := p.ConsumeToken(core.TokenIDComma); err != nil {
+			return nil, err
+		}
+	}
+	return onDecl, nil
+}
+
+// parseValuesFunc parses VALUES(column).
+func parseValuesFunc(p *core.Parser) (*core.Decl, error) {
+	valuesDecl, err := p.ConsumeToken(core.TokenIDValues)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := p.ConsumeToken(core.TokenIDBracketOpening); err != nil {
+		return nil, err
+	}
+	columnDecl, err := p.ParseQuotedToken()
+	if err != nil {
+		return nil, err
+	}
+	valuesDecl.Append(columnDecl)
+	if _, err := p.ConsumeToken(core.TokenIDBracketClosing); err != nil {
+		return nil, err
+	}
+	return valuesDecl, nil
+}
@@ -0,0 +1,27 @@
+package mysql
+
+import (
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// NewLexer returns a new lexer for MySQL.
+func NewLexer(input string) *core.Lexer {
+	return core.NewLexer(input, newMatchers)
+}
+
+// newMatchers returns the matchers of the MySQL lexer. MySQL double quoted strings are
+// string literals, they are unescaped like single quoted ones (e.g. 'it\'s'). Backtick
+// quoted identifiers may contain any character, a backtick is written as two backticks.
+func newMatchers(l *core.Lex) core.Matchers {
+	return core.Matchers{
+		l.MatchSpace,
+		func() bool { return l.MatchWord(keywords) },
+		func() bool { return l.MatchQuoted('\'', '\'', core.TokenIDSingleQuote, unescape) },
+		func() bool { return l.MatchQuoted('"', '"', core.TokenIDDoubleQuote, unescape) },
+		func() bool { return l.MatchQuoted('`', '`', core.TokenIDBacktick, nil) },
+		l.MatchDate,
+		l.MatchNumber,
+		l.MatchString,
+		l.MatchOperator,
+	}
+}
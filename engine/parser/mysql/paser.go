@@ -0,0 +1,55 @@
+// Package mysql parses SQL queries with MySQL syntax.
+package mysql
+
+import (
+	"strings"
+
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// dialect is what MySQL adds to the grammar shared by the parsers.
+var dialect = core.Dialect{
+	Matchers:          newMatchers,
+	LimitOffsetCount:  true,
+	ParseType:         parseType,
+	ParseCollate:      parseCollate,
+	ParseColumnOption: parseComment,
+	ParseTableOptions: parseTableOptions,
+	ParseInsertOn:     parseOnDuplicateKeyUpdate,
+}
+
+// NewParser returns a new parser of SQL queries conforming to MySQL.
+// It satisfies the Parser interface of the parser package.
+func NewParser() *core.Parser {
+	return core.NewParser(dialect)
+}
+
+// parseType parse a type of the form
+// int
+// varchar(255)
+// decimal(10, 2)
+// int(11) unsigned
+// The UNSIGNED, SIGNED and ZEROFILL attributes are accepted and ignored.
+func parseType(p *core.Parser) (*core.Decl, error) {
+	typeDecl, err := p.ParseType()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.Is(core.TokenIDString) && isTypeAttribute(p.Current().Lexeme) {
+		if err := p.Next(); err != nil {
+			return nil, err
+		}
+	}
+	return typeDecl, nil
+}
+
+// isTypeAttribute returns true if the lexeme is an attribute of numeric types.
+func isTypeAttribute(l core.Lexeme) bool {
+	switch strings.ToLower(l.String()) {
+	case "unsigned", "signed", "zerofill":
+		return true
+	default:
+		return false
+	}
+}
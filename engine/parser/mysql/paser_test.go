@@ -0,0 +1,212 @@
+package mysql
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/nao1215/aiondb/engine/parser/core"
+	"github.com/nao1215/aiondb/engine/parser/postgres"
+)
+
+func TestParserSameDeclsAsPostgres(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		mysql    string
+		postgres string
+	}{
+		{
+			name:     "Backtick identifiers",
+			mysql:    "SELECT `u`.`id`, `name` FROM `users` WHERE `id` = 1",
+			postgres: `SELECT "u"."id", "name" FROM "users" WHERE "id" = 1`,
+		},
+		{
+			name:     "Backtick identifiers which are keywords",
+			mysql:    "UPDATE `order` SET `key` = 'a', `time` = 1 WHERE `from` = 2",
+			postgres: `UPDATE "order" SET "key" = 'a', "time" = 1 WHERE "from" = 2`,
+		},
+		{
+			name:     "LIMIT offset, count",
+			mysql:    "SELECT * FROM users ORDER BY id DESC LIMIT 20, 10",
+			postgres: "SELECT * FROM users ORDER BY id DESC LIMIT 10 OFFSET 20",
+		},
+		{
+			name:     "LIMIT count OFFSET offset",
+			mysql:    "SELECT * FROM users LIMIT 10 OFFSET 20",
+			postgres: "SELECT * FROM users LIMIT 10 OFFSET 20",
+		},
+		{
+			name:     "AUTO_INCREMENT column and table options",
+			mysql:    "CREATE TABLE IF NOT EXISTS `users` (`id` INT(11) UNSIGNED NOT NULL AUTO_INCREMENT, `email` VARCHAR(255) NULL UNIQUE KEY COMMENT 'login', PRIMARY KEY (`id`)) ENGINE=InnoDB AUTO_INCREMENT=10 DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin COMMENT='users'",
+			postgres: `CREATE TABLE IF NOT EXISTS users (id INT(11) NOT NULL AUTO_INCREMENT, email VARCHAR(255) UNIQUE, PRIMARY KEY (id))`,
+		},
+		{
+			name:     "Table options separated by commas",
+			mysql:    "CREATE TABLE t (a TEXT) ENGINE = MyISAM, CHARACTER SET = latin1",
+			postgres: "CREATE TABLE t (a TEXT)",
+		},
+		{
+			name:     "INSERT with escaped strings",
+			mysql:    `INSERT INTO users (name, note) VALUES ('it\'s', "say ""hi""")`,
+			postgres: `INSERT INTO users (name, note) VALUES ($$it's$$, $$say "hi"$$)`,
+		},
+		{
+			name:     "DELETE",
+			mysql:    "DELETE FROM `users` WHERE `id` IN (1, 2)",
+			postgres: "DELETE FROM users WHERE id IN (1, 2)",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewParser().Parse(tt.mysql)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := postgres.NewParser().Parse(tt.postgres)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParserOnDuplicateKeyUpdate(t *testing.T) {
+	t.Parallel()
+
+	stmts, err := NewParser().Parse("INSERT INTO t (id, name) VALUES (1, 'a') ON DUPLICATE KEY UPDATE name = VALUES(`name`), n = 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	onDecl := stmts[0].Decls[0].DeclList[2]
+	want := &core.Decl{
+		TokenID: core.TokenIDOn,
+		Lexeme:  "on",
+		DeclList: []*core.Decl{
+			{
+				TokenID: core.TokenIDString,
+				Lexeme:  "name",
+				DeclList: []*core.Decl{
+					{TokenID: core.TokenIDEquality, Lexeme: "="},
+					{
+						TokenID:  core.TokenIDValues,
+						Lexeme:   "values",
+						DeclList: []*core.Decl{{TokenID: core.TokenIDString, Lexeme: "name"}},
+					},
+				},
+			},
+			{
+				TokenID: core.TokenIDString,
+				Lexeme:  "n",
+				DeclList: []*core.Decl{
+					{TokenID: core.TokenIDEquality, Lexeme: "="},
+					{TokenID: core.TokenIDNumber, Lexeme: "2", Number: core.NumberKindInteger},
+				},
+			},
+		},
+	}
+	if diff := cmp.Diff(want, onDecl); diff != "" {
+		t.Errorf("value is mismatch (-want +got):\n%s", diff)
+	}
+
+	for _, query := range []string{
+		"INSERT INTO t (id) VALUES (1) ON DUPLICATE UPDATE id = 2",
+		"INSERT INTO t (id) VALUES (1) ON CONFLICT KEY UPDATE id = 2",
+		"INSERT INTO t (id) VALUES (1) ON DUPLICATE KEY UPDATE id",
+	} {
+		if _, err := NewParser().Parse(query); err == nil {
+			t.Errorf("expect error for %q, however no error", query)
+		}
+	}
+}
+
+func TestLexerStrings(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  []core.Token
+	}{
+		{
+			name:  "Backslash escapes",
+			input: `'a\'b\"c\\d\ne\tf\0g\%h\_i\qj'`,
+			want: []core.Token{
+				{ID: core.TokenIDSingleQuote, Lexeme: "'"},
+				{ID: core.TokenIDString, Lexeme: "a'b\"c\\d\ne\tf\x00g\\%h\\_iqj"},
+				{ID: core.TokenIDSingleQuote, Lexeme: "'"},
+			},
+		},
+		{
+			name:  "Doubled quotes",
+			input: `'it''s' "a""b"`,
+			want: []core.Token{
+				{ID: core.TokenIDSingleQuote, Lexeme: "'"},
+				{ID: core.TokenIDString, Lexeme: "it's"},
+				{ID: core.TokenIDSingleQuote, Lexeme: "'"},
+				{ID: core.TokenIDSpace, Lexeme: " "},
+				{ID: core.TokenIDDoubleQuote, Lexeme: `"`},
+				{ID: core.TokenIDString, Lexeme: `a"b`},
+				{ID: core.TokenIDDoubleQuote, Lexeme: `"`},
+			},
+		},
+		{
+			name:  "Backtick identifier without escapes",
+			input: "`a``b\\n c`",
+			want: []core.Token{
+				{ID: core.TokenIDBacktick, Lexeme: "`"},
+				{ID: core.TokenIDString, Lexeme: "a`b\\n c"},
+				{ID: core.TokenIDBacktick, Lexeme: "`"},
+			},
+		},
+		{
+			name:  "Empty string",
+			input: "''",
+			want: []core.Token{
+				{ID: core.TokenIDSingleQuote, Lexeme: "'"},
+				{ID: core.TokenIDString, Lexeme: ""},
+				{ID: core.TokenIDSingleQuote, Lexeme: "'"},
+			},
+		},
+		{
+			name:  "Unterminated string",
+			input: "'abc",
+			want: []core.Token{
+				{ID: core.TokenIDSingleQuote, Lexeme: "'"},
+				{ID: core.TokenIDString, Lexeme: "abc"},
+			},
+		},
+		{
+			name:  "Keyword prefix at the end of the input",
+			input: "selec",
+			want: []core.Token{
+				{ID: core.TokenIDString, Lexeme: "selec"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewLexer(tt.input).Lex()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(core.Token{}, "Pos", "Line", "Col")); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package mysql
+
+import (
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// keywords maps the keywords of MySQL in lower case to their token ID.
+var keywords = map[string]core.TokenID{
+	"now()":             core.TokenIDNow,
+	"current_timestamp": core.TokenIDLocalTimestamp,
+	"unique":            core.TokenIDUnique,
+	"localtimestamp":    core.TokenIDLocalTimestamp,
+	"default":           core.TokenIDDefault,
+	"true":              core.TokenIDTrue,
+	"false":             core.TokenIDFalse,
+	"asc":               core.TokenIDAsc,
+	"desc":              core.TokenIDDesc,
+	"and":               core.TokenIDAnd,
+	"or":                core.TokenIDOr,
+	"in":                core.TokenIDIn,
+	"truncate":          core.TokenIDTruncate,
+	"drop":              core.TokenIDDrop,
+	"grant":             core.TokenIDGrant,
+	"is":                core.TokenIDIs,
+	"for":               core.TokenIDFor,
+	"limit":             core.TokenIDLimit,
+	"order":             core.TokenIDOrder,
+	"by":                core.TokenIDBy,
+	"set":               core.TokenIDSet,
+	"update":            core.TokenIDUpdate,
+	"create":            core.TokenIDCreate,
+	"select":            core.TokenIDSelect,
+	"distinct":          core.TokenIDDistinct,
+	"insert":            core.TokenIDInsert,
+	"from":              core.TokenIDFrom,
+	"where":             core.TokenIDWhere,
+	"table":             core.TokenIDTable,
+	"null":              core.TokenIDNull,
+	"if":                core.TokenIDIf,
+	"not":               core.TokenIDNot,
+	"exists":            core.TokenIDExists,
+	"count":             core.TokenIDCount,
+	"delete":            core.TokenIDDelete,
+	"auto_increment":    core.TokenIDAutoincrement,
+	"autoincrement":     core.TokenIDAutoincrement,
+	"primary":           core.TokenIDPrimary,
+	"key":               core.TokenIDKey,
+	"into":              core.TokenIDInto,
+	"values":            core.TokenIDValues,
+	"join":              core.TokenIDJoin,
+	"on":                core.TokenIDOn,
+	"offset":            core.TokenIDOffset,
+	"index":             core.TokenIDIndex,
+	"collate":           core.TokenIDCollate,
+	"nocase":            core.TokenIDNocase,
+	"replace":           core.TokenIDReplace,
+	"ignore":            core.TokenIDIgnore,
+}
+
+func init() { //nolint:gochecknoinits
+	core.RegisterKeywords(core.SQLSyntaxModeMySQL, keywords)
+}
+
+// unescape returns the character of the MySQL escape sequence '\' followed by c.
+// \% and \_ keep their backslash, so that they still match literally in LIKE patterns.
+func unescape(c byte) []byte {
+	switch c {
+	case '0':
+		return []byte{0}
+	case 'b':
+		return []byte{'\b'}
+	case 'n':
+		return []byte{'\n'}
+	case 'r':
+		return []byte{'\r'}
+	case 't':
+		return []byte{'\t'}
+	case 'Z':
+		return []byte{0x1a}
+	case '%', '_':
+		return []byte{'\\', c}
+	default:
+		return []byte{c}
+	}
+}
@@ -0,0 +1,70 @@
+package oracle
+
+import (
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// parseSequence parses the CREATE SEQUENCE statement.
+//
+// The generated AST is as follows:
+//
+//	|-> "SEQUENCE" (SequenceToken)
+//	    |-> sequence name
+//	    |-> "WITH" (WithToken) (optional, START WITH)
+//	        |-> first value
+//	    |-> "BY" (ByToken) (optional, INCREMENT BY)
+//	        |-> increment
+//
+// The other options (e.g. NOCACHE, MAXVALUE n) are ignored.
+func parseSequence(p *core.Parser) (*core.Decl, error) {
+	sequenceDecl, err := p.ConsumeToken(core.TokenIDSequence)
+	if err != nil {
+		return nil, err
+	}
+
+	nameDecl, err := p.ParseQuotedToken()
+	if err != nil {
+		return nil, err
+	}
+	sequenceDecl.Append(nameDecl)
+
+	for p.IsNot(core.TokenIDSemicolon) {
+		switch {
+		case p.IsWord("start"):
+			if _, err := p.ConsumeWord("start"); err != nil {
+				return nil, err
+			}
+			if _, err := p.ConsumeWord("with"); err != nil {
+				return nil, err
+			}
+			withDecl := &core.Decl{TokenID: core.TokenIDWith, Lexeme: "with"}
+			valueDecl, err := p.ConsumeToken(core.TokenIDNumber)
+			if err != nil {
+				return nil, err
+			}
+			withDecl.Append(valueDecl)
+			sequenceDecl.Append(withDecl)
+		case p.IsWord("increment"):
+			if _, err := p.ConsumeWord("increment"); err != nil {
+				return nil, err
+			}
+			byDecl, err := p.ConsumeToken(core.TokenIDBy)
+			if err != nil {
+				return nil, err
+			}
+			valueDecl, err := p.ConsumeToken(core.TokenIDNumber)
+			if err != nil {
+				return nil, err
+			}
+			byDecl.Append(valueDecl)
+			sequenceDecl.Append(byDecl)
+		case p.Is(core.TokenIDString, core.TokenIDNumber, core.TokenIDOrder):
+			if err := p.Next(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, p.SyntaxError()
+		}
+	}
+	return sequenceDecl, nil
+}
@@ -0,0 +1,26 @@
+package oracle
+
+import (
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// NewLexer returns a new lexer for Oracle.
+func NewLexer(input string) *core.Lexer {
+	return core.NewLexer(input, newMatchers)
+}
+
+// newMatchers returns the matchers of the Oracle lexer. A quote is written as two quotes,
+// there are no backslash escapes. Bind variables are written :name or :1.
+func newMatchers(l *core.Lex) core.Matchers {
+	return core.Matchers{
+		l.MatchSpace,
+		func() bool { return l.MatchWord(keywords) },
+		func() bool { return l.MatchQuoted('\'', '\'', core.TokenIDSingleQuote, nil) },
+		func() bool { return l.MatchQuoted('"', '"', core.TokenIDDoubleQuote, nil) },
+		func() bool { return l.MatchParameter(":") },
+		l.MatchDate,
+		l.MatchNumber,
+		l.MatchString,
+		l.MatchOperator,
+	}
+}
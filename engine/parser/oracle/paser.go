@@ -0,0 +1,78 @@
+// Package oracle parses SQL queries with Oracle syntax.
+package oracle
+
+import (
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// dialect is what Oracle adds to the grammar shared by the parsers.
+var dialect = core.Dialect{
+	Matchers:  newMatchers,
+	ParseType: parseType,
+	ParseCreate: map[core.TokenID]func(p *core.Parser) (*core.Decl, error){
+		core.TokenIDSequence: parseSequence,
+	},
+	ParseStmtCondition: parseRownum,
+}
+
+// NewParser returns a new parser of SQL queries conforming to Oracle.
+// It satisfies the Parser interface of the parser package.
+func NewParser() *core.Parser {
+	return core.NewParser(dialect)
+}
+
+// parseType parses a column type with its optional size, precision and scale,
+// e.g. VARCHAR2(20 CHAR), NUMBER(10, 2) or TIMESTAMP(6) WITH TIME ZONE.
+// The length semantics (BYTE or CHAR) is ignored.
+func parseType(p *core.Parser) (*core.Decl, error) {
+	typeDecl, err := p.ConsumeToken(core.TokenIDString)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.Is(core.TokenIDBracketOpening) {
+		if _, err = p.ConsumeToken(core.TokenIDBracketOpening); err != nil {
+			return nil, err
+		}
+		for {
+			sizeDecl, err := p.ConsumeToken(core.TokenIDNumber)
+			if err != nil {
+				return nil, err
+			}
+			typeDecl.Append(sizeDecl)
+
+			if p.IsWord("byte", "char") {
+				if err := p.Next(); err != nil {
+					return nil, err
+				}
+			}
+			if !p.Is(core.TokenIDComma) {
+				break
+			}
+			if _, err := p.ConsumeToken(core.TokenIDComma); err != nil {
+				return nil, err
+			}
+		}
+		if _, err = p.ConsumeToken(core.TokenIDBracketClosing); err != nil {
+			return nil, err
+		}
+	}
+
+	// TIMESTAMP WITH [LOCAL] TIME ZONE
+	if p.IsWord("with") {
+		withDecl := &core.Decl{TokenID: core.TokenIDWith, Lexeme: "with"}
+		for _, word := range []string{"with", "local", "time", "zone"} {
+			if word == "local" && !p.IsWord(word) {
+				continue
+			}
+			if !p.IsWord(word) {
+				return nil, p.SyntaxError()
+			}
+			if err := p.Next(); err != nil {
+				return nil, err
+			}
+		}
+		typeDecl.Append(withDecl)
+	}
+	return typeDecl, nil
+}
@@ -0,0 +1,161 @@
+package oracle
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/nao1215/aiondb/engine/parser/core"
+	"github.com/nao1215/aiondb/engine/parser/postgres"
+)
+
+func TestParserSameDeclsAsPostgres(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		oracle   string
+		postgres string
+	}{
+		{
+			name:     "VARCHAR2 and NUMBER types",
+			oracle:   "CREATE TABLE users (id NUMBER(10) NOT NULL PRIMARY KEY, name VARCHAR2(20 CHAR) NULL, code VARCHAR2(4 BYTE))",
+			postgres: "CREATE TABLE users (id NUMBER(10) NOT NULL PRIMARY KEY, name VARCHAR2(20), code VARCHAR2(4))",
+		},
+		{
+			name:     "FETCH FIRST n ROWS ONLY",
+			oracle:   "SELECT * FROM users ORDER BY id FETCH FIRST 5 ROWS ONLY",
+			postgres: "SELECT * FROM users ORDER BY id LIMIT 5",
+		},
+		{
+			name:     "OFFSET n ROWS FETCH NEXT ROW ONLY",
+			oracle:   "SELECT * FROM users OFFSET 10 ROWS FETCH NEXT ROW ONLY",
+			postgres: "SELECT * FROM users OFFSET 10 LIMIT 1",
+		},
+		{
+			name:     "ROWNUM filtering",
+			oracle:   "SELECT * FROM users WHERE ROWNUM <= 5",
+			postgres: "SELECT * FROM users WHERE 1 LIMIT 5",
+		},
+		{
+			name:     "ROWNUM filtering with other predicates",
+			oracle:   "SELECT * FROM users WHERE id > 1 AND ROWNUM < 5 AND name = 'a'",
+			postgres: "SELECT * FROM users WHERE id > 1 AND name = 'a' LIMIT 4",
+		},
+		{
+			name:     "FROM DUAL",
+			oracle:   "SELECT * FROM DUAL",
+			postgres: "SELECT * FROM DUAL",
+		},
+		{
+			name:     "Quoted identifiers and doubled quotes",
+			oracle:   `INSERT INTO "users" ("name") VALUES ('it''s')`,
+			postgres: `INSERT INTO users (name) VALUES ($$it's$$)`,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewParser().Parse(tt.oracle)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want, err := postgres.NewParser().Parse(tt.postgres)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParserSequence(t *testing.T) {
+	t.Parallel()
+
+	stmts, err := NewParser().Parse("CREATE SEQUENCE seq START WITH 10 INCREMENT BY 5 NOCACHE NOCYCLE")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &core.Decl{
+		TokenID: core.TokenIDSequence,
+		Lexeme:  "sequence",
+		DeclList: []*core.Decl{
+			{TokenID: core.TokenIDString, Lexeme: "seq"},
+			{TokenID: core.TokenIDWith, Lexeme: "with", DeclList: []*core.Decl{{TokenID: core.TokenIDNumber, Lexeme: "10", Number: core.NumberKindInteger}}},
+			{TokenID: core.TokenIDBy, Lexeme: "by", DeclList: []*core.Decl{{TokenID: core.TokenIDNumber, Lexeme: "5", Number: core.NumberKindInteger}}},
+		},
+	}
+	if diff := cmp.Diff(want, stmts[0].Decls[0].DeclList[0]); diff != "" {
+		t.Errorf("value is mismatch (-want +got):\n%s", diff)
+	}
+
+	stmts, err = NewParser().Parse("INSERT INTO users (id, name) VALUES (seq.NEXTVAL, :name)")
+	if err != nil {
+		t.Fatal(err)
+	}
+	values := stmts[0].Decls[0].DeclList[1].DeclList[0].DeclList
+	wantValues := []*core.Decl{
+		{TokenID: core.TokenIDNextval, Lexeme: "nextval", DeclList: []*core.Decl{{TokenID: core.TokenIDString, Lexeme: "seq"}}},
+		{TokenID: core.TokenIDParameter, Lexeme: ":name"},
+	}
+	if diff := cmp.Diff(wantValues, values); diff != "" {
+		t.Errorf("value is mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestLexer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  []core.Token
+	}{
+		{
+			name:  "Bind variables",
+			input: ":name :1",
+			want: []core.Token{
+				{ID: core.TokenIDParameter, Lexeme: ":name"},
+				{ID: core.TokenIDSpace, Lexeme: " "},
+				{ID: core.TokenIDParameter, Lexeme: ":1"},
+			},
+		},
+		{
+			name:  "SYSDATE and NVL",
+			input: "sysdate nvl",
+			want: []core.Token{
+				{ID: core.TokenIDNow, Lexeme: "sysdate"},
+				{ID: core.TokenIDSpace, Lexeme: " "},
+				{ID: core.TokenIDCoalesce, Lexeme: "nvl"},
+			},
+		},
+		{
+			name:  "Keyword prefix at the end of the input",
+			input: "selec",
+			want: []core.Token{
+				{ID: core.TokenIDString, Lexeme: "selec"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewLexer(tt.input).Lex()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(core.Token{}, "Pos", "Line", "Col")); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package oracle
+
+import (
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// keywords maps the keywords of Oracle in lower case to their token ID.
+var keywords = map[string]core.TokenID{
+	"systimestamp":   core.TokenIDNow,
+	"sysdate":        core.TokenIDNow,
+	"unique":         core.TokenIDUnique,
+	"localtimestamp": core.TokenIDLocalTimestamp,
+	"default":        core.TokenIDDefault,
+	"true":           core.TokenIDTrue,
+	"false":          core.TokenIDFalse,
+	"asc":            core.TokenIDAsc,
+	"desc":           core.TokenIDDesc,
+	"and":            core.TokenIDAnd,
+	"or":             core.TokenIDOr,
+	"in":             core.TokenIDIn,
+	"truncate":       core.TokenIDTruncate,
+	"drop":           core.TokenIDDrop,
+	"grant":          core.TokenIDGrant,
+	"is":             core.TokenIDIs,
+	"for":            core.TokenIDFor,
+	"order":          core.TokenIDOrder,
+	"by":             core.TokenIDBy,
+	"set":            core.TokenIDSet,
+	"update":         core.TokenIDUpdate,
+	"create":         core.TokenIDCreate,
+	"select":         core.TokenIDSelect,
+	"distinct":       core.TokenIDDistinct,
+	"insert":         core.TokenIDInsert,
+	"from":           core.TokenIDFrom,
+	"where":          core.TokenIDWhere,
+	"table":          core.TokenIDTable,
+	"null":           core.TokenIDNull,
+	"if":             core.TokenIDIf,
+	"not":            core.TokenIDNot,
+	"exists":         core.TokenIDExists,
+	"count":          core.TokenIDCount,
+	"delete":         core.TokenIDDelete,
+	"primary":        core.TokenIDPrimary,
+	"key":            core.TokenIDKey,
+	"into":           core.TokenIDInto,
+	"values":         core.TokenIDValues,
+	"join":           core.TokenIDJoin,
+	"on":             core.TokenIDOn,
+	"offset":         core.TokenIDOffset,
+	"index":          core.TokenIDIndex,
+	"fetch":          core.TokenIDFetch,
+	"sequence":       core.TokenIDSequence,
+	"nextval":        core.TokenIDNextval,
+	"currval":        core.TokenIDCurrval,
+	"nvl":            core.TokenIDCoalesce,
+}
+
+func init() { //nolint:gochecknoinits
+	core.RegisterKeywords(core.SQLSyntaxModeOracle, keywords)
+}
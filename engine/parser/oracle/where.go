@@ -0,0 +1,57 @@
+package oracle
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// parseRownum parses a condition on the ROWNUM pseudocolumn of the form ROWNUM <= n,
+// ROWNUM < n or ROWNUM = 1, and appends it to the statement as a LIMIT declaration. Like
+// LIMIT, the rows are limited after they are sorted by ORDER BY, whereas Oracle numbers
+// them before. It returns false if the condition is not on ROWNUM.
+func parseRownum(p *core.Parser, stmtDecl *core.Decl) (bool, error) {
+	if !p.IsWord("rownum") {
+		return false, nil
+	}
+	if stmtDecl.TokenID != core.TokenIDSelect {
+		return false, errors.New("ROWNUM is only supported in SELECT statements")
+	}
+	if _, err := p.ConsumeWord("rownum"); err != nil {
+		return false, err
+	}
+
+	opDecl, err := p.ConsumeToken(core.TokenIDLessOrEqual, core.TokenIDLeftDiple, core.TokenIDEquality)
+	if err != nil {
+		return false, err
+	}
+	numDecl, err := p.ConsumeToken(core.TokenIDNumber)
+	if err != nil {
+		return false, err
+	}
+	n, err := strconv.Atoi(numDecl.Lexeme.String())
+	if err != nil {
+		return false, fmt.Errorf("wrong ROWNUM value: %w", err)
+	}
+
+	switch opDecl.TokenID {
+	case core.TokenIDLeftDiple:
+		n--
+	case core.TokenIDEquality:
+		// ROWNUM = n is false for all rows, unless n is 1
+		if n != 1 {
+			n = 0
+		}
+	default:
+	}
+	if n < 0 {
+		n = 0
+	}
+
+	limitDecl := core.NewDecl(core.Token{ID: core.TokenIDLimit, Lexeme: "limit"})
+	limitDecl.Append(core.NewDecl(core.Token{ID: core.TokenIDNumber, Lexeme: core.Lexeme(strconv.Itoa(n)), Number: core.NumberKindInteger}))
+	stmtDecl.Append(limitDecl)
+	return true, nil
+}
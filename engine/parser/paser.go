@@ -5,18 +5,30 @@ package parser
 
 import (
 	"github.com/nao1215/aiondb/engine/parser/core"
+	"github.com/nao1215/aiondb/engine/parser/mysql"
+	"github.com/nao1215/aiondb/engine/parser/oracle"
 	"github.com/nao1215/aiondb/engine/parser/postgres"
+	"github.com/nao1215/aiondb/engine/parser/sqlite"
 )
 
 // Parser is an interface introduced to comprehensively
 // parse the SQL syntax of common RDBMS (e.g. MySQL, SQLite, PostgreSQL, Oracle).
 type Parser interface {
+	// Parse returns the declaration trees of the statements.
 	Parse(input string) ([]core.Statement, error)
+	// ParseAST returns the typed AST of the statements, converted from their declaration trees.
+	ParseAST(input string) ([]core.Stmt, error)
 }
 
 // NewParser returns a new Parser.
 func NewParser(mode core.SQLSyntaxMode) Parser {
 	switch mode {
+	case core.SQLSyntaxModeMySQL:
+		return mysql.NewParser()
+	case core.SQLSyntaxModeOracle:
+		return oracle.NewParser()
+	case core.SQLSyntaxModeSQLite:
+		return sqlite.NewParser()
 	case core.SQLSyntaxModePostgreSQL:
 		return postgres.NewParser()
 	}
@@ -0,0 +1,277 @@
+package parser
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+func TestFormat(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		mode  core.SQLSyntaxMode
+		input string
+		want  string
+	}{
+		{
+			name:  "SELECT is normalized",
+			mode:  core.SQLSyntaxModePostgreSQL,
+			input: "select  id,users.name from users where id>1 and name='alice'  order by id desc limit 10 offset 5;",
+			want:  "SELECT id, users.name FROM users WHERE id > 1 AND name = 'alice' ORDER BY id DESC LIMIT 10 OFFSET 5",
+		},
+		{
+			name:  "SELECT with JOIN, IN and IS NULL",
+			mode:  core.SQLSyntaxModePostgreSQL,
+			input: "SELECT COUNT(*) FROM users JOIN groups ON users.gid = groups.id WHERE id NOT IN (1, 2) AND name IS NOT NULL FOR UPDATE",
+			want:  "SELECT COUNT(*) FROM users JOIN groups ON users.gid = groups.id WHERE id NOT IN (1, 2) AND name IS NOT NULL FOR UPDATE",
+		},
+		{
+			name:  "SELECT DISTINCT ON",
+			mode:  core.SQLSyntaxModePostgreSQL,
+			input: "SELECT DISTINCT ON (name) name, id FROM users",
+			want:  "SELECT DISTINCT ON (name) name, id FROM users",
+		},
+		{
+			name:  "keywords and special characters are quoted",
+			mode:  core.SQLSyntaxModePostgreSQL,
+			input: `SELECT "order", "first name" FROM users`,
+			want:  `SELECT "order", "first name" FROM users`,
+		},
+		{
+			name:  "PostgreSQL strings with quotes are dollar quoted",
+			mode:  core.SQLSyntaxModePostgreSQL,
+			input: "INSERT INTO users (id, name) VALUES (1, $$it's$$), (2, DEFAULT) RETURNING id",
+			want:  "INSERT INTO users (id, name) VALUES (1, 'it''s'), (2, DEFAULT) RETURNING id",
+		},
+		{
+			name:  "UPDATE",
+			mode:  core.SQLSyntaxModePostgreSQL,
+			input: "update users set name = 'bob', age = 20 where id = 1",
+			want:  "UPDATE users SET name = 'bob', age = 20 WHERE id = 1",
+		},
+		{
+			name:  "DELETE without WHERE",
+			mode:  core.SQLSyntaxModePostgreSQL,
+			input: "delete from users",
+			want:  "DELETE FROM users",
+		},
+		{
+			name:  "CREATE TABLE",
+			mode:  core.SQLSyntaxModePostgreSQL,
+			input: "create table if not exists public.users (id bigserial primary key, name varchar(20) not null unique, age int default 0, created_at timestamp with time zone)",
+			want:  "CREATE TABLE IF NOT EXISTS public.users (id BIGSERIAL PRIMARY KEY, name VARCHAR(20) NOT NULL UNIQUE, age INT DEFAULT 0, created_at TIMESTAMP WITH TIME ZONE)",
+		},
+		{
+			name:  "CREATE TABLE with a table primary key",
+			mode:  core.SQLSyntaxModePostgreSQL,
+			input: "CREATE TABLE t (a INT, b TEXT, PRIMARY KEY (a, b))",
+			want:  "CREATE TABLE t (a INT, b TEXT, PRIMARY KEY (a, b))",
+		},
+		{
+			name:  "CREATE UNIQUE INDEX",
+			mode:  core.SQLSyntaxModePostgreSQL,
+			input: "create unique index if not exists users_name on users (name, id)",
+			want:  "CREATE UNIQUE INDEX IF NOT EXISTS users_name ON users (name, id)",
+		},
+		{
+			name:  "TRUNCATE",
+			mode:  core.SQLSyntaxModePostgreSQL,
+			input: "truncate users;",
+			want:  "TRUNCATE users",
+		},
+		{
+			name:  "DROP TABLE",
+			mode:  core.SQLSyntaxModePostgreSQL,
+			input: "drop table users",
+			want:  "DROP TABLE users",
+		},
+		{
+			name:  "MySQL identifiers, escapes and ON DUPLICATE KEY UPDATE",
+			mode:  core.SQLSyntaxModeMySQL,
+			input: "INSERT INTO `order` (id, note) VALUES (1, 'it\\'s') ON DUPLICATE KEY UPDATE note = VALUES(note)",
+			want:  "INSERT INTO `order` (id, note) VALUES (1, 'it\\'s') ON DUPLICATE KEY UPDATE note = VALUES(note)",
+		},
+		{
+			name:  "MySQL AUTO_INCREMENT",
+			mode:  core.SQLSyntaxModeMySQL,
+			input: "CREATE TABLE users (id INT NOT NULL AUTO_INCREMENT PRIMARY KEY, name VARCHAR(20))",
+			want:  "CREATE TABLE users (id INT NOT NULL PRIMARY KEY AUTO_INCREMENT, name VARCHAR(20))",
+		},
+		{
+			name:  "SQLite INSERT OR REPLACE and COLLATE NOCASE",
+			mode:  core.SQLSyntaxModeSQLite,
+			input: "INSERT OR REPLACE INTO users (id, name) VALUES (?1, 'it''s')",
+			want:  "INSERT OR REPLACE INTO users (id, name) VALUES (?1, 'it''s')",
+		},
+		{
+			name:  "SQLite column collation",
+			mode:  core.SQLSyntaxModeSQLite,
+			input: "CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT COLLATE NOCASE)",
+			want:  "CREATE TABLE users (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT COLLATE NOCASE)",
+		},
+		{
+			name:  "Oracle FETCH FIRST, NVL and sequences",
+			mode:  core.SQLSyntaxModeOracle,
+			input: "SELECT NVL(name, 'none'), seq.NEXTVAL FROM users OFFSET 5 ROWS FETCH NEXT 10 ROWS ONLY",
+			want:  "SELECT NVL(name, 'none'), seq.NEXTVAL FROM users OFFSET 5 ROWS FETCH FIRST 10 ROWS ONLY",
+		},
+		{
+			name:  "Oracle CREATE SEQUENCE",
+			mode:  core.SQLSyntaxModeOracle,
+			input: "create sequence seq start with 10 increment by 2",
+			want:  "CREATE SEQUENCE seq START WITH 10 INCREMENT BY 2",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			p := NewParser(tt.mode)
+			stmts, err := p.Parse(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := core.Format(stmts[0], tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+
+			// The formatted statement is parsed as the same statement,
+			// except for the case of the type names, which are written in upper case
+			want, err := p.ParseAST(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			reparsed, err := p.ParseAST(got)
+			if err != nil {
+				t.Fatal(err)
+			}
+			typeName := cmp.Comparer(func(x, y core.TypeName) bool {
+				return strings.EqualFold(x.Name, y.Name) && cmp.Equal(x.Args, y.Args) && x.WithTimeZone == y.WithTimeZone
+			})
+			if diff := cmp.Diff(want[:1], reparsed, typeName); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestFormatTranslate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		from    core.SQLSyntaxMode
+		to      core.SQLSyntaxMode
+		input   string
+		want    string
+		wantErr error
+	}{
+		{
+			name:  "MySQL to PostgreSQL",
+			from:  core.SQLSyntaxModeMySQL,
+			to:    core.SQLSyntaxModePostgreSQL,
+			input: "SELECT `key`, name FROM users WHERE name = 'it\\'s' LIMIT 5, 10",
+			want:  `SELECT "key", name FROM users WHERE name = 'it''s' LIMIT 10 OFFSET 5`,
+		},
+		{
+			name:  "PostgreSQL to Oracle",
+			from:  core.SQLSyntaxModePostgreSQL,
+			to:    core.SQLSyntaxModeOracle,
+			input: "SELECT * FROM users LIMIT 10 OFFSET 5",
+			want:  "SELECT * FROM users OFFSET 5 ROWS FETCH FIRST 10 ROWS ONLY",
+		},
+		{
+			name:  "SQLite to MySQL",
+			from:  core.SQLSyntaxModeSQLite,
+			to:    core.SQLSyntaxModeMySQL,
+			input: "INSERT OR IGNORE INTO [order] (id) VALUES (1)",
+			want:  "INSERT IGNORE INTO `order` (id) VALUES (1)",
+		},
+		{
+			name:  "SQLite to PostgreSQL",
+			from:  core.SQLSyntaxModeSQLite,
+			to:    core.SQLSyntaxModePostgreSQL,
+			input: "INSERT OR IGNORE INTO users (id) VALUES (1)",
+			want:  "INSERT INTO users (id) VALUES (1) ON CONFLICT DO NOTHING",
+		},
+		{
+			name:  "PostgreSQL to MySQL quotes the MySQL keywords only",
+			from:  core.SQLSyntaxModePostgreSQL,
+			to:    core.SQLSyntaxModeMySQL,
+			input: `SELECT "zone", "key" FROM users`,
+			want:  "SELECT zone, `key` FROM users",
+		},
+		{
+			name:  "TIMESTAMP WITH TIME ZONE is DATETIME in MySQL",
+			from:  core.SQLSyntaxModePostgreSQL,
+			to:    core.SQLSyntaxModeMySQL,
+			input: "CREATE TABLE events (id INT, at TIMESTAMP WITH TIME ZONE)",
+			want:  "CREATE TABLE events (id INT, at DATETIME)",
+		},
+		{
+			name:    "TIMESTAMP WITH TIME ZONE has no SQLite equivalent",
+			from:    core.SQLSyntaxModePostgreSQL,
+			to:      core.SQLSyntaxModeSQLite,
+			input:   "CREATE TABLE events (id INT, at TIMESTAMP WITH TIME ZONE)",
+			wantErr: core.ErrUnsupportedSyntax,
+		},
+		{
+			name:    "DISTINCT ON is PostgreSQL only",
+			from:    core.SQLSyntaxModePostgreSQL,
+			to:      core.SQLSyntaxModeMySQL,
+			input:   "SELECT DISTINCT ON (name) name FROM users",
+			wantErr: core.ErrUnsupportedSyntax,
+		},
+		{
+			name:    "ON DUPLICATE KEY UPDATE is MySQL only",
+			from:    core.SQLSyntaxModeMySQL,
+			to:      core.SQLSyntaxModeSQLite,
+			input:   "INSERT INTO users (id) VALUES (1) ON DUPLICATE KEY UPDATE id = 2",
+			wantErr: core.ErrUnsupportedSyntax,
+		},
+		{
+			name:    "INSERT OR REPLACE has no PostgreSQL equivalent",
+			from:    core.SQLSyntaxModeSQLite,
+			to:      core.SQLSyntaxModePostgreSQL,
+			input:   "INSERT OR REPLACE INTO users (id) VALUES (1)",
+			wantErr: core.ErrUnsupportedSyntax,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stmts, err := NewParser(tt.from).Parse(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got, err := core.Format(stmts[0], tt.to)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+
+			// The translated statement is parsed by the parser of the target mode
+			if tt.wantErr == nil {
+				if _, err := NewParser(tt.to).Parse(got); err != nil {
+					t.Errorf("failed to parse %q: %v", got, err)
+				}
+			}
+		})
+	}
+}
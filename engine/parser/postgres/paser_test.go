@@ -0,0 +1,540 @@
+package postgres
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+func TestParserParseAST(t *testing.T) {
+	t.Parallel()
+
+	ten := int64(10)
+	five := int64(5)
+	tests := []struct {
+		name  string
+		input string
+		want  core.Stmt
+	}{
+		{
+			name:  "SELECT with WHERE, ORDER BY, LIMIT and OFFSET",
+			input: "SELECT id, users.name FROM users WHERE id > 1 AND name = 'alice' ORDER BY id DESC LIMIT 10 OFFSET 5",
+			want: &core.SelectStmt{
+				Columns: []core.Expr{
+					&core.ColumnRef{Name: "id"},
+					&core.ColumnRef{Table: "users", Name: "name"},
+				},
+				From: []*core.TableRef{{Name: "users"}},
+				Where: &core.BinaryExpr{
+					Op:    core.OpAnd,
+					Left:  &core.BinaryExpr{Op: core.OpGreater, Left: &core.ColumnRef{Name: "id"}, Right: &core.Literal{Kind: core.LiteralNumber, Value: "1", Number: core.NumberKindInteger}},
+					Right: &core.BinaryExpr{Op: core.OpEqual, Left: &core.ColumnRef{Name: "name"}, Right: &core.Literal{Kind: core.LiteralString, Value: "alice"}},
+				},
+				OrderBy: []*core.OrderItem{{Expr: &core.ColumnRef{Name: "id"}, Desc: true}},
+				Limit:   &ten,
+				Offset:  &five,
+			},
+		},
+		{
+			name:  "SELECT COUNT(*) with JOIN, IN and IS NULL",
+			input: "SELECT COUNT(*) FROM users JOIN groups ON users.group_id = groups.id WHERE users.id IN (1, 2) AND groups.name IS NOT NULL",
+			want: &core.SelectStmt{
+				Columns: []core.Expr{&core.FuncCall{Name: "count", Args: []core.Expr{&core.Star{}}}},
+				From:    []*core.TableRef{{Name: "users"}},
+				Joins: []*core.Join{{
+					Table: &core.TableRef{Name: "groups"},
+					On:    &core.BinaryExpr{Op: core.OpEqual, Left: &core.ColumnRef{Table: "users", Name: "group_id"}, Right: &core.ColumnRef{Table: "groups", Name: "id"}},
+				}},
+				Where: &core.BinaryExpr{
+					Op: core.OpAnd,
+					Left: &core.InExpr{
+						Expr: &core.ColumnRef{Table: "users", Name: "id"},
+						List: []core.Expr{&core.Literal{Kind: core.LiteralNumber, Value: "1", Number: core.NumberKindInteger}, &core.Literal{Kind: core.LiteralNumber, Value: "2", Number: core.NumberKindInteger}},
+					},
+					Right: &core.IsNullExpr{Expr: &core.ColumnRef{Table: "groups", Name: "name"}, Not: true},
+				},
+			},
+		},
+		{
+			name:  "SELECT DISTINCT ON",
+			input: "SELECT DISTINCT ON (name) name, id FROM users",
+			want: &core.SelectStmt{
+				Distinct:   true,
+				DistinctOn: []core.Expr{&core.ColumnRef{Name: "name"}},
+				Columns:    []core.Expr{&core.ColumnRef{Name: "name"}, &core.ColumnRef{Name: "id"}},
+				From:       []*core.TableRef{{Name: "users"}},
+			},
+		},
+		{
+			name:  "INSERT with RETURNING",
+			input: "INSERT INTO users (id, name) VALUES (1, 'alice'), (2, DEFAULT) RETURNING id",
+			want: &core.InsertStmt{
+				Table:   &core.TableRef{Name: "users"},
+				Columns: []string{"id", "name"},
+				Rows: [][]core.Expr{
+					{&core.Literal{Kind: core.LiteralNumber, Value: "1", Number: core.NumberKindInteger}, &core.Literal{Kind: core.LiteralString, Value: "alice"}},
+					{&core.Literal{Kind: core.LiteralNumber, Value: "2", Number: core.NumberKindInteger}, &core.Literal{Kind: core.LiteralDefault, Value: "default"}},
+				},
+				Returning: []core.Expr{&core.ColumnRef{Name: "id"}},
+			},
+		},
+		{
+			name:  "UPDATE",
+			input: "UPDATE users SET name = 'bob', age = 20 WHERE id = 1",
+			want: &core.UpdateStmt{
+				Table: &core.TableRef{Name: "users"},
+				Set: []*core.Assignment{
+					{Column: "name", Value: &core.Literal{Kind: core.LiteralString, Value: "bob"}},
+					{Column: "age", Value: &core.Literal{Kind: core.LiteralNumber, Value: "20", Number: core.NumberKindInteger}},
+				},
+				Where: &core.BinaryExpr{Op: core.OpEqual, Left: &core.ColumnRef{Name: "id"}, Right: &core.Literal{Kind: core.LiteralNumber, Value: "1", Number: core.NumberKindInteger}},
+			},
+		},
+		{
+			name:  "DELETE without WHERE",
+			input: "DELETE FROM users",
+			want:  &core.DeleteStmt{Table: &core.TableRef{Name: "users"}},
+		},
+		{
+			name:  "CREATE TABLE",
+			input: "CREATE TABLE IF NOT EXISTS public.users (id BIGSERIAL PRIMARY KEY, name VARCHAR(20) NOT NULL UNIQUE, age INT DEFAULT 0, created_at TIMESTAMP WITH TIME ZONE)",
+			want: &core.CreateTableStmt{
+				Table:       &core.TableRef{Schema: "public", Name: "users"},
+				IfNotExists: true,
+				Columns: []*core.ColumnDef{
+					{Name: "id", Type: &core.TypeName{Name: "BIGSERIAL"}, PrimaryKey: true},
+					{Name: "name", Type: &core.TypeName{Name: "VARCHAR", Args: []string{"20"}}, NotNull: true, Unique: true},
+					{Name: "age", Type: &core.TypeName{Name: "INT"}, Default: &core.Literal{Kind: core.LiteralNumber, Value: "0", Number: core.NumberKindInteger}},
+					{Name: "created_at", Type: &core.TypeName{Name: "TIMESTAMP", WithTimeZone: true}},
+				},
+			},
+		},
+		{
+			name:  "CREATE UNIQUE INDEX",
+			input: "CREATE UNIQUE INDEX users_name ON users (name)",
+			want: &core.CreateIndexStmt{
+				Name:    "users_name",
+				Table:   &core.TableRef{Name: "users"},
+				Unique:  true,
+				Columns: []*core.IndexColumn{{Name: "name"}},
+			},
+		},
+		{
+			name:  "TRUNCATE",
+			input: "TRUNCATE users",
+			want:  &core.TruncateStmt{Table: &core.TableRef{Name: "users"}},
+		},
+		{
+			name:  "DROP TABLE",
+			input: "DROP TABLE users",
+			want:  &core.DropTableStmt{Table: &core.TableRef{Name: "users"}},
+		},
+		{
+			name:  "CREATE TABLE with unreserved keywords as names",
+			input: "CREATE TABLE events (time timestamp, key text, index int, at time)",
+			want: &core.CreateTableStmt{
+				Table: &core.TableRef{Name: "events"},
+				Columns: []*core.ColumnDef{
+					{Name: "time", Type: &core.TypeName{Name: "timestamp"}},
+					{Name: "key", Type: &core.TypeName{Name: "text"}},
+					{Name: "index", Type: &core.TypeName{Name: "int"}},
+					{Name: "at", Type: &core.TypeName{Name: "time"}},
+				},
+			},
+		},
+		{
+			name:  "SELECT with unreserved keywords as names",
+			input: "SELECT events.key, time FROM events WHERE key = 'a' ORDER BY time",
+			want: &core.SelectStmt{
+				Columns: []core.Expr{
+					&core.ColumnRef{Table: "events", Name: "key"},
+					&core.ColumnRef{Name: "time"},
+				},
+				From:    []*core.TableRef{{Name: "events"}},
+				Where:   &core.BinaryExpr{Op: core.OpEqual, Left: &core.ColumnRef{Name: "key"}, Right: &core.Literal{Kind: core.LiteralString, Value: "a"}},
+				OrderBy: []*core.OrderItem{{Expr: &core.ColumnRef{Name: "time"}}},
+			},
+		},
+		{
+			name:  "COUNT without brackets is a name",
+			input: "SELECT count, COUNT(count) FROM events WHERE count = 1",
+			want: &core.SelectStmt{
+				Columns: []core.Expr{
+					&core.ColumnRef{Name: "count"},
+					&core.FuncCall{Name: "count", Args: []core.Expr{&core.ColumnRef{Name: "count"}}},
+				},
+				From:  []*core.TableRef{{Name: "events"}},
+				Where: &core.BinaryExpr{Op: core.OpEqual, Left: &core.ColumnRef{Name: "count"}, Right: &core.Literal{Kind: core.LiteralNumber, Value: "1", Number: core.NumberKindInteger}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewParser().ParseAST(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff([]core.Stmt{tt.want}, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewASTInvalidDecl(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		// mutate breaks the declaration tree of the parsed statement.
+		mutate func(stmt *core.Statement)
+	}{
+		{
+			name:  "SELECT without FROM",
+			input: "SELECT id FROM users",
+			mutate: func(stmt *core.Statement) {
+				stmt.Decls[0].DeclList = stmt.Decls[0].DeclList[:1]
+			},
+		},
+		{
+			name:  "INSERT without VALUES",
+			input: "INSERT INTO users (id) VALUES (1)",
+			mutate: func(stmt *core.Statement) {
+				stmt.Decls[0].DeclList = stmt.Decls[0].DeclList[:1]
+			},
+		},
+		{
+			name:  "TRUNCATE without table",
+			input: "TRUNCATE users",
+			mutate: func(stmt *core.Statement) {
+				stmt.Decls[0].DeclList = nil
+			},
+		},
+		{
+			name:  "empty statement",
+			input: "DELETE FROM users",
+			mutate: func(stmt *core.Statement) {
+				stmt.Decls = nil
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stmts, err := NewParser().Parse(tt.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tt.mutate(&stmts[0])
+			if _, err := core.NewAST(stmts[0]); !errors.Is(err, core.ErrInvalidDecl) {
+				t.Errorf("expected %v, got %v", core.ErrInvalidDecl, err)
+			}
+		})
+	}
+}
+
+func TestLexerPositions(t *testing.T) {
+	t.Parallel()
+
+	got, err := NewLexer("SELECT id\n  FROM \"café\" WHERE id = 1").Lex()
+	if err != nil {
+		t.Fatal(err)
+	}
+	got = core.StripSpaces(got)
+
+	want := []core.Token{
+		{ID: core.TokenIDSelect, Lexeme: "select", Pos: 0, Line: 1, Col: 1},
+		{ID: core.TokenIDString, Lexeme: "id", Pos: 7, Line: 1, Col: 8},
+		{ID: core.TokenIDFrom, Lexeme: "from", Pos: 12, Line: 2, Col: 3},
+		{ID: core.TokenIDDoubleQuote, Lexeme: "\"", Pos: 17, Line: 2, Col: 8},
+		{ID: core.TokenIDString, Lexeme: "café", Pos: 18, Line: 2, Col: 9},
+		{ID: core.TokenIDDoubleQuote, Lexeme: "\"", Pos: 23, Line: 2, Col: 13},
+		{ID: core.TokenIDWhere, Lexeme: "where", Pos: 25, Line: 2, Col: 15},
+		{ID: core.TokenIDString, Lexeme: "id", Pos: 31, Line: 2, Col: 21},
+		{ID: core.TokenIDEquality, Lexeme: "=", Pos: 34, Line: 2, Col: 24},
+		{ID: core.TokenIDNumber, Lexeme: "1", Pos: 36, Line: 2, Col: 26, Number: core.NumberKindInteger},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("value is mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParserSyntaxError(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  *core.SyntaxError
+	}{
+		{
+			name:  "unexpected token",
+			input: "SELECT *\n  FORM users",
+			want: &core.SyntaxError{
+				Pos: 11, Line: 2, Col: 3, Near: "FORM",
+				Expected: []core.TokenID{core.TokenIDFrom}, Err: core.ErrParserSyntax,
+			},
+		},
+		{
+			name:  "missing token",
+			input: "CREATE UNIQUE users",
+			want: &core.SyntaxError{
+				Pos: 14, Line: 1, Col: 15, Near: "users",
+				Expected: []core.TokenID{core.TokenIDIndex}, Err: core.ErrParserSyntax,
+			},
+		},
+		{
+			name:  "unexpected end of input",
+			input: "INSERT INTO users (id) VALUES",
+			want: &core.SyntaxError{
+				Pos: 29, Line: 1, Col: 30, AtEnd: true,
+				Expected: []core.TokenID{core.TokenIDBracketOpening}, Err: core.ErrParserSyntax,
+			},
+		},
+		{
+			name:  "semicolon written in the statement",
+			input: "SELECT 1;",
+			want: &core.SyntaxError{
+				Pos: 8, Line: 1, Col: 9, Near: ";",
+				Expected: []core.TokenID{core.TokenIDFrom}, Err: core.ErrParserSyntax,
+			},
+		},
+		{
+			name:  "reserved keyword as column name",
+			input: "CREATE TABLE t (select int)",
+			want: &core.SyntaxError{
+				Pos: 16, Line: 1, Col: 17, Near: "select", Err: core.ErrParserSyntax,
+			},
+		},
+		{
+			name:  "unknown character",
+			input: "SELECT * FROM users\nWHERE é",
+			want: &core.SyntaxError{
+				Pos: 26, Line: 2, Col: 7, Near: "é", Err: core.ErrLexerSyntax,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := NewParser().Parse(tt.input)
+			var got *core.SyntaxError
+			if !errors.As(err, &got) {
+				t.Fatalf("expect syntax error, got %v", err)
+			}
+			if !errors.Is(err, tt.want.Err) {
+				t.Errorf("expect %v, got %v", tt.want.Err, err)
+			}
+			if diff := cmp.Diff(*tt.want, *got, cmpopts.EquateErrors()); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLexerStrings(t *testing.T) {
+	t.Parallel()
+
+	quoted := func(s string) []core.Token {
+		return []core.Token{
+			{ID: core.TokenIDSingleQuote, Lexeme: "'"},
+			{ID: core.TokenIDString, Lexeme: core.Lexeme(s)},
+			{ID: core.TokenIDSingleQuote, Lexeme: "'"},
+		}
+	}
+	tests := []struct {
+		name  string
+		input string
+		want  []core.Token
+	}{
+		{
+			name:  "Doubled quotes",
+			input: "'O''Brien'''",
+			want:  quoted("O'Brien'"),
+		},
+		{
+			name:  "Escape string",
+			input: `E'it\'s\n\t\\'`,
+			want:  quoted("it's\n\t\\"),
+		},
+		{
+			name:  "Escape string with octal, hexadecimal and unicode characters",
+			input: `e'\101\x42é\U0001F600\q'`,
+			want:  quoted("ABé😀q"),
+		},
+		{
+			name:  "Dollar quoted string",
+			input: "$$it's$$",
+			want:  []core.Token{{ID: core.TokenIDString, Lexeme: "it's"}},
+		},
+		{
+			name:  "Tagged dollar quoted string",
+			input: "$body$a $$ b$body$",
+			want:  []core.Token{{ID: core.TokenIDString, Lexeme: "a $$ b"}},
+		},
+		{
+			name:  "Positional parameter is not a dollar quote",
+			input: "$1=$12",
+			want: []core.Token{
+				{ID: core.TokenIDParameter, Lexeme: "$1"},
+				{ID: core.TokenIDEquality, Lexeme: "="},
+				{ID: core.TokenIDParameter, Lexeme: "$12"},
+			},
+		},
+		{
+			name:  "Line comment",
+			input: "1 -- it's a comment\n",
+			want: []core.Token{
+				{ID: core.TokenIDNumber, Lexeme: "1", Number: core.NumberKindInteger},
+				{ID: core.TokenIDSpace, Lexeme: " "},
+				{ID: core.TokenIDSpace, Lexeme: " "},
+			},
+		},
+		{
+			name:  "Nested block comments",
+			input: "/* a /* b */ c */1",
+			want:  []core.Token{{ID: core.TokenIDNumber, Lexeme: "1", Number: core.NumberKindInteger}},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewLexer(tt.input).Lex()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(core.Token{}, "Pos", "Line", "Col")); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("Unterminated block comment", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := NewLexer("1 /* a").Lex(); !errors.Is(err, core.ErrLexerSyntax) {
+			t.Errorf("expect lexer syntax error, got %v", err)
+		}
+	})
+}
+
+func TestLexerNumbers(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  []core.Token
+	}{
+		{input: "42", want: []core.Token{{ID: core.TokenIDNumber, Lexeme: "42", Number: core.NumberKindInteger}}},
+		{input: "-7", want: []core.Token{{ID: core.TokenIDNumber, Lexeme: "-7", Number: core.NumberKindInteger}}},
+		{input: "1_000_000", want: []core.Token{{ID: core.TokenIDNumber, Lexeme: "1_000_000", Number: core.NumberKindInteger}}},
+		{input: "0x1F", want: []core.Token{{ID: core.TokenIDNumber, Lexeme: "0x1F", Number: core.NumberKindInteger}}},
+		{input: "0o17", want: []core.Token{{ID: core.TokenIDNumber, Lexeme: "0o17", Number: core.NumberKindInteger}}},
+		{input: "0b101", want: []core.Token{{ID: core.TokenIDNumber, Lexeme: "0b101", Number: core.NumberKindInteger}}},
+		{input: "1.5", want: []core.Token{{ID: core.TokenIDNumber, Lexeme: "1.5", Number: core.NumberKindDecimal}}},
+		{input: "-0.25", want: []core.Token{{ID: core.TokenIDNumber, Lexeme: "-0.25", Number: core.NumberKindDecimal}}},
+		{input: "1.5e-3", want: []core.Token{{ID: core.TokenIDNumber, Lexeme: "1.5e-3", Number: core.NumberKindFloat}}},
+		{input: "2E10", want: []core.Token{{ID: core.TokenIDNumber, Lexeme: "2E10", Number: core.NumberKindFloat}}},
+		{input: "$$42$$", want: []core.Token{{ID: core.TokenIDNumber, Lexeme: "42", Number: core.NumberKindInteger}}},
+		{
+			input: "1_",
+			want: []core.Token{
+				{ID: core.TokenIDNumber, Lexeme: "1", Number: core.NumberKindInteger},
+				{ID: core.TokenIDString, Lexeme: "_"},
+			},
+		},
+		{
+			input: "1e",
+			want: []core.Token{
+				{ID: core.TokenIDNumber, Lexeme: "1", Number: core.NumberKindInteger},
+				{ID: core.TokenIDString, Lexeme: "e"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewLexer(tt.input).Lex()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(core.Token{}, "Pos", "Line", "Col")); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLexerKeywords(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		input string
+		want  []core.Token
+	}{
+		{
+			input: "SeLeCt order_id FROM selec",
+			want: []core.Token{
+				{ID: core.TokenIDSelect, Lexeme: "select"},
+				{ID: core.TokenIDSpace, Lexeme: " "},
+				{ID: core.TokenIDString, Lexeme: "order_id"},
+				{ID: core.TokenIDSpace, Lexeme: " "},
+				{ID: core.TokenIDFrom, Lexeme: "from"},
+				{ID: core.TokenIDSpace, Lexeme: " "},
+				{ID: core.TokenIDString, Lexeme: "selec"},
+			},
+		},
+		{
+			input: "in1",
+			want:  []core.Token{{ID: core.TokenIDString, Lexeme: "in1"}},
+		},
+		{
+			input: "NOW()",
+			want:  []core.Token{{ID: core.TokenIDNow, Lexeme: "now()"}},
+		},
+		{
+			input: "now ()",
+			want: []core.Token{
+				{ID: core.TokenIDString, Lexeme: "now"},
+				{ID: core.TokenIDSpace, Lexeme: " "},
+				{ID: core.TokenIDBracketOpening, Lexeme: "("},
+				{ID: core.TokenIDBracketClosing, Lexeme: ")"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.input, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewLexer(tt.input).Lex()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(core.Token{}, "Pos", "Line", "Col")); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
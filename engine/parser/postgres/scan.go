@@ -0,0 +1,147 @@
+package postgres
+
+import (
+	"strings"
+
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// ScanKind is the kind of a part of a statement returned by Scanner.Next.
+type ScanKind int
+
+const (
+	// ScanCode is a byte outside quotes and comments.
+	ScanCode ScanKind = iota
+	// ScanString is a string constant: '...', E'...' or $tag$...$tag$.
+	ScanString
+	// ScanIdentifier is a quoted identifier: "...".
+	ScanIdentifier
+	// ScanComment is a comment: -- up to the end of the line, or /* */ (which may be nested).
+	ScanComment
+)
+
+// Scanner splits PostgreSQL statements into code, string constants, quoted identifiers
+// and comments, so that tools splitting statements or replacing parameters ignore the
+// semicolons and the parameters inside quotes and comments. A statement can be scanned
+// in several parts (e.g. line by line): a quote or a comment open at the end of a part
+// continues in the next one.
+type Scanner struct {
+	// kind is the kind of the open quote or comment, ScanCode if there is none.
+	kind ScanKind
+	// end is the delimiter closing the open quote or comment (e.g. "'" or "$body$").
+	end string
+	// escape is true inside an E'...' string, whose quotes may be escaped with a backslash.
+	escape bool
+	// escaped is true if the last byte scanned in an E'...' string is an escaping backslash.
+	escaped bool
+	// depth is the nesting depth of the open block comment.
+	depth int
+}
+
+// Open returns true if a quote or a comment is open at the end of the scanned text.
+func (s *Scanner) Open() bool {
+	return s.kind != ScanCode
+}
+
+// Next returns the end of the part of the text starting at the index i, and its kind.
+// A part of code is a single byte. Other parts end after their closing delimiter, or at
+// the end of the text if they are not closed yet. The text before i is the text of the
+// statement scanned before, it tells whether a quote follows the E prefix of an escape
+// string or the end of a name.
+func (s *Scanner) Next(text string, i int) (int, ScanKind) {
+	if s.kind == ScanCode {
+		n := s.begin(text, i)
+		if n == 0 {
+			return i + 1, ScanCode
+		}
+		i += n
+	}
+
+	kind := s.kind
+	for ; i < len(text); i++ {
+		switch {
+		case s.escaped:
+			s.escaped = false
+		case s.escape && text[i] == '\\':
+			s.escaped = true
+		case s.end == "*/" && strings.HasPrefix(text[i:], "/*"):
+			s.depth++
+			i++
+		case s.end == "*/" && strings.HasPrefix(text[i:], "*/") && s.depth > 1:
+			s.depth--
+			i++
+		case strings.HasPrefix(text[i:], s.end):
+			if s.end != "\n" {
+				// The end of the line is not part of a line comment
+				i += len(s.end)
+			}
+			*s = Scanner{}
+			return i, kind
+		}
+	}
+	return len(text), kind
+}
+
+// begin opens the quote or the comment starting at the index i of the text, if any,
+// and returns the length of its opening delimiter, 0 if there is none.
+func (s *Scanner) begin(text string, i int) int {
+	switch c := text[i]; {
+	case c == '\'':
+		s.kind, s.end = ScanString, "'"
+		s.escape = IsEscapeStringPrefix(text[:i])
+		return 1
+	case c == '"':
+		s.kind, s.end = ScanIdentifier, `"`
+		return 1
+	case c == '$':
+		tag := DollarTag(text[i:])
+		if tag == "" || (i > 0 && isNameByte(text[i-1])) {
+			return 0
+		}
+		s.kind, s.end = ScanString, tag
+		return len(tag)
+	case c == '-' && strings.HasPrefix(text[i:], "--"):
+		s.kind, s.end = ScanComment, "\n"
+		return 2
+	case c == '/' && strings.HasPrefix(text[i:], "/*"):
+		s.kind, s.end, s.depth = ScanComment, "*/", 1
+		return 2
+	}
+	return 0
+}
+
+// IsEscapeStringPrefix returns true if the text ends with the E prefix of an escape
+// string constant, e.g. the text before the quote of E'it\'s'.
+func IsEscapeStringPrefix(text string) bool {
+	n := len(text)
+	if n == 0 || (text[n-1] != 'E' && text[n-1] != 'e') {
+		return false
+	}
+	return n == 1 || !isNameByte(text[n-2])
+}
+
+// DollarTag returns the dollar quote tag at the beginning of the text
+// (e.g. "$$" or "$body$"), or an empty string if there is none.
+// Parameters such as $1 are not dollar quotes.
+func DollarTag(text string) string {
+	if len(text) == 0 || text[0] != '$' {
+		return ""
+	}
+	for i := 1; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case c == '$':
+			return text[:i+1]
+		case c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z'):
+		case core.IsDigit(c, 10) && i > 1:
+		default:
+			return ""
+		}
+	}
+	return ""
+}
+
+// isNameByte returns true if c may be part of a name.
+func isNameByte(c byte) bool {
+	return c == '_' || core.IsDigit(c, 10) || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z')
+}
@@ -0,0 +1,97 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestScanner(t *testing.T) {
+	t.Parallel()
+
+	// part is a part of a statement returned by Scanner.Next
+	type part struct {
+		Text string
+		Kind ScanKind
+	}
+
+	tests := []struct {
+		name     string
+		parts    []string
+		want     []part
+		wantOpen bool
+	}{
+		{
+			name:  "Strings and identifiers",
+			parts: []string{`a'b''c'E'd\'e'"f"$g$h$g$`},
+			want: []part{
+				{Text: "a", Kind: ScanCode},
+				{Text: "'b'", Kind: ScanString},
+				{Text: "'c'", Kind: ScanString},
+				{Text: "E", Kind: ScanCode},
+				{Text: `'d\'e'`, Kind: ScanString},
+				{Text: `"f"`, Kind: ScanIdentifier},
+				{Text: "$g$h$g$", Kind: ScanString},
+			},
+		},
+		{
+			name:  "Parameters and names with dollars are not dollar quotes",
+			parts: []string{"$1a$b$"},
+			want: []part{
+				{Text: "$", Kind: ScanCode},
+				{Text: "1", Kind: ScanCode},
+				{Text: "a", Kind: ScanCode},
+				{Text: "$", Kind: ScanCode},
+				{Text: "b", Kind: ScanCode},
+				{Text: "$", Kind: ScanCode},
+			},
+		},
+		{
+			name:  "Comments",
+			parts: []string{"--a\n/*b/*c*/d*/"},
+			want: []part{
+				{Text: "--a", Kind: ScanComment},
+				{Text: "\n", Kind: ScanCode},
+				{Text: "/*b/*c*/d*/", Kind: ScanComment},
+			},
+		},
+		{
+			name:  "Quote continued in the next part",
+			parts: []string{"$$a;", "b$$;"},
+			want: []part{
+				{Text: "$$a;", Kind: ScanString},
+				{Text: "b$$", Kind: ScanString},
+				{Text: ";", Kind: ScanCode},
+			},
+		},
+		{
+			name:     "Open comment",
+			parts:    []string{"/* a"},
+			want:     []part{{Text: "/* a", Kind: ScanComment}},
+			wantOpen: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var s Scanner
+			var got []part
+			for _, text := range tt.parts {
+				for i := 0; i < len(text); {
+					end, kind := s.Next(text, i)
+					got = append(got, part{Text: text[i:end], Kind: kind})
+					i = end
+				}
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+			if s.Open() != tt.wantOpen {
+				t.Errorf("want open=%t, got=%t", tt.wantOpen, s.Open())
+			}
+		})
+	}
+}
@@ -1,540 +1,280 @@
 package postgres
 
 import (
+	"bytes"
+	"strconv"
 	"unicode"
+	"unicode/utf8"
 
 	"github.com/nao1215/aiondb/engine/parser/core"
 )
 
-// appendToken appends a token to the lexer.
-// Now that the verification of the current position (character) is complete,
-// the next position will check.
-func (l *Lexer) appendToken(t core.Token) {
-	l.lex.Tokens = append(l.lex.Tokens, t)
-	l.lex.Position.Current += uint64(len(t.Lexeme))
-}
-
-// match checks whether the argument str matches the SQL token specified in the argument.
-// The argument str can be entered in either uppercase or lowercase.
-func (l *Lexer) match(str []byte, token core.TokenID) bool {
-	if l.Position()+uint64(len(str))-1 > l.InstructionLength() {
+// keywords maps the keywords of PostgreSQL in lower case to their token ID.
+var keywords = map[string]core.TokenID{
+	"now()":          core.TokenIDNow,
+	"unique":         core.TokenIDUnique,
+	"localtimestamp": core.TokenIDLocalTimestamp,
+	"default":        core.TokenIDDefault,
+	"true":           core.TokenIDTrue,
+	"false":          core.TokenIDFalse,
+	"asc":            core.TokenIDAsc,
+	"desc":           core.TokenIDDesc,
+	"and":            core.TokenIDAnd,
+	"or":             core.TokenIDOr,
+	"in":             core.TokenIDIn,
+	"returning":      core.TokenIDReturning,
+	"truncate":       core.TokenIDTruncate,
+	"drop":           core.TokenIDDrop,
+	"grant":          core.TokenIDGrant,
+	"with":           core.TokenIDWith,
+	"time":           core.TokenIDTime,
+	"zone":           core.TokenIDZone,
+	"is":             core.TokenIDIs,
+	"for":            core.TokenIDFor,
+	"limit":          core.TokenIDLimit,
+	"order":          core.TokenIDOrder,
+	"by":             core.TokenIDBy,
+	"set":            core.TokenIDSet,
+	"update":         core.TokenIDUpdate,
+	"create":         core.TokenIDCreate,
+	"select":         core.TokenIDSelect,
+	"distinct":       core.TokenIDDistinct,
+	"insert":         core.TokenIDInsert,
+	"from":           core.TokenIDFrom,
+	"where":          core.TokenIDWhere,
+	"table":          core.TokenIDTable,
+	"null":           core.TokenIDNull,
+	"if":             core.TokenIDIf,
+	"not":            core.TokenIDNot,
+	"exists":         core.TokenIDExists,
+	"count":          core.TokenIDCount,
+	"delete":         core.TokenIDDelete,
+	"auto_increment": core.TokenIDAutoincrement,
+	"autoincrement":  core.TokenIDAutoincrement,
+	"primary":        core.TokenIDPrimary,
+	"key":            core.TokenIDKey,
+	"into":           core.TokenIDInto,
+	"values":         core.TokenIDValues,
+	"join":           core.TokenIDJoin,
+	"on":             core.TokenIDOn,
+	"offset":         core.TokenIDOffset,
+	"index":          core.TokenIDIndex,
+	"collate":        core.TokenIDCollate,
+	"nocase":         core.TokenIDNocase,
+}
+
+func init() { //nolint:gochecknoinits
+	core.RegisterKeywords(core.SQLSyntaxModePostgreSQL, keywords)
+}
+
+// keywordCategories maps the token IDs of the keywords to their category. The words
+// which PostgreSQL does not treat as keywords (e.g. COUNT or NOCASE) are unreserved.
+var keywordCategories = map[core.TokenID]core.KeywordCategory{
+	core.TokenIDNow:            core.ReservedKeyword,
+	core.TokenIDUnique:         core.ReservedKeyword,
+	core.TokenIDLocalTimestamp: core.ReservedKeyword,
+	core.TokenIDDefault:        core.ReservedKeyword,
+	core.TokenIDTrue:           core.ReservedKeyword,
+	core.TokenIDFalse:          core.ReservedKeyword,
+	core.TokenIDAsc:            core.ReservedKeyword,
+	core.TokenIDDesc:           core.ReservedKeyword,
+	core.TokenIDAnd:            core.ReservedKeyword,
+	core.TokenIDOr:             core.ReservedKeyword,
+	core.TokenIDIn:             core.ReservedKeyword,
+	core.TokenIDReturning:      core.ReservedKeyword,
+	core.TokenIDTruncate:       core.UnreservedKeyword,
+	core.TokenIDDrop:           core.UnreservedKeyword,
+	core.TokenIDGrant:          core.ReservedKeyword,
+	core.TokenIDWith:           core.ReservedKeyword,
+	core.TokenIDTime:           core.ColNameKeyword,
+	core.TokenIDZone:           core.UnreservedKeyword,
+	core.TokenIDIs:             core.TypeFuncNameKeyword,
+	core.TokenIDFor:            core.ReservedKeyword,
+	core.TokenIDLimit:          core.ReservedKeyword,
+	core.TokenIDOrder:          core.ReservedKeyword,
+	core.TokenIDBy:             core.UnreservedKeyword,
+	core.TokenIDSet:            core.UnreservedKeyword,
+	core.TokenIDUpdate:         core.UnreservedKeyword,
+	core.TokenIDCreate:         core.ReservedKeyword,
+	core.TokenIDSelect:         core.ReservedKeyword,
+	core.TokenIDDistinct:       core.ReservedKeyword,
+	core.TokenIDInsert:         core.UnreservedKeyword,
+	core.TokenIDFrom:           core.ReservedKeyword,
+	core.TokenIDWhere:          core.ReservedKeyword,
+	core.TokenIDTable:          core.ReservedKeyword,
+	core.TokenIDNull:           core.ReservedKeyword,
+	core.TokenIDIf:             core.UnreservedKeyword,
+	core.TokenIDNot:            core.ReservedKeyword,
+	core.TokenIDExists:         core.ColNameKeyword,
+	core.TokenIDCount:          core.UnreservedKeyword,
+	core.TokenIDDelete:         core.UnreservedKeyword,
+	core.TokenIDAutoincrement:  core.UnreservedKeyword,
+	core.TokenIDPrimary:        core.ReservedKeyword,
+	core.TokenIDKey:            core.UnreservedKeyword,
+	core.TokenIDInto:           core.ReservedKeyword,
+	core.TokenIDValues:         core.ColNameKeyword,
+	core.TokenIDJoin:           core.TypeFuncNameKeyword,
+	core.TokenIDOn:             core.ReservedKeyword,
+	core.TokenIDOffset:         core.ReservedKeyword,
+	core.TokenIDIndex:          core.UnreservedKeyword,
+	core.TokenIDCollate:        core.ReservedKeyword,
+	core.TokenIDNocase:         core.UnreservedKeyword,
+}
+
+// matchEscapeString checks whether it matches an escape string constant
+// (e.g. E'it\'s\n'), whose backslash escapes are replaced like PostgreSQL does.
+// It appends the quotes and the unescaped string as for single quoted strings.
+func matchEscapeString(l *core.Lex) bool {
+	content := l.Instruction.Content
+	i := l.Position.Current
+	if i+1 >= l.Instruction.Length || (content[i] != 'E' && content[i] != 'e') || content[i+1] != '\'' {
 		return false
 	}
-
-	for i := range str {
-		if unicode.ToLower(rune(l.lex.Instruction.Content[int(l.Position())+i])) != unicode.ToLower(rune(str[i])) {
-			return false
-		}
-	}
-
-	// if next character is still a string, it means it doesn't match
-	// ie: COUNT shoulnd match COUNTRY
-	if l.InstructionLength() > l.Position()+uint64(len(str)) {
-		if unicode.IsLetter(rune(l.lex.Instruction.Content[l.Position()+uint64(len(str))])) ||
-			l.lex.Instruction.Content[l.Position()+uint64(len(str))] == '_' {
-			return false
-		}
+	l.Append(core.Token{ID: core.TokenIDSingleQuote, Lexeme: core.Lexeme("'")})
+
+	value, end, closed := unescapeString(content, int(i+2))
+	l.Append(core.Token{ID: core.TokenIDString, Lexeme: core.Lexeme(value)})
+	l.Position.Current = uint64(end)
+	if closed {
+		l.Append(core.Token{ID: core.TokenIDSingleQuote, Lexeme: core.Lexeme("'")})
+		l.Position.Current++
 	}
-	l.appendToken(core.Token{ID: token, Lexeme: core.Lexeme(str)})
 	return true
 }
 
-// matchSingleChar checks whether it matches the single character token.
-func (l *Lexer) matchSingleChar(char byte, token core.TokenID) bool {
-	if l.Position() > l.InstructionLength() {
-		return false
-	}
-
-	if l.lex.Instruction.Content[l.Position()] != char {
-		return false
-	}
-	l.appendToken(core.Token{ID: token, Lexeme: core.Lexeme(char)})
-	return true
-}
-
-// matchStringToken checks whether it matches the string token.
-func (l *Lexer) matchStringToken() bool {
-	i := l.Position()
-	for i < l.InstructionLength() &&
-		(unicode.IsLetter(rune(l.lex.Instruction.Content[i])) ||
-			unicode.IsDigit(rune(l.lex.Instruction.Content[i])) ||
-			l.lex.Instruction.Content[i] == '_' ||
-			l.lex.Instruction.Content[i] == '@' /* || l.instruction[i] == '.'*/) {
-		i++
-	}
-
-	if i == l.Position() {
-		return false
-	}
-
-	l.appendToken(core.Token{
-		ID:     core.TokenIDString,
-		Lexeme: core.Lexeme(l.lex.Instruction.Content[l.Position():i]),
-	})
-	l.lex.Position.Current = i
-	return true
-}
-
-// matchNumberToken checks whether it matches the number token.
-func (l *Lexer) matchNumberToken() bool {
-	i := l.Position()
-	for i < l.InstructionLength() && unicode.IsDigit(rune(l.lex.Instruction.Content[i])) {
-		i++
-	}
-
-	if i < l.InstructionLength() && l.lex.Instruction.Content[i] == '.' {
-		i++
-		for i < l.InstructionLength() && unicode.IsDigit(rune(l.lex.Instruction.Content[i])) {
+// unescapeString returns the content of an escape string constant starting at the
+// index i, the index of its closing quote and whether the string is closed.
+// The escapes are \b, \f, \n, \r, \t, octal (\ooo), hexadecimal (\xhh) and
+// unicode (\uxxxx, \Uxxxxxxxx) characters, other escaped characters are kept as is.
+func unescapeString(content []byte, i int) ([]byte, int, bool) {
+	value := []byte{}
+	for i < len(content) {
+		c := content[i]
+		switch {
+		case c == '\'' && i+1 < len(content) && content[i+1] == '\'':
+			value = append(value, '\'')
+			i += 2
+		case c == '\'':
+			return value, i, true
+		case c == '\\' && i+1 < len(content):
+			i++
+			switch e := content[i]; {
+			case e == 'b':
+				value = append(value, '\b')
+			case e == 'f':
+				value = append(value, '\f')
+			case e == 'n':
+				value = append(value, '\n')
+			case e == 'r':
+				value = append(value, '\r')
+			case e == 't':
+				value = append(value, '\t')
+			case '0' <= e && e <= '7':
+				n, size := parseDigits(content[i:], 8, 3)
+				value = append(value, byte(n))
+				i += size - 1
+			case e == 'x' || e == 'u' || e == 'U':
+				maxSize := 2
+				if e == 'u' {
+					maxSize = 4
+				} else if e == 'U' {
+					maxSize = 8
+				}
+				n, size := parseDigits(content[i+1:], 16, maxSize)
+				switch {
+				case size == 0:
+					value = append(value, e)
+				case e == 'x':
+					value = append(value, byte(n))
+				default:
+					value = utf8.AppendRune(value, rune(n))
+				}
+				i += size
+			default:
+				value = append(value, e)
+			}
+			i++
+		default:
+			value = append(value, c)
 			i++
 		}
 	}
-
-	if i == l.Position() {
-		return false
-	}
-
-	l.appendToken(core.Token{
-		ID:     core.TokenIDNumber,
-		Lexeme: core.Lexeme(l.lex.Instruction.Content[l.Position():i]),
-	})
-	l.lex.Position.Current = i
-	return true
+	return value, i, false
 }
 
-// 2015-09-10 14:03:09.444695269 +0200 CEST);
-func (l *Lexer) matchDateToken() bool {
-	i := l.Position()
-	for i < l.InstructionLength() &&
-		l.lex.Instruction.Content[i] != ',' &&
-		l.lex.Instruction.Content[i] != ')' {
-		i++
+// parseDigits parses at most maxSize digits in the base at the beginning of the text.
+// It returns the value and the number of digits.
+func parseDigits(text []byte, base, maxSize int) (uint64, int) {
+	size := 0
+	for size < len(text) && size < maxSize && core.IsDigit(text[size], base) {
+		size++
 	}
-
-	data := string(l.lex.Instruction.Content[l.Position():i])
-	if _, err := core.ParseDate(data); err != nil {
-		return false
+	n, err := strconv.ParseUint(string(text[:size]), base, 32)
+	if err != nil {
+		return 0, size
 	}
-	l.appendToken(core.Token{ID: core.TokenIDDate, Lexeme: core.Lexeme(data)})
-	l.lex.Position.Current = i
-
-	return true
+	return n, size
 }
 
-// matchSingleQuoteToken checks whether it matches the single quote token.
-func (l *Lexer) matchSingleQuoteToken() bool {
-	if l.lex.Instruction.Content[l.Position()] != '\'' {
+// matchDollarQuotedString checks whether it matches the dollar quoted string token
+// (e.g. $$it's$$ or $body$it's$body$). The content is a number or a date token if it
+// is one, a string token otherwise.
+func matchDollarQuotedString(l *core.Lex) bool {
+	content := l.Instruction.Content
+	text := content[l.Position.Current:]
+	if text[0] != '$' {
 		return false
 	}
-
-	l.appendToken(core.Token{ID: core.TokenIDSingleQuote, Lexeme: core.Lexeme("'")})
-
-	if l.matchSingleQuotedStringToken() {
-		l.appendToken(core.Token{ID: core.TokenIDSingleQuote, Lexeme: core.Lexeme("'")})
-		return true
-	}
-	return true
-}
-
-// matchSingleQuotedStringToken checks whether it matches the single quoted string token.
-func (l *Lexer) matchSingleQuotedStringToken() bool {
-	i := l.Position()
-	for i < l.InstructionLength() && l.lex.Instruction.Content[i] != '\'' {
-		i++
-	}
-
-	l.appendToken(core.Token{
-		ID:     core.TokenIDString,
-		Lexeme: core.Lexeme(l.lex.Instruction.Content[l.Position():i]),
-	})
-	l.lex.Position.Current = i
-	return true
-}
-
-// matchDoubleQuoteToken checks whether it matches the double quote token.
-func (l *Lexer) matchDoubleQuoteToken() bool {
-	if l.lex.Instruction.Content[l.Position()] != '"' {
+	// Only the text up to the second dollar may be a tag
+	n := bytes.IndexByte(text[1:], '$')
+	if n < 0 {
 		return false
 	}
-	l.appendToken(core.Token{ID: core.TokenIDDoubleQuote, Lexeme: "\""})
-
-	if l.matchDoubleQuotedStringToken() {
-		l.appendToken(core.Token{ID: core.TokenIDDoubleQuote, Lexeme: "\""})
-		return true
-	}
-	return true
-}
-
-// matchDoubleQuotedStringToken checks whether it matches the double quoted string token.
-func (l *Lexer) matchDoubleQuotedStringToken() bool {
-	i := l.Position()
-	for i < l.InstructionLength() && l.lex.Instruction.Content[i] != '"' {
-		i++
-	}
-	l.appendToken(core.Token{
-		ID:     core.TokenIDString,
-		Lexeme: core.Lexeme(l.lex.Instruction.Content[l.Position():i]),
-	})
-	l.lex.Position.Current = i
-
-	return true
-}
-
-// matchEscapedStringToken checks whether it matches the escaped string token.
-func (l *Lexer) matchEscapedStringToken() bool {
-	i := l.Position()
-	if l.lex.Instruction.Content[i] != '$' || l.lex.Instruction.Content[i+1] != '$' {
+	tag := DollarTag(string(text[:n+2]))
+	if tag == "" {
 		return false
 	}
-	i += 2
-
-	for i+1 < l.InstructionLength() &&
-		!(l.lex.Instruction.Content[i] == '$' &&
-			l.lex.Instruction.Content[i+1] == '$') {
-		i++
-	}
-	i++
-
-	if i == l.InstructionLength() {
+	start := int(l.Position.Current) + len(tag)
+	end := bytes.Index(content[start:], []byte(tag))
+	if end < 0 {
 		return false
 	}
+	escaped := content[start : start+end]
 
-	tokenID := core.TokenIDNumber
-	escaped := l.lex.Instruction.Content[l.Position()+2 : i-1]
-
+	tokenID, kind := core.TokenIDNumber, core.NumberKindInteger
 	for _, r := range escaped {
 		if !unicode.IsDigit(rune(r)) {
-			tokenID = core.TokenIDString
+			tokenID, kind = core.TokenIDString, core.NumberKindNone
 		}
 	}
 
 	_, err := core.ParseDate(string(escaped))
 	if err == nil {
-		tokenID = core.TokenIDDate
+		tokenID, kind = core.TokenIDDate, core.NumberKindNone
 	}
 
-	l.appendToken(core.Token{ID: tokenID, Lexeme: core.Lexeme(escaped)})
-	l.lex.Position.Current = i + 1
+	l.Append(core.Token{ID: tokenID, Lexeme: core.Lexeme(escaped), Number: kind})
+	l.Position.Current = uint64(start + end + len(tag))
 
 	return true
 }
 
-// matchSpaceToken checks whether it matches the space(e.g. " ") token.
-func (l *Lexer) matchSpaceToken() bool {
-	if !unicode.IsSpace(rune(l.lex.Instruction.Content[l.lex.Position.Current])) {
+// matchPositionalParameter checks whether it matches a positional parameter of a
+// prepared statement (e.g. $1), whose value is bound by the client.
+func matchPositionalParameter(l *core.Lex) bool {
+	content := l.Instruction.Content
+	start := l.Position.Current
+	if content[start] != '$' {
 		return false
 	}
-	l.appendToken(core.Token{ID: core.TokenIDSpace, Lexeme: " "})
-	return true
-}
-
-// matchNowToken checks whether it matches the now() token.
-func (l *Lexer) matchNowToken() bool {
-	return l.match([]byte("now()"), core.TokenIDNow)
-}
-
-// matchUniqueToken checks whether it matches the unique token.
-func (l *Lexer) matchUniqueToken() bool {
-	return l.match([]byte("unique"), core.TokenIDUnique)
-}
-
-// matchLocalTimestampToken checks whether it matches the localtimestamp token.
-func (l *Lexer) matchLocalTimestampToken() bool {
-	return l.match([]byte("localtimestamp"), core.TokenIDLocalTimestamp)
-}
-
-// matchDefaultToken checks whether it matches the default token.
-func (l *Lexer) matchDefaultToken() bool {
-	return l.match([]byte("default"), core.TokenIDDefault)
-}
-
-// matchTrueToken checks whether it matches the true token.
-func (l *Lexer) matchTrueToken() bool {
-	return l.match([]byte("true"), core.TokenIDTrue)
-}
-
-// matchFlaseToken checks whether it matches the false token.
-func (l *Lexer) matchFalseToken() bool {
-	return l.match([]byte("false"), core.TokenIDFalse)
-}
-
-// matchAscToken checks whether it matches the asc token.
-func (l *Lexer) matchAscToken() bool {
-	return l.match([]byte("asc"), core.TokenIDAsc)
-}
-
-// matchDescToken checks whether it matches the desc token.
-func (l *Lexer) matchDescToken() bool {
-	return l.match([]byte("desc"), core.TokenIDDesc)
-}
-
-// matchAndToken checks whether it matches the and token.
-func (l *Lexer) matchAndToken() bool {
-	return l.match([]byte("and"), core.TokenIDAnd)
-}
-
-// matchOrToken checks whether it matches the or token.
-func (l *Lexer) matchOrToken() bool {
-	return l.match([]byte("or"), core.TokenIDOr)
-}
-
-// matchInToken checks whether it matches the in token.
-func (l *Lexer) matchInToken() bool {
-	return l.match([]byte("in"), core.TokenIDIn)
-}
-
-// matchReturningToken checks whether it matches the returning token.
-func (l *Lexer) matchReturningToken() bool {
-	return l.match([]byte("returning"), core.TokenIDReturning)
-}
-
-// matchTruncateToken checks whether it matches the truncate token.
-func (l *Lexer) matchTruncateToken() bool {
-	return l.match([]byte("truncate"), core.TokenIDTruncate)
-}
-
-// matchDropToken checks whether it matches the drop token.
-func (l *Lexer) matchDropToken() bool {
-	return l.match([]byte("drop"), core.TokenIDDrop)
-}
-
-// matchGrantToken checks whether it matches the grant token.
-func (l *Lexer) matchGrantToken() bool {
-	return l.match([]byte("grant"), core.TokenIDGrant)
-}
-
-// matchWithToken checks whether it matches the with token.
-func (l *Lexer) matchWithToken() bool {
-	return l.match([]byte("with"), core.TokenIDWith)
-}
-
-// matchTimeToken checks whether it matches the time token.
-func (l *Lexer) matchTimeToken() bool {
-	return l.match([]byte("time"), core.TokenIDTime)
-}
-
-// matchZoneToken checks whether it matches the zone token.
-func (l *Lexer) matchZoneToken() bool {
-	return l.match([]byte("zone"), core.TokenIDZone)
-}
-
-// matchIsToken checks whether it matches the is token.
-func (l *Lexer) matchIsToken() bool {
-	return l.match([]byte("is"), core.TokenIDIs)
-}
-
-// matchForToken checks whether it matches the for token.
-func (l *Lexer) matchForToken() bool {
-	return l.match([]byte("for"), core.TokenIDFor)
-}
-
-// matchLimitToken checks whether it matches the limit token.
-func (l *Lexer) matchLimitToken() bool {
-	return l.match([]byte("limit"), core.TokenIDLimit)
-}
-
-// matchOrderToken checks whether it matches the order token.
-func (l *Lexer) matchOrderToken() bool {
-	return l.match([]byte("order"), core.TokenIDOrder)
-}
-
-// matchByToken checks whether it matches the by token.
-func (l *Lexer) matchByToken() bool {
-	return l.match([]byte("by"), core.TokenIDBy)
-}
-
-// matchSetToken checks whether it matches the set token.
-func (l *Lexer) matchSetToken() bool {
-	return l.match([]byte("set"), core.TokenIDSet)
-}
-
-// matchUpdateToken checks whether it matches the update token.
-func (l *Lexer) matchUpdateToken() bool {
-	return l.match([]byte("update"), core.TokenIDUpdate)
-}
-
-// matchCreateToken checks whether it matches the create token.
-func (l *Lexer) matchCreateToken() bool {
-	return l.match([]byte("create"), core.TokenIDCreate)
-}
-
-// matchSelectToken checks whether it matches the select token.
-func (l *Lexer) matchSelectToken() bool {
-	return l.match([]byte("select"), core.TokenIDSelect)
-}
-
-// matchDistinctToken checks whether it matches the distinct token.
-func (l *Lexer) matchDistinctToken() bool {
-	return l.match([]byte("distinct"), core.TokenIDDistinct)
-}
-
-// matchInsertToken	checks whether it matches the insert token.
-func (l *Lexer) matchInsertToken() bool {
-	return l.match([]byte("insert"), core.TokenIDInsert)
-}
-
-// matchWhereToken checks whether it matches the where token.
-func (l *Lexer) matchWhereToken() bool {
-	return l.match([]byte("where"), core.TokenIDWhere)
-}
-
-func (l *Lexer) matchFromToken() bool {
-	return l.match([]byte("from"), core.TokenIDFrom)
-}
-
-// matchTableToken checks whether it matches the table token.
-func (l *Lexer) matchTableToken() bool {
-	return l.match([]byte("table"), core.TokenIDTable)
-}
-
-// matchNullToken checks whether it matches the null token.
-func (l *Lexer) matchNullToken() bool {
-	return l.match([]byte("null"), core.TokenIDNull)
-}
-
-// matchIfToken checks whether it matches the if token.
-func (l *Lexer) matchIfToken() bool {
-	return l.match([]byte("if"), core.TokenIDIf)
-}
-
-// matchNotToken checks whether it matches the not token.
-func (l *Lexer) matchNotToken() bool {
-	return l.match([]byte("not"), core.TokenIDNot)
-}
-
-// matchExistsToken checks whether it matches the exists token.
-func (l *Lexer) matchExistsToken() bool {
-	return l.match([]byte("exists"), core.TokenIDExists)
-}
-
-// matchCountToken checks whether it matches the count token.
-func (l *Lexer) matchCountToken() bool {
-	return l.match([]byte("count"), core.TokenIDCount)
-}
-
-// matchDeleteToken checks whether it matches the delete token.
-func (l *Lexer) matchDeleteToken() bool {
-	return l.match([]byte("delete"), core.TokenIDDelete)
-}
-
-// matchAutoIncrementToken checks whether it matches the auto_increment token.
-func (l *Lexer) matchAutoIncrementToken() bool {
-	if l.match([]byte("auto_increment"), core.TokenIDAutoincrement) {
-		return true
+	i := start + 1
+	for i < l.Instruction.Length && core.IsDigit(content[i], 10) {
+		i++
 	}
-	return l.match([]byte("autoincrement"), core.TokenIDAutoincrement)
-}
-
-// matchPrimaryToken checks whether it matches the primary token.
-func (l *Lexer) matchPrimaryToken() bool {
-	return l.match([]byte("primary"), core.TokenIDPrimary)
-}
-
-// matchKeyToken checks whether it matches the key token.
-func (l *Lexer) matchKeyToken() bool {
-	return l.match([]byte("key"), core.TokenIDKey)
-}
-
-// matchIntoToken checks whether it matches the into token.
-func (l *Lexer) matchIntoToken() bool {
-	return l.match([]byte("into"), core.TokenIDInto)
-}
-
-// matchValuesToken checks whether it matches the values token.
-func (l *Lexer) matchValuesToken() bool {
-	return l.match([]byte("values"), core.TokenIDValues)
-}
-
-// matchJoinToken checks whether it matches the join token.
-func (l *Lexer) matchJoinToken() bool {
-	return l.match([]byte("join"), core.TokenIDJoin)
-}
-
-// matchOnToken checks whether it matches the on token.
-func (l *Lexer) matchOnToken() bool {
-	return l.match([]byte("on"), core.TokenIDOn)
-}
-
-// matchOffsetToken checks whether it matches the offset token.
-func (l *Lexer) matchOffsetToken() bool {
-	return l.match([]byte("offset"), core.TokenIDOffset)
-}
-
-// matchIndexToken checks whether it matches the index token.
-func (l *Lexer) matchIndexToken() bool {
-	return l.match([]byte("index"), core.TokenIDIndex)
-}
-
-// matchCollateToken checks whether it matches the collate token.
-func (l *Lexer) matchCollateToken() bool {
-	return l.match([]byte("collate"), core.TokenIDCollate)
-}
-
-// matchNocaseToken	checks whether it matches the nocase token.
-func (l *Lexer) matchNocaseToken() bool {
-	return l.match([]byte("nocase"), core.TokenIDNocase)
-}
-
-// matchSemicolonToken checks whether it matches the semicolon token.
-func (l *Lexer) matchSemicolonToken() bool {
-	return l.matchSingleChar(';', core.TokenIDSemicolon)
-}
-
-// matchPeriodToken checks whether it matches the period token.
-func (l *Lexer) matchPeriodToken() bool {
-	return l.matchSingleChar('.', core.TokenIDPeriod)
-}
-
-// matchBracketOpeningToken checks whether it matches the bracket opening token.
-func (l *Lexer) matchBracketOpeningToken() bool {
-	return l.matchSingleChar('(', core.TokenIDBracketOpening)
-}
-
-// matchBracketClosingToken checks whether it matches the bracket closing token.
-func (l *Lexer) matchBracketClosingToken() bool {
-	return l.matchSingleChar(')', core.TokenIDBracketClosing)
-}
-
-// matchCommaToken checks whether it matches the comma token.
-func (l *Lexer) matchCommaToken() bool {
-	return l.matchSingleChar(',', core.TokenIDComma)
-}
-
-// matchStarToken checks whether it matches the star token.
-func (l *Lexer) matchStarToken() bool {
-	return l.matchSingleChar('*', core.TokenIDStar)
-}
-
-// matchEqualityToken checks whether it matches the equality token.
-func (l *Lexer) matchEqualityToken() bool {
-	return l.matchSingleChar('=', core.TokenIDEquality)
-}
-
-// matchDistinctnessToken checks whether it matches the distinctness token.
-func (l *Lexer) matchDistinctnessToken() bool {
-	return l.match([]byte("<>"), core.TokenIDDistinctness)
-}
-
-// matchLeftDipleToken checks whether it matches the left diple token.
-func (l *Lexer) matchLeftDipleToken() bool {
-	return l.matchSingleChar('<', core.TokenIDLeftDiple)
-}
-
-// matchRightDipleToken checks whether it matches the right diple token.
-func (l *Lexer) matchRightDipleToken() bool {
-	return l.matchSingleChar('>', core.TokenIDRightDiple)
-}
-
-// matchLessOrEqualToken checks whether it matches the less or equal token.
-func (l *Lexer) matchLessOrEqualToken() bool {
-	return l.match([]byte("<="), core.TokenIDLessOrEqual)
-}
-
-// matchGreaterOrEqualToken checks whether it matches the greater or equal token.
-func (l *Lexer) matchGreaterOrEqualToken() bool {
-	return l.match([]byte(">="), core.TokenIDGreaterOrEqual)
-}
-
-// matchBacktickToken checks whether it matches the backtick token.
-func (l *Lexer) matchBacktickToken() bool {
-	return l.matchSingleChar('`', core.TokenIDBacktick)
+	if i == start+1 {
+		return false
+	}
+	l.Append(core.Token{ID: core.TokenIDParameter, Lexeme: core.Lexeme(content[start:i])})
+	l.Position.Current = i
+	return true
 }
@@ -0,0 +1,30 @@
+package sqlite
+
+import (
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// NewLexer returns a new lexer for SQLite.
+func NewLexer(input string) *core.Lexer {
+	return core.NewLexer(input, newMatchers)
+}
+
+// newMatchers returns the matchers of the SQLite lexer. A quote is written as two quotes,
+// there are no backslash escapes. Identifiers quoted by square brackets (e.g. [order])
+// are lexed as double quoted identifiers, so that the parser handles all quoted
+// identifiers in the same way. SQLite accepts the parameters ?, ?NNN, :name, @name and $name.
+func newMatchers(l *core.Lex) core.Matchers {
+	return core.Matchers{
+		l.MatchSpace,
+		func() bool { return l.MatchWord(keywords) },
+		func() bool { return l.MatchQuoted('\'', '\'', core.TokenIDSingleQuote, nil) },
+		func() bool { return l.MatchQuoted('"', '"', core.TokenIDDoubleQuote, nil) },
+		func() bool { return l.MatchQuoted('`', '`', core.TokenIDBacktick, nil) },
+		func() bool { return l.MatchQuoted('[', ']', core.TokenIDDoubleQuote, nil) },
+		func() bool { return l.MatchParameter("?:@$") },
+		l.MatchDate,
+		l.MatchNumber,
+		l.MatchString,
+		l.MatchOperator,
+	}
+}
@@ -0,0 +1,99 @@
+// Package sqlite parses SQL queries with SQLite syntax.
+package sqlite
+
+import (
+	"strings"
+
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// dialect is what SQLite adds to the grammar shared by the parsers.
+var dialect = core.Dialect{
+	Matchers:       newMatchers,
+	ParseType:      parseType,
+	CompleteColumn: completeColumn,
+}
+
+// NewParser returns a new parser of SQL queries conforming to SQLite.
+// It satisfies the Parser interface of the parser package.
+func NewParser() *core.Parser {
+	return core.NewParser(dialect)
+}
+
+// parseType parses a column type. SQLite accepts any sequence of names as type,
+// optionally followed by one or two sizes which are ignored, e.g. UNSIGNED BIG INT
+// or VARYING CHARACTER(255). The type may be omitted too. The returned declaration
+// has the names as lexeme, it is empty if there is no type.
+func parseType(p *core.Parser) (*core.Decl, error) {
+	names := []string{}
+	for p.Is(core.TokenIDString) {
+		names = append(names, p.Current().Lexeme.String())
+		if err := p.Next(); err != nil {
+			return nil, err
+		}
+	}
+	typeDecl := &core.Decl{TokenID: core.TokenIDString, Lexeme: core.Lexeme(strings.Join(names, " "))}
+
+	if len(names) == 0 || !p.Is(core.TokenIDBracketOpening) {
+		return typeDecl, nil
+	}
+	if _, err := p.ConsumeToken(core.TokenIDBracketOpening); err != nil {
+		return nil, err
+	}
+	if _, err := p.ConsumeToken(core.TokenIDNumber); err != nil {
+		return nil, err
+	}
+	if p.Is(core.TokenIDComma) {
+		if _, err := p.ConsumeToken(core.TokenIDComma); err != nil {
+			return nil, err
+		}
+		if _, err := p.ConsumeToken(core.TokenIDNumber); err != nil {
+			return nil, err
+		}
+	}
+	if _, err := p.ConsumeToken(core.TokenIDBracketClosing); err != nil {
+		return nil, err
+	}
+	return typeDecl, nil
+}
+
+// completeColumn adds the type affinity of the column to its declared type, which is kept.
+// A column of type INTEGER PRIMARY KEY is an alias for the rowid, it is
+// assigned automatically like an AUTOINCREMENT column.
+func completeColumn(columnDecl *core.Decl) {
+	typeDecl := columnDecl.DeclList[0]
+	rowID := strings.EqualFold(typeDecl.Lexeme.String(), "integer")
+	typeDecl.Append(&core.Decl{TokenID: core.TokenIDString, Lexeme: core.Lexeme(affinity(typeDecl.Lexeme.String()))})
+
+	primaryKey, autoincrement := false, false
+	for _, d := range columnDecl.DeclList[1:] {
+		switch d.TokenID {
+		case core.TokenIDPrimary:
+			primaryKey = true
+		case core.TokenIDAutoincrement:
+			autoincrement = true
+		default:
+		}
+	}
+	if rowID && primaryKey && !autoincrement {
+		columnDecl.Append(&core.Decl{TokenID: core.TokenIDAutoincrement, Lexeme: "autoincrement"})
+	}
+}
+
+// affinity returns the type affinity of the declared column type typeName,
+// following the rules of SQLite: INTEGER, TEXT, BLOB, REAL or NUMERIC.
+func affinity(typeName string) string {
+	name := strings.ToUpper(typeName)
+	switch {
+	case strings.Contains(name, "INT"):
+		return "INTEGER"
+	case strings.Contains(name, "CHAR"), strings.Contains(name, "CLOB"), strings.Contains(name, "TEXT"):
+		return "TEXT"
+	case name == "", strings.Contains(name, "BLOB"):
+		return "BLOB"
+	case strings.Contains(name, "REAL"), strings.Contains(name, "FLOA"), strings.Contains(name, "DOUB"):
+		return "REAL"
+	default:
+		return "NUMERIC"
+	}
+}
@@ -0,0 +1,263 @@
+package sqlite
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/nao1215/aiondb/engine/parser/core"
+	"github.com/nao1215/aiondb/engine/parser/postgres"
+)
+
+func TestParserSameDeclsAsPostgres(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		sqlite   string
+		postgres string
+	}{
+		{
+			name:     "Quoted identifiers",
+			sqlite:   "SELECT [u].`id`, \"name\" FROM [users] WHERE [id] = 1",
+			postgres: `SELECT "u"."id", "name" FROM "users" WHERE "id" = 1`,
+		},
+		{
+			name:     "Identifiers which are PostgreSQL keywords",
+			sqlite:   "UPDATE events SET time = 1, zone = 'a' WHERE with = 2",
+			postgres: `UPDATE events SET "time" = 1, "zone" = 'a' WHERE "with" = 2`,
+		},
+		{
+			name:     "Doubled quotes",
+			sqlite:   `INSERT INTO "my users" (note) VALUES ('it''s')`,
+			postgres: `INSERT INTO "my users" (note) VALUES ($$it's$$)`,
+		},
+		{
+			name:     "INTEGER PRIMARY KEY is auto-incremented",
+			sqlite:   "CREATE TABLE t (id INTEGER PRIMARY KEY, a INTEGER NOT NULL)",
+			postgres: "CREATE TABLE t (id INTEGER PRIMARY KEY AUTOINCREMENT, a INTEGER NOT NULL)",
+		},
+		{
+			name:     "INTEGER PRIMARY KEY AUTOINCREMENT",
+			sqlite:   "CREATE TABLE IF NOT EXISTS t (id INTEGER PRIMARY KEY ASC AUTOINCREMENT, a TEXT NULL UNIQUE)",
+			postgres: "CREATE TABLE IF NOT EXISTS t (id INTEGER PRIMARY KEY AUTOINCREMENT, a TEXT UNIQUE)",
+		},
+		{
+			name:     "COLLATE BINARY is the default",
+			sqlite:   "CREATE TABLE t (a TEXT COLLATE BINARY DEFAULT 'x')",
+			postgres: "CREATE TABLE t (a TEXT DEFAULT 'x')",
+		},
+		{
+			name:     "DELETE",
+			sqlite:   "DELETE FROM [users] WHERE id IN (1, 2)",
+			postgres: "DELETE FROM users WHERE id IN (1, 2)",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewParser().Parse(tt.sqlite)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, stmt := range got {
+				for _, d := range stmt.Decls {
+					removeAffinity(d)
+				}
+			}
+			want, err := postgres.NewParser().Parse(tt.postgres)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+// removeAffinity removes the type affinity added to the types of the columns,
+// which PostgreSQL does not have. SQLite ignores the type sizes, so the
+// declaration of a type only has the affinity.
+func removeAffinity(d *core.Decl) {
+	if d.TokenID == core.TokenIDString && len(d.DeclList) > 0 && d.DeclList[0].TokenID == core.TokenIDString {
+		d.DeclList[0].DeclList = nil
+	}
+	for _, c := range d.DeclList {
+		removeAffinity(c)
+	}
+}
+
+func TestParserTypeAffinity(t *testing.T) {
+	t.Parallel()
+
+	stmts, err := NewParser().Parse("CREATE TABLE t (a INT, b UNSIGNED BIG INT, c VARYING CHARACTER(255), d NVARCHAR(100), e CLOB, f BLOB, g, h REAL, i DOUBLE PRECISION, j FLOAT, k DECIMAL(10, 5), l BOOLEAN, m DATETIME)")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[string]core.TypeName{
+		"a": {Name: "INT", Affinity: "INTEGER"},
+		"b": {Name: "UNSIGNED BIG INT", Affinity: "INTEGER"},
+		"c": {Name: "VARYING CHARACTER", Affinity: "TEXT"},
+		"d": {Name: "NVARCHAR", Affinity: "TEXT"},
+		"e": {Name: "CLOB", Affinity: "TEXT"},
+		"f": {Name: "BLOB", Affinity: "BLOB"},
+		"g": {Name: "", Affinity: "BLOB"},
+		"h": {Name: "REAL", Affinity: "REAL"},
+		"i": {Name: "DOUBLE PRECISION", Affinity: "REAL"},
+		"j": {Name: "FLOAT", Affinity: "REAL"},
+		"k": {Name: "DECIMAL", Affinity: "NUMERIC"},
+		"l": {Name: "BOOLEAN", Affinity: "NUMERIC"},
+		"m": {Name: "DATETIME", Affinity: "NUMERIC"},
+	}
+	ast, err := core.NewAST(stmts[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]core.TypeName{}
+	for _, column := range ast.(*core.CreateTableStmt).Columns {
+		got[column.Name] = *column.Type
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("value is mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParserInsertOr(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		query string
+		want  core.TokenID
+	}{
+		{
+			name:  "INSERT OR REPLACE",
+			query: "INSERT OR REPLACE INTO t (id) VALUES (1)",
+			want:  core.TokenIDReplace,
+		},
+		{
+			name:  "INSERT OR IGNORE",
+			query: "insert or ignore into t (id) values (1), (2)",
+			want:  core.TokenIDIgnore,
+		},
+		{
+			name:  "REPLACE INTO",
+			query: "REPLACE INTO t (id) VALUES (1)",
+			want:  core.TokenIDReplace,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			stmts, err := NewParser().Parse(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			insertDecl := stmts[0].Decls[0]
+			if insertDecl.TokenID != core.TokenIDInsert || len(insertDecl.DeclList) != 3 {
+				t.Fatalf("unexpected declaration: %v", insertDecl)
+			}
+			orDecl := insertDecl.DeclList[2]
+			if orDecl.TokenID != core.TokenIDOr || len(orDecl.DeclList) != 1 || orDecl.DeclList[0].TokenID != tt.want {
+				t.Errorf("unexpected OR declaration: %v", orDecl)
+			}
+		})
+	}
+
+	for _, query := range []string{
+		"INSERT OR INTO t (id) VALUES (1)",
+		"INSERT OR ABORT INTO t (id) VALUES (1)",
+		"CREATE TABLE t (a TEXT COLLATE RTRIM)",
+	} {
+		if _, err := NewParser().Parse(query); err == nil {
+			t.Errorf("expect error for %q, however no error", query)
+		}
+	}
+}
+
+func TestLexer(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		input string
+		want  []core.Token
+	}{
+		{
+			name:  "Parameters",
+			input: "? ?12 :name @x $y_1",
+			want: []core.Token{
+				{ID: core.TokenIDParameter, Lexeme: "?"},
+				{ID: core.TokenIDSpace, Lexeme: " "},
+				{ID: core.TokenIDParameter, Lexeme: "?12"},
+				{ID: core.TokenIDSpace, Lexeme: " "},
+				{ID: core.TokenIDParameter, Lexeme: ":name"},
+				{ID: core.TokenIDSpace, Lexeme: " "},
+				{ID: core.TokenIDParameter, Lexeme: "@x"},
+				{ID: core.TokenIDSpace, Lexeme: " "},
+				{ID: core.TokenIDParameter, Lexeme: "$y_1"},
+			},
+		},
+		{
+			name:  "Doubled quotes without backslash escapes",
+			input: `'it''s\n' "a""b"`,
+			want: []core.Token{
+				{ID: core.TokenIDSingleQuote, Lexeme: "'"},
+				{ID: core.TokenIDString, Lexeme: `it's\n`},
+				{ID: core.TokenIDSingleQuote, Lexeme: "'"},
+				{ID: core.TokenIDSpace, Lexeme: " "},
+				{ID: core.TokenIDDoubleQuote, Lexeme: `"`},
+				{ID: core.TokenIDString, Lexeme: `a"b`},
+				{ID: core.TokenIDDoubleQuote, Lexeme: `"`},
+			},
+		},
+		{
+			name:  "Square brackets are lexed as double quotes",
+			input: "[a b]",
+			want: []core.Token{
+				{ID: core.TokenIDDoubleQuote, Lexeme: `"`},
+				{ID: core.TokenIDString, Lexeme: "a b"},
+				{ID: core.TokenIDDoubleQuote, Lexeme: `"`},
+			},
+		},
+		{
+			name:  "Unterminated string",
+			input: "'abc",
+			want: []core.Token{
+				{ID: core.TokenIDSingleQuote, Lexeme: "'"},
+				{ID: core.TokenIDString, Lexeme: "abc"},
+			},
+		},
+		{
+			name:  "Keyword prefix at the end of the input",
+			input: "selec",
+			want: []core.Token{
+				{ID: core.TokenIDString, Lexeme: "selec"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := NewLexer(tt.input).Lex()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(core.Token{}, "Pos", "Line", "Col")); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,62 @@
+package sqlite
+
+import (
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// keywords maps the keywords of SQLite in lower case to their token ID.
+var keywords = map[string]core.TokenID{
+	"now()":          core.TokenIDNow,
+	"unique":         core.TokenIDUnique,
+	"localtimestamp": core.TokenIDLocalTimestamp,
+	"default":        core.TokenIDDefault,
+	"true":           core.TokenIDTrue,
+	"false":          core.TokenIDFalse,
+	"asc":            core.TokenIDAsc,
+	"desc":           core.TokenIDDesc,
+	"and":            core.TokenIDAnd,
+	"or":             core.TokenIDOr,
+	"in":             core.TokenIDIn,
+	"returning":      core.TokenIDReturning,
+	"truncate":       core.TokenIDTruncate,
+	"drop":           core.TokenIDDrop,
+	"grant":          core.TokenIDGrant,
+	"is":             core.TokenIDIs,
+	"for":            core.TokenIDFor,
+	"limit":          core.TokenIDLimit,
+	"order":          core.TokenIDOrder,
+	"by":             core.TokenIDBy,
+	"set":            core.TokenIDSet,
+	"update":         core.TokenIDUpdate,
+	"create":         core.TokenIDCreate,
+	"select":         core.TokenIDSelect,
+	"distinct":       core.TokenIDDistinct,
+	"insert":         core.TokenIDInsert,
+	"from":           core.TokenIDFrom,
+	"where":          core.TokenIDWhere,
+	"table":          core.TokenIDTable,
+	"null":           core.TokenIDNull,
+	"if":             core.TokenIDIf,
+	"not":            core.TokenIDNot,
+	"exists":         core.TokenIDExists,
+	"count":          core.TokenIDCount,
+	"delete":         core.TokenIDDelete,
+	"auto_increment": core.TokenIDAutoincrement,
+	"autoincrement":  core.TokenIDAutoincrement,
+	"primary":        core.TokenIDPrimary,
+	"key":            core.TokenIDKey,
+	"into":           core.TokenIDInto,
+	"values":         core.TokenIDValues,
+	"join":           core.TokenIDJoin,
+	"on":             core.TokenIDOn,
+	"offset":         core.TokenIDOffset,
+	"index":          core.TokenIDIndex,
+	"replace":        core.TokenIDReplace,
+	"ignore":         core.TokenIDIgnore,
+	"collate":        core.TokenIDCollate,
+	"nocase":         core.TokenIDNocase,
+}
+
+func init() { //nolint:gochecknoinits
+	core.RegisterKeywords(core.SQLSyntaxModeSQLite, keywords)
+}
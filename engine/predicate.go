@@ -14,6 +14,8 @@ type Value struct {
 	lexeme   string
 	constant bool
 	table    string
+	// nocase is true if the value is compared case-insensitively (COLLATE NOCASE)
+	nocase bool
 }
 
 // Predicate evaluate if a condition is valid with 2 values and an operator on this 2 values
@@ -56,35 +58,28 @@ func (p *Predicate) Eval(row virtualRow) (bool, error) {
 	}
 
 	// Find left attribute
-	left := p.LeftValue.table + "." + p.LeftValue.lexeme
-	val, ok := row[left]
+	attr := p.LeftValue.table + "." + p.LeftValue.lexeme
+	val, ok := row[attr]
 	if !ok {
-		return false, fmt.Errorf("attribute [%s] not found in row", left)
+		return false, fmt.Errorf("attribute [%s] not found in row", attr)
 	}
 	p.LeftValue.v = val.v
 
-	return p.Operator(p.LeftValue, p.RightValue), nil
+	left := p.LeftValue
+	left.nocase = left.nocase || val.nocase
+	return p.Operator(left, p.RightValue), nil
 }
 
-// Evaluate is deprecated (see Eval). It calls operators and use tuple as operand
-// TODO: Delete that
-func (p *Predicate) Evaluate(t *Tuple, table *Table) (bool, error) {
-	if p.True {
-		return true, nil
-	}
-
-	// Find left
-	var i int
-	lenTable := len(table.attributes)
-	for i = 0; i < lenTable; i++ {
-		if table.attributes[i].name == p.LeftValue.lexeme {
-			break
+// matchTuple returns true if the tuple of the relation validates all predicates. They are
+// evaluated on the virtual row of the tuple, like the WHERE clause of a SELECT statement.
+func matchTuple(r *Relation, t *Tuple, predicates []Predicate) (bool, error) {
+	row := make(virtualRow)
+	row.addTuple(r, t)
+	for _, predicate := range predicates {
+		res, err := predicate.Eval(row)
+		if err != nil || !res {
+			return false, err
 		}
 	}
-	if i == lenTable {
-		return false, fmt.Errorf("attribute [%s] not found in table [%s]", p.LeftValue.lexeme, table.name)
-	}
-
-	p.LeftValue.v = t.Values[i]
-	return p.Operator(p.LeftValue, p.RightValue), nil
+	return true, nil
 }
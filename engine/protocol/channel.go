@@ -0,0 +1,376 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// ErrConnClosed means "the connection is already closed"
+var ErrConnClosed = errors.New("connection closed")
+
+// messageType is the type of a message exchanged on channels.
+type messageType int
+
+const (
+	// errMessage carries an error message.
+	errMessage messageType = iota
+	// queryMessage carries a statement expected to return rows.
+	queryMessage
+	// execMessage carries a statement expected to return a result.
+	execMessage
+	// resultMessage carries the last inserted ID and the number of rows affected.
+	resultMessage
+	// rowHeaderMessage carries the column names of a row set.
+	rowHeaderMessage
+	// rowValueMessage carries a single row.
+	rowValueMessage
+	// rowEndMessage ends a row set.
+	rowEndMessage
+	// dialectMessage carries the SQL syntax mode of a network connection, see SyntaxModeOfDSN.
+	dialectMessage
+)
+
+// message is the unit exchanged between ChannelDriverConn and ChannelEngineConn.
+type message struct {
+	// Type is the type of the message.
+	Type messageType
+	// Value is the payload of the message.
+	Value []string
+	// Null flags the NULL values of a row value message, whose Value is empty.
+	// NULL is sent out of band, so that any text is a value. It is nil if no value is NULL.
+	Null []bool
+}
+
+// rowMessage returns the row value message of a row, whose NULL values are nil.
+func rowMessage(row []*string) message {
+	m := message{Type: rowValueMessage, Value: make([]string, len(row))}
+	for i, v := range row {
+		if v == nil {
+			if m.Null == nil {
+				m.Null = make([]bool, len(row))
+			}
+			m.Null[i] = true
+			continue
+		}
+		m.Value[i] = *v
+	}
+	return m
+}
+
+// row returns the values of a message, NULL values are nil.
+func (m message) row() []*string {
+	row := make([]*string, len(m.Value))
+	for i := range m.Value {
+		if i < len(m.Null) && m.Null[i] {
+			continue
+		}
+		row[i] = &m.Value[i]
+	}
+	return row
+}
+
+// channelPair is the pair of channels of a connection.
+// Each side only writes to one of them, so a driver may send
+// a statement while a previous row set is still buffered.
+type channelPair struct {
+	// toEngine carries statements from the driver to the engine.
+	toEngine chan message
+	// toDriver carries results, errors and rows from the engine to the driver.
+	toDriver chan message
+	// closed is closed with the driver connection, so that the engine
+	// never blocks writing to a driver that is gone.
+	closed chan struct{}
+	// mode is the SQL syntax mode of the statements, selected by the DSN.
+	mode core.SQLSyntaxMode
+}
+
+// ChannelDriverConn implements DriverConn for channel backend.
+type ChannelDriverConn struct {
+	// conn is the channel pair shared with the engine.
+	conn *channelPair
+	// once ensures the connection is closed only once.
+	once sync.Once
+}
+
+// WriteQuery sends a statement that is expected to return rows.
+func (c *ChannelDriverConn) WriteQuery(query string) error {
+	return c.write(message{Type: queryMessage, Value: []string{query}})
+}
+
+// WriteExec sends a statement that is expected to return a result.
+func (c *ChannelDriverConn) WriteExec(stmt string) error {
+	return c.write(message{Type: execMessage, Value: []string{stmt}})
+}
+
+// write sends a message to the engine.
+func (c *ChannelDriverConn) write(m message) error {
+	if c.conn == nil {
+		return ErrConnClosed
+	}
+	select {
+	case <-c.conn.closed:
+		return ErrConnClosed
+	default:
+	}
+	c.conn.toEngine <- m
+	return nil
+}
+
+// ReadResult reads the result of a statement sent with WriteExec.
+func (c *ChannelDriverConn) ReadResult() (lastInsertedID int64, rowsAffected int64, err error) {
+	m, err := c.read()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	switch m.Type {
+	case resultMessage:
+	case rowHeaderMessage:
+		// The statement returned rows (e.g. INSERT ... RETURNING), discard them.
+		rows := bufferRows(c.conn.toDriver, m)
+		<-rows
+		for range rows {
+			rowsAffected++
+		}
+		return 0, rowsAffected, nil
+	default:
+		return 0, 0, fmt.Errorf("protocol error: ReadResult received %v", m)
+	}
+	return parseResult(m)
+}
+
+// parseResult returns the last inserted ID and the number of rows affected carried by a result message.
+func parseResult(m message) (lastInsertedID int64, rowsAffected int64, err error) {
+	if len(m.Value) != 2 {
+		return 0, 0, fmt.Errorf("protocol error: result has %d values, want 2", len(m.Value))
+	}
+	lastInsertedID, err = strconv.ParseInt(m.Value[0], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("protocol error: %w", err)
+	}
+	rowsAffected, err = strconv.ParseInt(m.Value[1], 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("protocol error: %w", err)
+	}
+	return lastInsertedID, rowsAffected, nil
+}
+
+// ReadRows reads the rows of a statement sent with WriteQuery.
+func (c *ChannelDriverConn) ReadRows() (chan []*string, error) {
+	m, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+
+	switch m.Type {
+	case rowHeaderMessage:
+		return bufferRows(c.conn.toDriver, m), nil
+	case resultMessage:
+		// The statement did not return rows (e.g. INSERT), return an empty set.
+		ch := make(chan []*string, 1)
+		ch <- []*string{}
+		close(ch)
+		return ch, nil
+	default:
+		return nil, fmt.Errorf("protocol error: ReadRows received %v", m)
+	}
+}
+
+// read reads the next message from the engine. An error message is returned as error.
+func (c *ChannelDriverConn) read() (message, error) {
+	if c.conn == nil {
+		return message{}, ErrConnClosed
+	}
+
+	m, ok := <-c.conn.toDriver
+	if !ok {
+		return message{}, ErrConnClosed
+	}
+	if m.Type == errMessage {
+		return message{}, errors.New(m.Value[0])
+	}
+	return m, nil
+}
+
+// Close closes the connection. The engine receives io.EOF.
+func (c *ChannelDriverConn) Close() {
+	c.once.Do(func() {
+		if c.conn != nil {
+			close(c.conn.closed)
+			close(c.conn.toEngine)
+		}
+	})
+}
+
+// bufferRows reads every row of a row set in background and returns them,
+// header first, on a channel closed after the last row. The engine is never
+// blocked by a driver that stops reading rows.
+func bufferRows(conn chan message, header message) chan []*string {
+	out := make(chan []*string)
+
+	go func() {
+		defer close(out)
+
+		rows := [][]*string{header.row()}
+		in := conn
+		for len(rows) > 0 || in != nil {
+			var first []*string
+			var sendTo chan []*string
+			if len(rows) > 0 {
+				first = rows[0]
+				sendTo = out
+			}
+
+			select {
+			case m, ok := <-in:
+				if !ok || m.Type != rowValueMessage {
+					// rowEndMessage or closed connection: no more rows to read
+					in = nil
+					continue
+				}
+				rows = append(rows, m.row())
+			case sendTo <- first:
+				rows = rows[1:]
+			}
+		}
+	}()
+	return out
+}
+
+// ChannelEngineConn implements EngineConn and SyntaxModeConn for channel backend.
+type ChannelEngineConn struct {
+	// conn is the channel pair shared with the driver.
+	conn *channelPair
+}
+
+// ReadStatement reads the next statement. It returns io.EOF once the driver is closed.
+func (c *ChannelEngineConn) ReadStatement() (string, error) {
+	m, ok := <-c.conn.toEngine
+	if !ok {
+		return "", io.EOF
+	}
+	return m.Value[0], nil
+}
+
+// SyntaxMode returns the SQL syntax mode selected by the DSN of the driver.
+func (c *ChannelEngineConn) SyntaxMode() core.SQLSyntaxMode {
+	return c.conn.mode
+}
+
+// WriteResult writes the result of a statement.
+func (c *ChannelEngineConn) WriteResult(lastInsertedID int64, rowsAffected int64) error {
+	return c.write(message{
+		Type: resultMessage,
+		Value: []string{
+			strconv.FormatInt(lastInsertedID, 10),
+			strconv.FormatInt(rowsAffected, 10),
+		},
+	})
+}
+
+// WriteError writes an error.
+func (c *ChannelEngineConn) WriteError(err error) error {
+	return c.write(message{Type: errMessage, Value: []string{err.Error()}})
+}
+
+// WriteRowHeader writes the header of a row set.
+func (c *ChannelEngineConn) WriteRowHeader(header []string) error {
+	return c.write(message{Type: rowHeaderMessage, Value: header})
+}
+
+// WriteRow writes a row of a row set.
+func (c *ChannelEngineConn) WriteRow(row []*string) error {
+	return c.write(rowMessage(row))
+}
+
+// WriteRowEnd ends a row set.
+func (c *ChannelEngineConn) WriteRowEnd() error {
+	return c.write(message{Type: rowEndMessage})
+}
+
+// write sends a message to the driver.
+func (c *ChannelEngineConn) write(m message) error {
+	select {
+	case c.conn.toDriver <- m:
+		return nil
+	case <-c.conn.closed:
+		return ErrConnClosed
+	}
+}
+
+// ChannelDriverEndpoint implements DriverEndpoint for channel backend.
+type ChannelDriverEndpoint struct {
+	// newConnChannel sends new connections to the engine endpoint.
+	newConnChannel chan<- *channelPair
+	// closed is closed with the engine endpoint.
+	closed <-chan struct{}
+}
+
+// New creates a new connection to the engine. Only the dialect option of the DSN
+// is used, see SyntaxModeOfDSN.
+func (e *ChannelDriverEndpoint) New(dsn string) (DriverConn, error) {
+	mode, err := SyntaxModeOfDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+	pair := &channelPair{
+		toEngine: make(chan message),
+		toDriver: make(chan message),
+		closed:   make(chan struct{}),
+		mode:     mode,
+	}
+
+	select {
+	case e.newConnChannel <- pair:
+		return &ChannelDriverConn{conn: pair}, nil
+	case <-e.closed:
+		return nil, ErrConnClosed
+	}
+}
+
+// ChannelEngineEndpoint implements EngineEndpoint for channel backend.
+type ChannelEngineEndpoint struct {
+	// newConnChannel receives new connections from the driver endpoint.
+	newConnChannel <-chan *channelPair
+	// closed is closed when the endpoint is closed.
+	closed chan struct{}
+	// once ensures the endpoint is closed only once.
+	once sync.Once
+}
+
+// Accept waits for a new connection. It returns io.EOF once the endpoint is closed.
+func (e *ChannelEngineEndpoint) Accept() (EngineConn, error) {
+	select {
+	case pair := <-e.newConnChannel:
+		return &ChannelEngineConn{conn: pair}, nil
+	case <-e.closed:
+		return nil, io.EOF
+	}
+}
+
+// Close closes the endpoint. Pending and future Accept calls return io.EOF.
+func (e *ChannelEngineEndpoint) Close() {
+	e.once.Do(func() {
+		close(e.closed)
+	})
+}
+
+// NewChannelEndpoints returns a pair of endpoints connected with channels.
+// Connections created with the DriverEndpoint are accepted by the EngineEndpoint.
+func NewChannelEndpoints() (DriverEndpoint, EngineEndpoint) {
+	newConnChannel := make(chan *channelPair)
+	closed := make(chan struct{})
+
+	return &ChannelDriverEndpoint{
+		newConnChannel: newConnChannel,
+		closed:         closed,
+	}, &ChannelEngineEndpoint{
+		newConnChannel: newConnChannel,
+		closed:         closed,
+	}
+}
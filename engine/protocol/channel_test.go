@@ -0,0 +1,121 @@
+package protocol
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// text returns a non-NULL value of a row.
+func text(s string) *string {
+	return &s
+}
+
+// serve answers each statement of conn: "rows" returns two rows,
+// "fail" returns an error and anything else returns a result.
+func serve(t *testing.T, conn EngineConn) {
+	t.Helper()
+
+	for {
+		stmt, err := conn.ReadStatement()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		if err != nil {
+			t.Error(err)
+			return
+		}
+
+		switch stmt {
+		case "rows":
+			_ = conn.WriteRowHeader([]string{"id", "name"})
+			_ = conn.WriteRow([]*string{text("1"), text("<nil>")})
+			_ = conn.WriteRow([]*string{text("2"), nil})
+			_ = conn.WriteRowEnd()
+		case "fail":
+			_ = conn.WriteError(errors.New("failed"))
+		default:
+			_ = conn.WriteResult(4, 2)
+		}
+	}
+}
+
+func TestChannelEndpoints(t *testing.T) {
+	t.Parallel()
+
+	driverEndpoint, engineEndpoint := NewChannelEndpoints()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		conn, err := engineEndpoint.Accept()
+		if err != nil {
+			t.Error(err)
+			return
+		}
+		serve(t, conn)
+	}()
+
+	conn, err := driverEndpoint.New("testdb")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("exec", func(t *testing.T) {
+		if err := conn.WriteExec("insert"); err != nil {
+			t.Fatal(err)
+		}
+		id, affected, err := conn.ReadResult()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if id != 4 || affected != 2 {
+			t.Errorf("mismatch result: want=(4, 2), got=(%d, %d)", id, affected)
+		}
+	})
+
+	t.Run("query", func(t *testing.T) {
+		if err := conn.WriteQuery("rows"); err != nil {
+			t.Fatal(err)
+		}
+		rows, err := conn.ReadRows()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := [][]*string{}
+		for r := range rows {
+			got = append(got, r)
+		}
+		want := [][]*string{{text("id"), text("name")}, {text("1"), text("<nil>")}, {text("2"), nil}}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		if err := conn.WriteQuery("fail"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := conn.ReadRows(); err == nil || err.Error() != "failed" {
+			t.Errorf("mismatch error: want=failed, got=%v", err)
+		}
+	})
+
+	t.Run("close", func(t *testing.T) {
+		conn.Close()
+		<-done
+
+		if err := conn.WriteExec("insert"); !errors.Is(err, ErrConnClosed) {
+			t.Errorf("mismatch error: want=%v, got=%v", ErrConnClosed, err)
+		}
+
+		engineEndpoint.Close()
+		if _, err := engineEndpoint.Accept(); !errors.Is(err, io.EOF) {
+			t.Errorf("mismatch error: want=%v, got=%v", io.EOF, err)
+		}
+		if _, err := driverEndpoint.New("testdb"); !errors.Is(err, ErrConnClosed) {
+			t.Errorf("mismatch error: want=%v, got=%v", ErrConnClosed, err)
+		}
+	})
+}
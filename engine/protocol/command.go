@@ -0,0 +1,131 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/nao1215/aiondb/engine/parser/postgres"
+)
+
+// ErrRollbackNotSupported means "the engine cannot undo a transaction"
+var ErrRollbackNotSupported = errors.New("rollback is not supported")
+
+// Command returns the command of a statement, used as the command tag of its result.
+// Like PostgreSQL, CREATE, DROP and TRUNCATE are followed by the kind of object (e.g. "CREATE TABLE").
+// The comments before the statement are skipped.
+func Command(stmt string) string {
+	words := strings.Fields(strings.ToUpper(skipComments(stmt)))
+	if len(words) == 0 {
+		return ""
+	}
+	switch words[0] {
+	case "CREATE", "DROP":
+		if len(words) > 1 {
+			return words[0] + " " + words[1]
+		}
+	case "TRUNCATE":
+		return "TRUNCATE TABLE"
+	}
+	return words[0]
+}
+
+// skipComments returns the statement without its leading spaces and comments.
+func skipComments(stmt string) string {
+	var scanner postgres.Scanner
+	for i := 0; i < len(stmt); {
+		if unicode.IsSpace(rune(stmt[i])) {
+			i++
+			continue
+		}
+		end, kind := scanner.Next(stmt, i)
+		if kind != postgres.ScanComment {
+			return stmt[i:]
+		}
+		i = end
+	}
+	return ""
+}
+
+// CommandTag returns the PostgreSQL command tag of a statement result (e.g. "INSERT 0 1").
+func CommandTag(command string, rowsAffected int64) string {
+	switch command {
+	case "INSERT":
+		return fmt.Sprintf("INSERT 0 %d", rowsAffected)
+	case "SELECT", "UPDATE", "DELETE":
+		return fmt.Sprintf("%s %d", command, rowsAffected)
+	default:
+		return command
+	}
+}
+
+// sessionCommand returns the command of a transaction or session statement
+// (BEGIN, COMMIT, SET, ...) answered by the wire protocol endpoints without the
+// engine, which applies statements immediately and has no session settings.
+// A rollback is answered with ErrRollbackNotSupported.
+// It returns false for the statements run by the engine.
+func sessionCommand(stmt string) (string, bool, error) {
+	switch command := Command(stmt); command {
+	case "BEGIN", "COMMIT", "SET":
+		return command, true, nil
+	case "START":
+		return "START TRANSACTION", true, nil
+	case "END":
+		return "COMMIT", true, nil
+	case "ROLLBACK", "ABORT":
+		return "ROLLBACK", true, ErrRollbackNotSupported
+	default:
+		return "", false, nil
+	}
+}
+
+// errorClass is the class of an error of the engine, translated to the
+// error codes of the wire protocols.
+type errorClass int
+
+const (
+	// errorInternal is an error without a more specific class.
+	errorInternal errorClass = iota
+	// errorUniqueViolation is a violation of a unique or primary key constraint.
+	errorUniqueViolation
+	// errorNotNullViolation is a violation of a not-null constraint.
+	errorNotNullViolation
+	// errorDuplicateTable is the creation of an existing table.
+	errorDuplicateTable
+	// errorUndefinedTable is a reference to a missing table.
+	errorUndefinedTable
+	// errorUndefinedColumn is a reference to a missing column.
+	errorUndefinedColumn
+	// errorNotSupported is a feature the engine does not support.
+	errorNotSupported
+	// errorSyntax is a statement the parser cannot parse.
+	errorSyntax
+)
+
+// classifyError returns the class of an error of the engine.
+// The engine does not classify its errors, the class is guessed from the message.
+func classifyError(err error) errorClass {
+	if errors.Is(err, ErrRollbackNotSupported) {
+		return errorNotSupported
+	}
+
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "duplicate key value"):
+		return errorUniqueViolation
+	case strings.Contains(msg, "violates not-null constraint"):
+		return errorNotNullViolation
+	case strings.Contains(msg, "already exists"):
+		return errorDuplicateTable
+	case strings.HasPrefix(msg, "table") && strings.Contains(msg, "does not exist"),
+		strings.HasPrefix(msg, "relation") && strings.Contains(msg, "not found"):
+		return errorUndefinedTable
+	case strings.HasPrefix(msg, "attribute") && strings.Contains(msg, "does not exist"):
+		return errorUndefinedColumn
+	case strings.Contains(msg, "syntax error"), strings.Contains(msg, "parsing failed"), strings.Contains(msg, "parsing error"):
+		return errorSyntax
+	default:
+		return errorInternal
+	}
+}
@@ -0,0 +1,30 @@
+package protocol
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// dialectOption is the DSN option selecting the SQL syntax mode of a connection
+// (e.g. "aiondb://testdb?dialect=mysql").
+const dialectOption = "dialect"
+
+// SyntaxModeOfDSN returns the SQL syntax mode selected by the dialect option of a DSN.
+// Statements are written in the PostgreSQL syntax if the DSN has no dialect option.
+func SyntaxModeOfDSN(dsn string) (core.SQLSyntaxMode, error) {
+	_, query, ok := strings.Cut(dsn, "?")
+	if !ok {
+		return core.SQLSyntaxModePostgreSQL, nil
+	}
+	options, err := url.ParseQuery(query)
+	if err != nil {
+		return core.SQLSyntaxModeDefault, fmt.Errorf("invalid options in DSN %q: %w", dsn, err)
+	}
+	if !options.Has(dialectOption) {
+		return core.SQLSyntaxModePostgreSQL, nil
+	}
+	return core.ParseSQLSyntaxMode(options.Get(dialectOption))
+}
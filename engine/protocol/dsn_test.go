@@ -0,0 +1,41 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+func TestSyntaxModeOfDSN(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		dsn     string
+		want    core.SQLSyntaxMode
+		wantErr bool
+	}{
+		{name: "no option", dsn: "aiondb://testdb", want: core.SQLSyntaxModePostgreSQL},
+		{name: "other option", dsn: "testdb?timeout=1s", want: core.SQLSyntaxModePostgreSQL},
+		{name: "mysql", dsn: "aiondb://testdb?dialect=mysql", want: core.SQLSyntaxModeMySQL},
+		{name: "sqlite on a network DSN", dsn: "tcp://127.0.0.1:5433?timeout=1s&dialect=SQLite", want: core.SQLSyntaxModeSQLite},
+		{name: "oracle", dsn: "unix:///tmp/aion.sock?dialect=oracle", want: core.SQLSyntaxModeOracle},
+		{name: "postgresql", dsn: "testdb?dialect=postgresql", want: core.SQLSyntaxModePostgreSQL},
+		{name: "unknown dialect", dsn: "testdb?dialect=db2", wantErr: true},
+		{name: "invalid options", dsn: "testdb?dialect=%zz", wantErr: true},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := SyntaxModeOfDSN(tt.dsn)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("mismatch syntax mode: want=%s, got=%s", tt.want, got)
+			}
+		})
+	}
+}
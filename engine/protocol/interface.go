@@ -3,13 +3,25 @@
 // The engine is the server and the driver is the client. The driver is the only one to initiate a connection.
 package protocol
 
+import "github.com/nao1215/aiondb/engine/parser/core"
+
 // DriverConn is a networking helper hiding implementation
 // either with channels or network sockets.
+// Each call of WriteQuery or WriteExec gets a single reply, read by ReadRows or
+// ReadResult, even if the string holds several statements.
 type DriverConn interface {
+	// WriteQuery sends a statement that is expected to return rows.
 	WriteQuery(query string) error
+	// WriteExec sends a statement that is expected to return a result.
 	WriteExec(stmt string) error
+	// ReadResult reads the result of a statement sent with WriteExec.
 	ReadResult() (lastInsertedID int64, rowsAffected int64, err error)
-	ReadRows() (chan []string, error)
+	// ReadRows reads the rows of a statement sent with WriteQuery.
+	// The first value sent on the channel is the row header (column names),
+	// the following values are the rows, whose NULL values are nil.
+	// The channel is closed after the last row.
+	ReadRows() (chan []*string, error)
+	// Close closes the connection.
 	Close()
 }
 
@@ -20,10 +32,26 @@ type EngineConn interface {
 	WriteResult(lastInsertedID int64, rowsAffected int64) error
 	WriteError(err error) error
 	WriteRowHeader(header []string) error
-	WriteRow(row []string) error
+	// WriteRow writes a row, NULL values are nil.
+	WriteRow(row []*string) error
 	WriteRowEnd() error
 }
 
+// SyntaxModeConn is implemented by the EngineConn whose statements are not
+// written in the PostgreSQL syntax (e.g. the connections of MySQL clients).
+type SyntaxModeConn interface {
+	// SyntaxMode returns the SQL syntax mode of the statements of the connection.
+	SyntaxMode() core.SQLSyntaxMode
+}
+
+// DescribeConn is implemented by the EngineConn whose statements may be described
+// instead of run (e.g. the prepared statements of PostgreSQL clients).
+type DescribeConn interface {
+	// Describing returns true if the statement read last must only be described:
+	// the engine writes the row header of its result, if it returns rows, without running it.
+	Describing() bool
+}
+
 // EngineEndpoint is the query entrypoint of RamSQL engine.
 type EngineEndpoint interface {
 	Accept() (EngineConn, error)
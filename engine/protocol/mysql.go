@@ -0,0 +1,838 @@
+package protocol
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// The MySQL endpoint speaks the MySQL client/server protocol, so that MySQL
+// clients (go-sql-driver/mysql, the mysql command, ...) can connect to an AION DB
+// engine. Statements are parsed in the MySQL syntax mode.
+// See https://dev.mysql.com/doc/dev/mysql-server/latest/PAGE_PROTOCOL.html.
+//
+// Supported features are the handshake without authentication (any user and
+// password are accepted, TLS is not offered), COM_QUERY with multiple statements,
+// COM_STMT_PREPARE, COM_STMT_EXECUTE and COM_STMT_CLOSE, with text and binary
+// resultsets. Every column is described as VAR_STRING.
+
+const (
+	// myServerVersion is the server version sent in the handshake.
+	myServerVersion = "8.0.0-aiondb"
+	// myAuthPlugin is the authentication plugin announced in the handshake.
+	myAuthPlugin = "mysql_native_password"
+	// myCharset is the utf8mb4_general_ci collation.
+	myCharset = 45
+	// myMaxPacketSize is the maximum size of a packet payload, larger payloads are split.
+	myMaxPacketSize = 1<<24 - 1
+)
+
+// Capability flags of the MySQL protocol.
+const (
+	myClientLongPassword     = 0x00000001
+	myClientFoundRows        = 0x00000002
+	myClientLongFlag         = 0x00000004
+	myClientConnectWithDB    = 0x00000008
+	myClientProtocol41       = 0x00000200
+	myClientTransactions     = 0x00002000
+	myClientSecureConnection = 0x00008000
+	myClientMultiStatements  = 0x00010000
+	myClientMultiResults     = 0x00020000
+	myClientPluginAuth       = 0x00080000
+	// myCapabilities are the capabilities of the server.
+	myCapabilities = myClientLongPassword | myClientFoundRows | myClientLongFlag |
+		myClientConnectWithDB | myClientProtocol41 | myClientTransactions |
+		myClientSecureConnection | myClientMultiStatements | myClientMultiResults |
+		myClientPluginAuth
+)
+
+// Status flags of the MySQL protocol.
+const (
+	// myStatusAutocommit means that statements are applied immediately.
+	myStatusAutocommit = 0x0002
+	// myStatusMoreResultsExists means that another result of the same query follows.
+	myStatusMoreResultsExists = 0x0008
+)
+
+// Commands of the MySQL protocol.
+const (
+	myComQuit        = 0x01
+	myComInitDB      = 0x02
+	myComQuery       = 0x03
+	myComPing        = 0x0e
+	myComStmtPrepare = 0x16
+	myComStmtExecute = 0x17
+	myComStmtClose   = 0x19
+	myComStmtReset   = 0x1a
+	myComSetOption   = 0x1b
+)
+
+// Column types of the MySQL protocol, used to decode binary parameters.
+const (
+	myTypeTiny      = 0x01
+	myTypeShort     = 0x02
+	myTypeLong      = 0x03
+	myTypeFloat     = 0x04
+	myTypeDouble    = 0x05
+	myTypeNull      = 0x06
+	myTypeTimestamp = 0x07
+	myTypeLongLong  = 0x08
+	myTypeInt24     = 0x09
+	myTypeDate      = 0x0a
+	myTypeTime      = 0x0b
+	myTypeDateTime  = 0x0c
+	myTypeYear      = 0x0d
+	myTypeVarString = 0xfd
+)
+
+// NewMySQLEngineEndpoint returns a new NetworkEngineEndpoint listening on the
+// network ("tcp" or "unix") and the address, whose connections speak the
+// MySQL client/server protocol.
+func NewMySQLEngineEndpoint(network string, address string) (*NetworkEngineEndpoint, error) {
+	return listen(network, address, func(conn net.Conn, id uint32, release func()) networkConn {
+		return &MySQLEngineConn{
+			conn:       conn,
+			r:          bufio.NewReader(conn),
+			w:          bufio.NewWriter(conn),
+			id:         id,
+			release:    release,
+			statements: make(map[uint32]*myStatement),
+		}
+	})
+}
+
+// MySQLEngineConn implements EngineConn for MySQL clients.
+// It implements SyntaxModeConn, its statements are parsed in the MySQL syntax mode.
+//
+// The statements of a COM_QUERY are handed to the engine one at a time by
+// ReadStatement, and their results are written to the client as they come.
+type MySQLEngineConn struct {
+	// conn is the socket connected to the client.
+	conn net.Conn
+	// r buffers the packets read from the client.
+	r *bufio.Reader
+	// w buffers the packets written to the client, it is flushed at the end of each result.
+	w *bufio.Writer
+	// id is the connection ID sent in the handshake.
+	id uint32
+	// release is called once when the connection is closed.
+	release func()
+	// once ensures the connection is closed only once.
+	once sync.Once
+	// started is true once the handshake is done.
+	started bool
+	// seq is the sequence ID of the next packet.
+	seq byte
+	// statements are the prepared statements, keyed by ID.
+	statements map[uint32]*myStatement
+	// lastID is the ID of the last prepared statement.
+	lastID uint32
+	// queue is the list of statements of a COM_QUERY not run yet.
+	queue []string
+	// binary is true if the running statement answers COM_STMT_EXECUTE.
+	binary bool
+	// running is true while a statement is run by the engine.
+	running bool
+	// answered is true once the result of the running statement is written.
+	answered bool
+	// columns is the number of columns of the running resultset.
+	columns int
+}
+
+// myStatement is a statement prepared with COM_STMT_PREPARE.
+type myStatement struct {
+	// query is the statement with its ? parameters.
+	query string
+	// params is the number of parameters.
+	params int
+	// types are the types of the parameters (type and unsigned flag) sent
+	// by the last COM_STMT_EXECUTE, following executions may omit them.
+	types []byte
+}
+
+// SyntaxMode returns core.SQLSyntaxModeMySQL.
+func (c *MySQLEngineConn) SyntaxMode() core.SQLSyntaxMode {
+	return core.SQLSyntaxModeMySQL
+}
+
+// ReadStatement handles the commands of the client until a statement must be run by the engine.
+// It returns io.EOF once the client quits.
+func (c *MySQLEngineConn) ReadStatement() (string, error) {
+	if !c.started {
+		if err := c.handshake(); err != nil {
+			c.close()
+			return "", eof(err)
+		}
+		c.started = true
+	}
+
+	if c.running && !c.answered {
+		// The engine wrote nothing (e.g. a statement without declaration)
+		c.writeOK(0, 0)
+		if err := c.w.Flush(); err != nil {
+			c.close()
+			return "", eof(err)
+		}
+	}
+	c.running = false
+
+	for {
+		if len(c.queue) > 0 {
+			stmt := c.queue[0]
+			c.queue = c.queue[1:]
+			if c.run(stmt) {
+				return stmt, nil
+			}
+			if err := c.w.Flush(); err != nil {
+				c.close()
+				return "", eof(err)
+			}
+			continue
+		}
+
+		payload, err := c.readPacket()
+		if err != nil {
+			c.close()
+			return "", eof(err)
+		}
+		stmt, ok, err := c.handle(payload)
+		if err != nil {
+			c.close()
+			return "", eof(err)
+		}
+		if ok {
+			return stmt, nil
+		}
+		if err := c.w.Flush(); err != nil {
+			c.close()
+			return "", eof(err)
+		}
+	}
+}
+
+// handshake sends the initial handshake and accepts the answer of the client.
+func (c *MySQLEngineConn) handshake() error {
+	// The scramble is not checked, any password is accepted
+	scramble := []byte("aiondb-scramble-0000")
+
+	b := []byte{10} // protocol version
+	b = appendCString(b, myServerVersion)
+	b = binary.LittleEndian.AppendUint32(b, c.id)
+	b = append(b, scramble[:8]...)
+	b = append(b, 0)
+	b = binary.LittleEndian.AppendUint16(b, uint16(myCapabilities&0xffff))
+	b = append(b, myCharset)
+	b = binary.LittleEndian.AppendUint16(b, myStatusAutocommit)
+	b = binary.LittleEndian.AppendUint16(b, uint16(myCapabilities>>16))
+	b = append(b, byte(len(scramble)+1))
+	b = append(b, make([]byte, 10)...)
+	b = append(b, scramble[8:]...)
+	b = append(b, 0)
+	b = appendCString(b, myAuthPlugin)
+	c.seq = 0
+	c.writePacket(b)
+	if err := c.w.Flush(); err != nil {
+		return err
+	}
+
+	payload, err := c.readPacket()
+	if err != nil {
+		return err
+	}
+	if len(payload) < 32 {
+		return errors.New("protocol error: handshake response is too short")
+	}
+	if binary.LittleEndian.Uint32(payload)&myClientProtocol41 == 0 {
+		c.writeError(errors.New("client does not support protocol 4.1"))
+		c.w.Flush() //nolint
+		return io.EOF
+	}
+	c.writeOK(0, 0)
+	return c.w.Flush()
+}
+
+// handle handles a command of the client. It returns the statement to run
+// and true if the command must be answered by the engine.
+func (c *MySQLEngineConn) handle(payload []byte) (string, bool, error) {
+	if len(payload) == 0 {
+		return "", false, errors.New("protocol error: empty command")
+	}
+
+	switch payload[0] {
+	case myComQuit:
+		return "", false, io.EOF
+	case myComInitDB, myComPing, myComStmtReset:
+		c.writeOK(0, 0)
+	case myComSetOption:
+		c.writeEOF(myStatusAutocommit)
+	case myComQuery:
+		stmts := splitMySQLQuery(string(payload[1:]))
+		if len(stmts) == 0 {
+			c.writeError(errors.New("query was empty"))
+			return "", false, nil
+		}
+		c.queue = stmts
+		c.binary = false
+		stmt := c.queue[0]
+		c.queue = c.queue[1:]
+		return stmt, c.run(stmt), nil
+	case myComStmtPrepare:
+		c.prepare(string(payload[1:]))
+	case myComStmtExecute:
+		query, err := c.bind(payload[1:])
+		if err != nil {
+			c.writeError(err)
+			return "", false, nil
+		}
+		c.queue = nil
+		c.binary = true
+		return query, c.run(query), nil
+	case myComStmtClose:
+		if len(payload) >= 5 {
+			delete(c.statements, binary.LittleEndian.Uint32(payload[1:]))
+		}
+	default:
+		c.writeError(fmt.Errorf("unsupported command 0x%02x", payload[0]))
+	}
+	return "", false, nil
+}
+
+// run prepares the connection to write the result of a statement.
+// Session statements are answered without the engine, see sessionCommand.
+// It returns true if the engine must run the statement.
+func (c *MySQLEngineConn) run(stmt string) bool {
+	c.running = true
+	c.answered = false
+	c.columns = 0
+
+	_, ok, err := sessionCommand(stmt)
+	if !ok {
+		return true
+	}
+	if err != nil {
+		c.WriteError(err) //nolint
+	} else {
+		c.WriteResult(0, 0) //nolint
+	}
+	c.running = false
+	return false
+}
+
+// prepare handles COM_STMT_PREPARE. The columns of the statement are only known
+// once it is executed, they are described in the resultset of COM_STMT_EXECUTE.
+func (c *MySQLEngineConn) prepare(query string) {
+	stmts := splitMySQLQuery(query)
+	if len(stmts) != 1 {
+		c.writeError(errors.New("prepared statement must contain exactly one statement"))
+		return
+	}
+
+	params := 0
+	replaceMySQLParameters(stmts[0], func(int) (string, error) { //nolint
+		params++
+		return "", nil
+	})
+	c.lastID++
+	c.statements[c.lastID] = &myStatement{query: stmts[0], params: params}
+
+	b := []byte{0}
+	b = binary.LittleEndian.AppendUint32(b, c.lastID)
+	b = binary.LittleEndian.AppendUint16(b, 0)              // columns
+	b = binary.LittleEndian.AppendUint16(b, uint16(params)) //nolint:gosec // the number of parameters is small
+	b = append(b, 0, 0, 0)                                  // filler, warnings
+	c.writePacket(b)
+	if params > 0 {
+		for i := 0; i < params; i++ {
+			c.writePacket(columnDefinition("?"))
+		}
+		c.writeEOF(myStatusAutocommit)
+	}
+}
+
+// bind decodes the parameters of COM_STMT_EXECUTE and returns the statement
+// with its parameters replaced by their values.
+func (c *MySQLEngineConn) bind(payload []byte) (string, error) {
+	if len(payload) < 9 {
+		return "", errors.New("protocol error: COM_STMT_EXECUTE is too short")
+	}
+	id := binary.LittleEndian.Uint32(payload)
+	stmt, ok := c.statements[id]
+	if !ok {
+		return "", fmt.Errorf("unknown prepared statement handler (%d) given to mysqld_stmt_execute", id)
+	}
+	if stmt.params == 0 {
+		return stmt.query, nil
+	}
+
+	msg := &myReader{buf: payload[9:]} // statement ID, flags, iteration count
+	nulls := msg.next((stmt.params + 7) / 8)
+	if msg.byte() == 1 { // new parameters bound
+		stmt.types = append([]byte{}, msg.next(2*stmt.params)...)
+	}
+	if msg.err != nil || len(stmt.types) != 2*stmt.params {
+		return "", errors.New("protocol error: malformed COM_STMT_EXECUTE")
+	}
+
+	values := make([]string, 0, stmt.params)
+	for i := 0; i < stmt.params; i++ {
+		if nulls[i/8]&(1<<(i%8)) != 0 {
+			values = append(values, "NULL")
+			continue
+		}
+		v, err := msg.value(stmt.types[2*i], stmt.types[2*i+1]&0x80 != 0)
+		if err != nil {
+			return "", fmt.Errorf("parameter %d: %w", i+1, err)
+		}
+		values = append(values, v)
+	}
+	return replaceMySQLParameters(stmt.query, func(i int) (string, error) {
+		return values[i], nil
+	})
+}
+
+// WriteResult writes an OK packet.
+func (c *MySQLEngineConn) WriteResult(lastInsertedID int64, rowsAffected int64) error {
+	c.answered = true
+	c.writeOK(rowsAffected, lastInsertedID)
+	return c.w.Flush()
+}
+
+// WriteError writes an ERR packet. The following statements of the query are not run.
+func (c *MySQLEngineConn) WriteError(err error) error {
+	c.answered = true
+	c.queue = nil
+	c.writeError(err)
+	return c.w.Flush()
+}
+
+// WriteRowHeader writes the column count and the column definitions of a resultset.
+func (c *MySQLEngineConn) WriteRowHeader(header []string) error {
+	c.columns = len(header)
+	c.writePacket(appendLengthEncodedInt(nil, uint64(len(header))))
+	for _, name := range header {
+		c.writePacket(columnDefinition(name))
+	}
+	c.writeEOF(myStatusAutocommit)
+	return nil
+}
+
+// WriteRow writes a row of a resultset, in the binary format for COM_STMT_EXECUTE.
+func (c *MySQLEngineConn) WriteRow(row []*string) error {
+	if !c.binary {
+		var b []byte
+		for _, v := range row {
+			if v == nil {
+				b = append(b, 0xfb)
+				continue
+			}
+			b = appendLengthEncodedString(b, *v)
+		}
+		c.writePacket(b)
+		return nil
+	}
+
+	// Binary row: header, NULL bitmap with an offset of 2 bits, then non-NULL values
+	b := []byte{0}
+	nulls := make([]byte, (c.columns+7+2)/8)
+	for i, v := range row {
+		if v == nil {
+			nulls[(i+2)/8] |= 1 << ((i + 2) % 8)
+		}
+	}
+	b = append(b, nulls...)
+	for _, v := range row {
+		if v != nil {
+			b = appendLengthEncodedString(b, *v)
+		}
+	}
+	c.writePacket(b)
+	return nil
+}
+
+// WriteRowEnd ends a resultset.
+func (c *MySQLEngineConn) WriteRowEnd() error {
+	c.answered = true
+	c.writeEOF(c.status())
+	return c.w.Flush()
+}
+
+// status returns the status flags of the end of a result.
+func (c *MySQLEngineConn) status() uint16 {
+	if len(c.queue) > 0 {
+		return myStatusAutocommit | myStatusMoreResultsExists
+	}
+	return myStatusAutocommit
+}
+
+// writeOK buffers an OK packet.
+func (c *MySQLEngineConn) writeOK(rowsAffected int64, lastInsertedID int64) {
+	b := []byte{0}
+	b = appendLengthEncodedInt(b, uint64(rowsAffected))   //nolint:gosec // counts are positive
+	b = appendLengthEncodedInt(b, uint64(lastInsertedID)) //nolint:gosec // IDs are positive
+	b = binary.LittleEndian.AppendUint16(b, c.status())
+	b = binary.LittleEndian.AppendUint16(b, 0) // warnings
+	c.writePacket(b)
+}
+
+// writeEOF buffers an EOF packet.
+func (c *MySQLEngineConn) writeEOF(status uint16) {
+	b := []byte{0xfe, 0, 0} // warnings
+	b = binary.LittleEndian.AppendUint16(b, status)
+	c.writePacket(b)
+}
+
+// writeError buffers an ERR packet.
+func (c *MySQLEngineConn) writeError(err error) {
+	code, state := mysqlError(err)
+	b := []byte{0xff}
+	b = binary.LittleEndian.AppendUint16(b, code)
+	b = append(b, '#')
+	b = append(b, state...)
+	b = append(b, err.Error()...)
+	c.writePacket(b)
+}
+
+// writePacket buffers a packet, split in several packets if it is too large.
+// Write errors are reported by the next flush.
+func (c *MySQLEngineConn) writePacket(payload []byte) {
+	for {
+		n := len(payload)
+		if n > myMaxPacketSize {
+			n = myMaxPacketSize
+		}
+		head := []byte{byte(n), byte(n >> 8), byte(n >> 16), c.seq}
+		c.seq++
+		c.w.Write(head)        //nolint
+		c.w.Write(payload[:n]) //nolint
+		payload = payload[n:]
+		if n < myMaxPacketSize {
+			return
+		}
+	}
+}
+
+// readPacket reads a packet of the client, joining split packets.
+// The sequence ID of the answer follows the one of the packet.
+func (c *MySQLEngineConn) readPacket() ([]byte, error) {
+	var payload []byte
+	for {
+		var head [4]byte
+		if _, err := io.ReadFull(c.r, head[:]); err != nil {
+			if payload != nil {
+				return nil, unexpectedEOF(err)
+			}
+			return nil, err
+		}
+		n := int(head[0]) | int(head[1])<<8 | int(head[2])<<16
+		if len(payload)+n > maxFrameSize {
+			return nil, fmt.Errorf("protocol error: packet larger than %d bytes", maxFrameSize)
+		}
+		c.seq = head[3] + 1
+
+		b := make([]byte, n)
+		if _, err := io.ReadFull(c.r, b); err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		payload = append(payload, b...)
+		if n < myMaxPacketSize {
+			return payload, nil
+		}
+	}
+}
+
+// close closes the socket and releases the connection.
+func (c *MySQLEngineConn) close() {
+	c.once.Do(func() {
+		c.conn.Close() //nolint
+		c.release()
+	})
+}
+
+// mysqlError returns the MySQL error code and SQLSTATE of an error of the engine.
+func mysqlError(err error) (uint16, string) {
+	switch classifyError(err) {
+	case errorUniqueViolation:
+		return 1062, "23000" // ER_DUP_ENTRY
+	case errorNotNullViolation:
+		return 1048, "23000" // ER_BAD_NULL_ERROR
+	case errorDuplicateTable:
+		return 1050, "42S01" // ER_TABLE_EXISTS_ERROR
+	case errorUndefinedTable:
+		return 1146, "42S02" // ER_NO_SUCH_TABLE
+	case errorUndefinedColumn:
+		return 1054, "42S22" // ER_BAD_FIELD_ERROR
+	case errorNotSupported:
+		return 1235, "42000" // ER_NOT_SUPPORTED_YET
+	case errorSyntax:
+		return 1064, "42000" // ER_PARSE_ERROR
+	default:
+		return 1105, "HY000" // ER_UNKNOWN_ERROR
+	}
+}
+
+// columnDefinition returns a Protocol::ColumnDefinition41 packet of a VAR_STRING column.
+func columnDefinition(name string) []byte {
+	b := appendLengthEncodedString(nil, "def") // catalog
+	b = appendLengthEncodedString(b, "")       // schema
+	b = appendLengthEncodedString(b, "")       // table
+	b = appendLengthEncodedString(b, "")       // original table
+	b = appendLengthEncodedString(b, name)
+	b = appendLengthEncodedString(b, name) // original name
+	b = append(b, 0x0c)
+	b = binary.LittleEndian.AppendUint16(b, myCharset)
+	b = binary.LittleEndian.AppendUint32(b, 1<<24-1) // column length
+	b = append(b, myTypeVarString)
+	b = binary.LittleEndian.AppendUint16(b, 0) // flags
+	b = append(b, 0, 0, 0)                     // decimals, filler
+	return b
+}
+
+// quoteMySQLString returns a MySQL string literal, escaped with backslashes.
+func quoteMySQLString(s string) string {
+	var b strings.Builder
+	b.WriteByte('\'')
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case 0:
+			b.WriteString(`\0`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\r':
+			b.WriteString(`\r`)
+		case 0x1a:
+			b.WriteString(`\Z`)
+		case '\'', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		default:
+			b.WriteByte(c)
+		}
+	}
+	b.WriteByte('\'')
+	return b.String()
+}
+
+// replaceMySQLParameters replaces the ? parameters of a query with the values
+// returned by replace, called with the index of the parameter starting at 0.
+// Parameters inside quotes and comments are left untouched.
+func replaceMySQLParameters(query string, replace func(i int) (string, error)) (string, error) {
+	var b strings.Builder
+	n := 0
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			end := mysqlQuoteEnd(query, i)
+			b.WriteString(query[i:end])
+			i = end - 1
+		case c == '?':
+			v, err := replace(n)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+			n++
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String(), nil
+}
+
+// splitMySQLQuery splits the statements of a COM_QUERY on semicolons.
+// Semicolons inside quotes and comments do not terminate a statement.
+// Empty statements are dropped.
+func splitMySQLQuery(query string) []string {
+	stmts := []string{}
+	start := 0
+	for i := 0; i <= len(query); i++ {
+		if i < len(query) {
+			switch c := query[i]; {
+			case c == '\'' || c == '"' || c == '`':
+				i = mysqlQuoteEnd(query, i) - 1
+				continue
+			case c == '#' || (c == '-' && strings.HasPrefix(query[i:], "-- ")):
+				if end := strings.IndexByte(query[i:], '\n'); end >= 0 {
+					i += end
+				} else {
+					i = len(query) - 1
+				}
+				continue
+			case c == '/' && strings.HasPrefix(query[i:], "/*"):
+				if end := strings.Index(query[i+2:], "*/"); end >= 0 {
+					i += end + 3
+				} else {
+					i = len(query) - 1
+				}
+				continue
+			case c != ';':
+				continue
+			}
+		}
+		if stmt := strings.TrimSpace(query[start:i]); stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+		start = i + 1
+	}
+	return stmts
+}
+
+// mysqlQuoteEnd returns the index following the quote closing the one at index i.
+// Quotes are escaped by a backslash or by doubling them.
+func mysqlQuoteEnd(query string, i int) int {
+	q := query[i]
+	for j := i + 1; j < len(query); j++ {
+		switch query[j] {
+		case '\\':
+			if q != '`' {
+				j++
+			}
+		case q:
+			if j+1 < len(query) && query[j+1] == q {
+				j++
+				continue
+			}
+			return j + 1
+		}
+	}
+	return len(query)
+}
+
+// myReader reads the fields of a packet payload. The first read
+// error is recorded, following reads return zero values.
+type myReader struct {
+	// buf is the unread part of the payload.
+	buf []byte
+	// err is the first read error.
+	err error
+}
+
+// next returns the next n bytes of the payload.
+func (r *myReader) next(n int) []byte {
+	if r.err != nil {
+		return make([]byte, n)
+	}
+	if n > len(r.buf) {
+		r.err = errors.New("protocol error: packet is too short")
+		return make([]byte, n)
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+// byte reads a byte.
+func (r *myReader) byte() byte {
+	return r.next(1)[0]
+}
+
+// lengthEncodedInt reads a length-encoded integer.
+func (r *myReader) lengthEncodedInt() uint64 {
+	switch first := r.byte(); first {
+	case 0xfc:
+		return uint64(binary.LittleEndian.Uint16(r.next(2)))
+	case 0xfd:
+		b := r.next(3)
+		return uint64(b[0]) | uint64(b[1])<<8 | uint64(b[2])<<16
+	case 0xfe:
+		return binary.LittleEndian.Uint64(r.next(8))
+	default:
+		return uint64(first)
+	}
+}
+
+// value reads a parameter value in the binary format of its type and returns its SQL representation.
+func (r *myReader) value(typ byte, unsigned bool) (string, error) {
+	integer := func(size int) string {
+		b := append(r.next(size), make([]byte, 8-size)...)
+		v := binary.LittleEndian.Uint64(b)
+		if unsigned {
+			return strconv.FormatUint(v, 10)
+		}
+		shift := 64 - 8*size
+		return strconv.FormatInt(int64(v<<shift)>>shift, 10) //nolint:gosec // sign extension
+	}
+
+	var v string
+	switch typ {
+	case myTypeNull:
+		return "NULL", nil
+	case myTypeTiny:
+		v = integer(1)
+	case myTypeShort, myTypeYear:
+		v = integer(2)
+	case myTypeLong, myTypeInt24:
+		v = integer(4)
+	case myTypeLongLong:
+		v = integer(8)
+	case myTypeFloat:
+		v = strconv.FormatFloat(float64(math.Float32frombits(binary.LittleEndian.Uint32(r.next(4)))), 'g', -1, 32)
+	case myTypeDouble:
+		v = strconv.FormatFloat(math.Float64frombits(binary.LittleEndian.Uint64(r.next(8))), 'g', -1, 64)
+	case myTypeDate, myTypeDateTime, myTypeTimestamp:
+		b := r.next(int(r.byte()))
+		t := make([]byte, 11)
+		copy(t, b)
+		s := fmt.Sprintf("%04d-%02d-%02d", binary.LittleEndian.Uint16(t), t[2], t[3])
+		if typ != myTypeDate {
+			s += fmt.Sprintf(" %02d:%02d:%02d", t[4], t[5], t[6])
+			if us := binary.LittleEndian.Uint32(t[7:]); us != 0 {
+				s += fmt.Sprintf(".%06d", us)
+			}
+		}
+		v = quoteMySQLString(s)
+	case myTypeTime:
+		b := r.next(int(r.byte()))
+		t := make([]byte, 12)
+		copy(t, b)
+		sign := ""
+		if t[0] == 1 {
+			sign = "-"
+		}
+		hours := binary.LittleEndian.Uint32(t[1:])*24 + uint32(t[5])
+		s := fmt.Sprintf("%s%02d:%02d:%02d", sign, hours, t[6], t[7])
+		if us := binary.LittleEndian.Uint32(t[8:]); us != 0 {
+			s += fmt.Sprintf(".%06d", us)
+		}
+		v = quoteMySQLString(s)
+	default:
+		// Strings, blobs, decimals and JSON are length-encoded strings
+		n := r.lengthEncodedInt()
+		if n > uint64(len(r.buf)) {
+			return "", errors.New("protocol error: packet is too short")
+		}
+		v = quoteMySQLString(string(r.next(int(n))))
+	}
+	if r.err != nil {
+		return "", r.err
+	}
+	return v, nil
+}
+
+// appendLengthEncodedInt appends a length-encoded integer.
+func appendLengthEncodedInt(b []byte, v uint64) []byte {
+	switch {
+	case v < 0xfb:
+		return append(b, byte(v))
+	case v <= 0xffff:
+		return binary.LittleEndian.AppendUint16(append(b, 0xfc), uint16(v))
+	case v <= 0xffffff:
+		return append(b, 0xfd, byte(v), byte(v>>8), byte(v>>16))
+	default:
+		return binary.LittleEndian.AppendUint64(append(b, 0xfe), v)
+	}
+}
+
+// appendLengthEncodedString appends a string preceded by its length-encoded length.
+func appendLengthEncodedString(b []byte, s string) []byte {
+	return append(appendLengthEncodedInt(b, uint64(len(s))), s...)
+}
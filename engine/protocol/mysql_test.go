@@ -0,0 +1,317 @@
+package protocol
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// myClient is a minimal MySQL client recording the packets of the server
+// as readable strings, e.g. "Row [1 foo]".
+type myClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// newMyClient connects to the endpoint and runs the handshake.
+func newMyClient(t *testing.T, endpoint *NetworkEngineEndpoint) *myClient {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", endpoint.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &myClient{conn: conn, r: bufio.NewReader(conn)}
+
+	handshake := c.read(t)
+	if handshake[0] != 10 || !strings.HasPrefix(string(handshake[1:]), myServerVersion) {
+		t.Fatalf("unexpected handshake: %q", handshake)
+	}
+
+	b := binary.LittleEndian.AppendUint32(nil, myClientProtocol41|myClientSecureConnection|myClientPluginAuth|myClientMultiStatements)
+	b = binary.LittleEndian.AppendUint32(b, 1<<24)
+	b = append(b, myCharset)
+	b = append(b, make([]byte, 23)...)
+	b = appendCString(b, "root")
+	b = append(b, 0) // empty auth response
+	b = appendCString(b, myAuthPlugin)
+	c.write(t, 1, b)
+	if ok := c.read(t); ok[0] != 0 {
+		t.Fatalf("unexpected handshake answer: %q", ok)
+	}
+	return c
+}
+
+// write writes a packet.
+func (c *myClient) write(t *testing.T, seq byte, payload []byte) {
+	t.Helper()
+	n := len(payload)
+	if _, err := c.conn.Write(append([]byte{byte(n), byte(n >> 8), byte(n >> 16), seq}, payload...)); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// read reads a packet.
+func (c *myClient) read(t *testing.T) []byte {
+	t.Helper()
+	var head [4]byte
+	if _, err := io.ReadFull(c.r, head[:]); err != nil {
+		t.Fatal(err)
+	}
+	b := make([]byte, int(head[0])|int(head[1])<<8|int(head[2])<<16)
+	if _, err := io.ReadFull(c.r, b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// readResult reads the results of a command until the last one.
+// Rows of binary resultsets are read if binary is true.
+func (c *myClient) readResult(t *testing.T, binaryRows bool) []string {
+	t.Helper()
+
+	got := []string{}
+	for {
+		b := c.read(t)
+		msg := &myReader{buf: b}
+		switch b[0] {
+		case 0x00:
+			msg.byte()
+			affected, id := msg.lengthEncodedInt(), msg.lengthEncodedInt()
+			status := binary.LittleEndian.Uint16(msg.next(2))
+			got = append(got, fmt.Sprintf("OK affected=%d id=%d", affected, id))
+			if status&myStatusMoreResultsExists == 0 {
+				return got
+			}
+		case 0xff:
+			msg.byte()
+			code := binary.LittleEndian.Uint16(msg.next(2))
+			got = append(got, fmt.Sprintf("ERR %d %s", code, msg.buf[6:]))
+			return got
+		default:
+			columns := int(msg.lengthEncodedInt())
+			names := []string{}
+			for i := 0; i < columns; i++ {
+				def := &myReader{buf: c.read(t)}
+				for j := 0; j < 4; j++ {
+					def.next(int(def.lengthEncodedInt()))
+				}
+				names = append(names, string(def.next(int(def.lengthEncodedInt()))))
+			}
+			c.read(t) // EOF
+			got = append(got, fmt.Sprintf("Columns %v", names))
+
+			for {
+				row := c.read(t)
+				if row[0] == 0xfe && len(row) < 9 {
+					if binary.LittleEndian.Uint16(row[3:])&myStatusMoreResultsExists == 0 {
+						return got
+					}
+					break
+				}
+				got = append(got, fmt.Sprintf("Row %v", readRow(row, columns, binaryRows)))
+			}
+		}
+	}
+}
+
+// readRow returns the values of a text or binary row.
+func readRow(row []byte, columns int, binaryRow bool) []string {
+	values := []string{}
+	if !binaryRow {
+		msg := &myReader{buf: row}
+		for i := 0; i < columns; i++ {
+			if msg.buf[0] == 0xfb {
+				msg.byte()
+				values = append(values, "NULL")
+				continue
+			}
+			values = append(values, string(msg.next(int(msg.lengthEncodedInt()))))
+		}
+		return values
+	}
+
+	nulls := row[1 : 1+(columns+9)/8]
+	msg := &myReader{buf: row[1+len(nulls):]}
+	for i := 0; i < columns; i++ {
+		if nulls[(i+2)/8]&(1<<((i+2)%8)) != 0 {
+			values = append(values, "NULL")
+			continue
+		}
+		values = append(values, string(msg.next(int(msg.lengthEncodedInt()))))
+	}
+	return values
+}
+
+// serveMySQL starts an engine echoing the statements on a MySQL endpoint.
+func serveMySQL(t *testing.T) *NetworkEngineEndpoint {
+	t.Helper()
+
+	endpoint, err := NewMySQLEngineEndpoint("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(endpoint.Close)
+
+	go func() {
+		for {
+			conn, err := endpoint.Accept()
+			if err != nil {
+				return
+			}
+			if mode := conn.(SyntaxModeConn).SyntaxMode(); mode != core.SQLSyntaxModeMySQL {
+				t.Errorf("mismatch syntax mode: want=%d, got=%d", core.SQLSyntaxModeMySQL, mode)
+			}
+			go func() {
+				for {
+					stmt, err := conn.ReadStatement()
+					if err != nil {
+						return
+					}
+					switch {
+					case strings.HasPrefix(stmt, "SELECT"):
+						_ = conn.WriteRowHeader([]string{"id", "name"})
+						_ = conn.WriteRow([]*string{text("1"), text(stmt)})
+						_ = conn.WriteRow([]*string{text("2"), nil})
+						_ = conn.WriteRowEnd()
+					case strings.HasPrefix(stmt, "fail"):
+						_ = conn.WriteError(errors.New("duplicate key value violates unique constraint on column \"id\""))
+					default:
+						_ = conn.WriteResult(7, 1)
+					}
+				}
+			}()
+		}
+	}()
+	return endpoint
+}
+
+func TestMySQLEngineConn(t *testing.T) {
+	t.Parallel()
+
+	t.Run("COM_QUERY with several statements", func(t *testing.T) {
+		t.Parallel()
+
+		c := newMyClient(t, serveMySQL(t))
+		defer c.conn.Close()
+
+		c.write(t, 0, append([]byte{myComQuery}, "INSERT INTO t VALUES (1); SELECT 'a;b'; BEGIN"...))
+		want := []string{
+			"OK affected=1 id=7",
+			"Columns [id name]",
+			"Row [1 SELECT 'a;b']",
+			"Row [2 NULL]",
+			"OK affected=0 id=0",
+		}
+		if diff := cmp.Diff(want, c.readResult(t, false)); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+
+		c.write(t, 0, append([]byte{myComQuery}, "fail; INSERT INTO t VALUES (1)"...))
+		want = []string{`ERR 1062 duplicate key value violates unique constraint on column "id"`}
+		if diff := cmp.Diff(want, c.readResult(t, false)); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+
+		c.write(t, 0, []byte{myComPing})
+		if diff := cmp.Diff([]string{"OK affected=0 id=0"}, c.readResult(t, false)); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+
+		c.write(t, 0, []byte{myComQuit})
+		if _, err := c.r.ReadByte(); !errors.Is(err, io.EOF) {
+			t.Errorf("mismatch error: want=%v, got=%v", io.EOF, err)
+		}
+	})
+
+	t.Run("COM_STMT_PREPARE and COM_STMT_EXECUTE", func(t *testing.T) {
+		t.Parallel()
+
+		c := newMyClient(t, serveMySQL(t))
+		defer c.conn.Close()
+
+		c.write(t, 0, append([]byte{myComStmtPrepare}, "SELECT * FROM t WHERE name = ? AND id = ? AND note = '?' AND x = ?"...))
+		prepared := c.read(t)
+		if prepared[0] != 0 {
+			t.Fatalf("unexpected prepare answer: %q", prepared)
+		}
+		id := binary.LittleEndian.Uint32(prepared[1:])
+		if params := binary.LittleEndian.Uint16(prepared[7:]); params != 3 {
+			t.Fatalf("mismatch parameters: want=3, got=%d", params)
+		}
+		for i := 0; i < 4; i++ { // 3 parameter definitions and EOF
+			c.read(t)
+		}
+
+		b := []byte{myComStmtExecute}
+		b = binary.LittleEndian.AppendUint32(b, id)
+		b = append(b, 0, 1, 0, 0, 0) // flags, iteration count
+		b = append(b, 0b100)         // NULL bitmap: third parameter is NULL
+		b = append(b, 1)             // new parameters bound
+		b = append(b, myTypeVarString, 0, myTypeLongLong, 0, myTypeNull, 0)
+		b = appendLengthEncodedString(b, "it's")
+		b = binary.LittleEndian.AppendUint64(b, uint64(0xffffffffffffffff)) // -1
+		c.write(t, 0, b)
+
+		want := []string{
+			"Columns [id name]",
+			`Row [1 SELECT * FROM t WHERE name = 'it\'s' AND id = -1 AND note = '?' AND x = NULL]`,
+			"Row [2 NULL]",
+		}
+		if diff := cmp.Diff(want, c.readResult(t, true)); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+
+		b = binary.LittleEndian.AppendUint32([]byte{myComStmtExecute}, id+1)
+		c.write(t, 0, append(b, 0, 1, 0, 0, 0))
+		got := c.readResult(t, true)
+		if len(got) != 1 || !strings.HasPrefix(got[0], "ERR 1105 unknown prepared statement") {
+			t.Errorf("unexpected answer: %v", got)
+		}
+	})
+}
+
+func TestSplitMySQLQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "Single statement without semicolon",
+			query: "SELECT * FROM t",
+			want:  []string{"SELECT * FROM t"},
+		},
+		{
+			name:  "Semicolons in quotes and comments",
+			query: "INSERT INTO t VALUES ('a;\\'b', \"c;d\"); # e;f\nSELECT `g;h` FROM t /* i;j */;",
+			want:  []string{"INSERT INTO t VALUES ('a;\\'b', \"c;d\")", "# e;f\nSELECT `g;h` FROM t /* i;j */"},
+		},
+		{
+			name:  "Empty statements",
+			query: " ;; ",
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.want, splitMySQLQuery(tt.query)); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
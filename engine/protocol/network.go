@@ -0,0 +1,539 @@
+package protocol
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// The network endpoints exchange the same messages as the channel endpoints,
+// written on a TCP or Unix socket as frames:
+//
+//	type   1 byte, the messageType (0 error, 1 query, 2 exec, 3 result,
+//	       4 row header, 5 row value, 6 row end, 7 dialect)
+//	count  4 bytes, big-endian number of values
+//	values for each value, 4 bytes big-endian length followed by the UTF-8 bytes,
+//	       or the length 0xFFFFFFFF (-1) without bytes for a NULL value of a row
+//
+// The driver sends a query or exec frame carrying the statement. The engine
+// answers with an error frame, a result frame (last inserted ID and rows
+// affected, as decimal strings), or a row header frame followed by row value
+// frames and a row end frame. A frame holding several statements gets a single
+// answer: the rows of the last statement or the sum of the rows affected, or the
+// error of the first failing statement. A driver whose DSN has a dialect option (e.g.
+// "tcp://127.0.0.1:5433?dialect=mysql") sends a dialect frame carrying the name
+// of the SQL syntax mode before its first statement, the engine does not answer
+// it. Any client able to write these frames (e.g. a
+// fixture loader written in Python) can talk to an engine started by aion serve.
+
+const (
+	// maxFrameSize is the maximum size of the values of a frame.
+	// It protects the engine against corrupted frames.
+	maxFrameSize = 64 << 20
+	// dialTimeout is the timeout to connect to a network engine.
+	dialTimeout = 10 * time.Second
+	// nullLength is the length of a NULL value in a frame.
+	nullLength = 0xFFFFFFFF
+)
+
+// Network schemes of the addresses understood by ParseAddress.
+const (
+	// schemeTCP is the scheme of TCP addresses (e.g. tcp://127.0.0.1:5433).
+	schemeTCP = "tcp"
+	// schemeUnix is the scheme of Unix socket addresses (e.g. unix:///tmp/aion.sock).
+	schemeUnix = "unix"
+)
+
+// ParseAddress returns the network ("tcp" or "unix") and the address of a
+// network DSN or listen address, e.g. "tcp://127.0.0.1:5433" or
+// "unix:///tmp/aion.sock". An address without scheme is a TCP address.
+// Options after a question mark (e.g. "?dialect=mysql") are ignored.
+func ParseAddress(addr string) (network string, address string, err error) {
+	network, address, ok := strings.Cut(addr, "://")
+	if !ok {
+		network, address = schemeTCP, addr
+	}
+	if i := strings.IndexByte(address, '?'); i >= 0 {
+		address = address[:i]
+	}
+
+	switch network {
+	case schemeTCP, schemeUnix:
+	default:
+		return "", "", fmt.Errorf("unsupported network %q, allowed networks are %s and %s", network, schemeTCP, schemeUnix)
+	}
+	if address == "" {
+		return "", "", fmt.Errorf("missing %s address in %q", network, addr)
+	}
+	return network, address, nil
+}
+
+// IsNetworkDSN returns true if the DSN names an engine reached through
+// a network endpoint (e.g. "tcp://127.0.0.1:5433").
+func IsNetworkDSN(dsn string) bool {
+	return strings.HasPrefix(dsn, schemeTCP+"://") || strings.HasPrefix(dsn, schemeUnix+"://")
+}
+
+// writeFrame writes a message as a frame.
+func writeFrame(w io.Writer, m message) error {
+	buf := make([]byte, 5, 5+4*len(m.Value))
+	buf[0] = byte(m.Type)
+	binary.BigEndian.PutUint32(buf[1:], uint32(len(m.Value))) //nolint:gosec // the number of values is small
+	for i, v := range m.Value {
+		if i < len(m.Null) && m.Null[i] {
+			buf = binary.BigEndian.AppendUint32(buf, nullLength)
+			continue
+		}
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(v))) //nolint:gosec // values are smaller than maxFrameSize
+		buf = append(buf, v...)
+	}
+	_, err := w.Write(buf)
+	return err
+}
+
+// readFrame reads a frame. It returns io.EOF if the connection is closed between two frames.
+func readFrame(r io.Reader) (message, error) {
+	var head [5]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return message{}, err
+	}
+
+	m := message{Type: messageType(head[0])}
+	if m.Type < errMessage || m.Type > dialectMessage {
+		return message{}, fmt.Errorf("protocol error: unknown message type %d", head[0])
+	}
+
+	count := binary.BigEndian.Uint32(head[1:])
+	size := 0
+	m.Value = []string{}
+	for i := uint32(0); i < count; i++ {
+		var length [4]byte
+		if _, err := io.ReadFull(r, length[:]); err != nil {
+			return message{}, unexpectedEOF(err)
+		}
+		if binary.BigEndian.Uint32(length[:]) == nullLength {
+			if m.Null == nil {
+				m.Null = make([]bool, count)
+			}
+			m.Null[i] = true
+			m.Value = append(m.Value, "")
+			size += 4
+			continue
+		}
+		n := int(binary.BigEndian.Uint32(length[:]))
+		size += 4 + n
+		if size > maxFrameSize {
+			return message{}, fmt.Errorf("protocol error: frame larger than %d bytes", maxFrameSize)
+		}
+
+		v := make([]byte, n)
+		if _, err := io.ReadFull(r, v); err != nil {
+			return message{}, unexpectedEOF(err)
+		}
+		m.Value = append(m.Value, string(v))
+	}
+	return m, nil
+}
+
+// unexpectedEOF returns io.ErrUnexpectedEOF instead of io.EOF, the connection
+// is closed in the middle of a frame.
+func unexpectedEOF(err error) error {
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// NetworkDriverConn implements DriverConn for network backend.
+type NetworkDriverConn struct {
+	// conn is the socket connected to the engine.
+	conn net.Conn
+	// r buffers the frames read from the engine.
+	r *bufio.Reader
+	// once ensures the connection is closed only once.
+	once sync.Once
+	// closed is true once the connection is closed.
+	closed bool
+}
+
+// WriteQuery sends a statement that is expected to return rows.
+func (c *NetworkDriverConn) WriteQuery(query string) error {
+	return c.write(message{Type: queryMessage, Value: []string{query}})
+}
+
+// WriteExec sends a statement that is expected to return a result.
+func (c *NetworkDriverConn) WriteExec(stmt string) error {
+	return c.write(message{Type: execMessage, Value: []string{stmt}})
+}
+
+// write sends a message to the engine.
+func (c *NetworkDriverConn) write(m message) error {
+	if c.closed {
+		return ErrConnClosed
+	}
+	return writeFrame(c.conn, m)
+}
+
+// ReadResult reads the result of a statement sent with WriteExec.
+func (c *NetworkDriverConn) ReadResult() (lastInsertedID int64, rowsAffected int64, err error) {
+	m, err := c.read()
+	if err != nil {
+		return 0, 0, err
+	}
+
+	switch m.Type {
+	case resultMessage:
+	case rowHeaderMessage:
+		// The statement returned rows (e.g. INSERT ... RETURNING), discard them.
+		rows, err := c.readRows()
+		if err != nil {
+			return 0, 0, err
+		}
+		return 0, int64(len(rows)), nil
+	default:
+		return 0, 0, fmt.Errorf("protocol error: ReadResult received %v", m)
+	}
+	return parseResult(m)
+}
+
+// ReadRows reads the rows of a statement sent with WriteQuery.
+// The whole row set is read before returning, so that the connection is
+// ready for the next statement even if the caller stops reading rows.
+func (c *NetworkDriverConn) ReadRows() (chan []*string, error) {
+	m, err := c.read()
+	if err != nil {
+		return nil, err
+	}
+
+	switch m.Type {
+	case rowHeaderMessage:
+		rows, err := c.readRows()
+		if err != nil {
+			return nil, err
+		}
+		ch := make(chan []*string, len(rows)+1)
+		ch <- m.row()
+		for _, row := range rows {
+			ch <- row
+		}
+		close(ch)
+		return ch, nil
+	case resultMessage:
+		// The statement did not return rows (e.g. INSERT), return an empty set.
+		ch := make(chan []*string, 1)
+		ch <- []*string{}
+		close(ch)
+		return ch, nil
+	default:
+		return nil, fmt.Errorf("protocol error: ReadRows received %v", m)
+	}
+}
+
+// readRows reads the rows following a row header until the end of the row set.
+func (c *NetworkDriverConn) readRows() ([][]*string, error) {
+	rows := [][]*string{}
+	for {
+		m, err := c.read()
+		if err != nil {
+			return nil, err
+		}
+		switch m.Type {
+		case rowValueMessage:
+			rows = append(rows, m.row())
+		case rowEndMessage:
+			return rows, nil
+		default:
+			return nil, fmt.Errorf("protocol error: row set contains %v", m)
+		}
+	}
+}
+
+// read reads the next message from the engine. An error message is returned as error.
+func (c *NetworkDriverConn) read() (message, error) {
+	if c.closed {
+		return message{}, ErrConnClosed
+	}
+
+	m, err := readFrame(c.r)
+	if errors.Is(err, io.EOF) {
+		return message{}, ErrConnClosed
+	}
+	if err != nil {
+		return message{}, err
+	}
+	if m.Type == errMessage {
+		if len(m.Value) == 0 {
+			return message{}, errors.New("unknown error")
+		}
+		return message{}, errors.New(m.Value[0])
+	}
+	return m, nil
+}
+
+// Close closes the connection. The engine receives io.EOF.
+func (c *NetworkDriverConn) Close() {
+	c.once.Do(func() {
+		c.closed = true
+		c.conn.Close() //nolint
+	})
+}
+
+// NetworkDriverEndpoint implements DriverEndpoint for network backend.
+type NetworkDriverEndpoint struct{}
+
+// NewNetworkDriverEndpoint returns a new NetworkDriverEndpoint.
+func NewNetworkDriverEndpoint() *NetworkDriverEndpoint {
+	return &NetworkDriverEndpoint{}
+}
+
+// New connects to the engine listening on the address of the DSN
+// (e.g. "tcp://127.0.0.1:5433" or "unix:///tmp/aion.sock?dialect=sqlite").
+func (e *NetworkDriverEndpoint) New(dsn string) (DriverConn, error) {
+	network, address, err := ParseAddress(dsn)
+	if err != nil {
+		return nil, err
+	}
+	mode, err := SyntaxModeOfDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout(network, address, dialTimeout)
+	if err != nil {
+		return nil, err
+	}
+	if mode != core.SQLSyntaxModePostgreSQL {
+		if err := writeFrame(conn, message{Type: dialectMessage, Value: []string{mode.String()}}); err != nil {
+			conn.Close() //nolint
+			return nil, err
+		}
+	}
+	return &NetworkDriverConn{
+		conn: conn,
+		r:    bufio.NewReader(conn),
+	}, nil
+}
+
+// NetworkEngineConn implements EngineConn and SyntaxModeConn for network backend.
+type NetworkEngineConn struct {
+	// conn is the socket connected to the driver.
+	conn net.Conn
+	// r buffers the frames read from the driver.
+	r *bufio.Reader
+	// w buffers the frames written to the driver, it is flushed
+	// at the end of each answer.
+	w *bufio.Writer
+	// release is called once when the connection is closed.
+	release func()
+	// once ensures the connection is closed only once.
+	once sync.Once
+	// mode is the SQL syntax mode of the statements, set by a dialect frame.
+	mode core.SQLSyntaxMode
+}
+
+// SyntaxMode returns the SQL syntax mode sent by the driver, PostgreSQL by default.
+func (c *NetworkEngineConn) SyntaxMode() core.SQLSyntaxMode {
+	return c.mode
+}
+
+// ReadStatement reads the next statement.
+// It returns io.EOF and closes the connection once the driver is gone.
+func (c *NetworkEngineConn) ReadStatement() (string, error) {
+	m, err := readFrame(c.r)
+	if err != nil {
+		c.close()
+		if errors.Is(err, io.EOF) || errors.Is(err, net.ErrClosed) {
+			return "", io.EOF
+		}
+		return "", err
+	}
+
+	if m.Type == dialectMessage && len(m.Value) == 1 {
+		mode, err := core.ParseSQLSyntaxMode(m.Value[0])
+		if err != nil {
+			c.close()
+			return "", fmt.Errorf("protocol error: %w", err)
+		}
+		c.mode = mode
+		return c.ReadStatement()
+	}
+
+	if (m.Type != queryMessage && m.Type != execMessage) || len(m.Value) != 1 {
+		c.close()
+		return "", fmt.Errorf("protocol error: ReadStatement received %v", m)
+	}
+	return m.Value[0], nil
+}
+
+// WriteResult writes the result of a statement.
+func (c *NetworkEngineConn) WriteResult(lastInsertedID int64, rowsAffected int64) error {
+	return c.write(message{
+		Type: resultMessage,
+		Value: []string{
+			strconv.FormatInt(lastInsertedID, 10),
+			strconv.FormatInt(rowsAffected, 10),
+		},
+	}, true)
+}
+
+// WriteError writes an error.
+func (c *NetworkEngineConn) WriteError(err error) error {
+	return c.write(message{Type: errMessage, Value: []string{err.Error()}}, true)
+}
+
+// WriteRowHeader writes the header of a row set.
+func (c *NetworkEngineConn) WriteRowHeader(header []string) error {
+	return c.write(message{Type: rowHeaderMessage, Value: header}, false)
+}
+
+// WriteRow writes a row of a row set.
+func (c *NetworkEngineConn) WriteRow(row []*string) error {
+	return c.write(rowMessage(row), false)
+}
+
+// WriteRowEnd ends a row set.
+func (c *NetworkEngineConn) WriteRowEnd() error {
+	return c.write(message{Type: rowEndMessage}, true)
+}
+
+// write writes a message to the driver. The buffered frames are sent if flush is true.
+func (c *NetworkEngineConn) write(m message, flush bool) error {
+	if err := writeFrame(c.w, m); err != nil {
+		return err
+	}
+	if !flush {
+		return nil
+	}
+	return c.w.Flush()
+}
+
+// close closes the socket and releases the connection.
+func (c *NetworkEngineConn) close() {
+	c.once.Do(func() {
+		c.conn.Close() //nolint
+		c.release()
+	})
+}
+
+// networkConn is an EngineConn on a socket, closed with its endpoint.
+type networkConn interface {
+	EngineConn
+	// close closes the socket and releases the connection.
+	close()
+}
+
+// newNetworkConn returns the EngineConn speaking a wire protocol on an accepted socket.
+// id is unique among the connections of the endpoint, release must be called once
+// the connection is closed.
+type newNetworkConn func(conn net.Conn, id uint32, release func()) networkConn
+
+// NetworkEngineEndpoint implements EngineEndpoint for network backend.
+// Connections are accepted on a TCP or Unix socket.
+type NetworkEngineEndpoint struct {
+	// listener accepts the connections of drivers.
+	listener net.Listener
+	// newConn returns the connection speaking the wire protocol of the endpoint.
+	newConn newNetworkConn
+	// conns is the set of open connections, closed with the endpoint.
+	conns map[net.Conn]networkConn
+	// lastID is the ID of the last accepted connection.
+	lastID uint32
+	// closed is true once the endpoint is closed.
+	closed bool
+	// mu protects conns, lastID and closed.
+	mu sync.Mutex
+}
+
+// NewNetworkEngineEndpoint returns a new NetworkEngineEndpoint listening on the
+// network ("tcp" or "unix") and the address, see ParseAddress.
+// Connections exchange AION DB frames, see NetworkDriverEndpoint.
+func NewNetworkEngineEndpoint(network string, address string) (*NetworkEngineEndpoint, error) {
+	return listen(network, address, func(conn net.Conn, _ uint32, release func()) networkConn {
+		return &NetworkEngineConn{
+			conn:    conn,
+			r:       bufio.NewReader(conn),
+			w:       bufio.NewWriter(conn),
+			release: release,
+			mode:    core.SQLSyntaxModePostgreSQL,
+		}
+	})
+}
+
+// listen returns a new NetworkEngineEndpoint listening on the network and the address.
+// Accepted sockets are wrapped with newConn.
+func listen(network string, address string, newConn newNetworkConn) (*NetworkEngineEndpoint, error) {
+	listener, err := net.Listen(network, address)
+	if err != nil {
+		return nil, err
+	}
+	return &NetworkEngineEndpoint{
+		listener: listener,
+		newConn:  newConn,
+		conns:    make(map[net.Conn]networkConn),
+	}, nil
+}
+
+// Addr returns the address the endpoint listens on.
+// It is useful to know the port chosen by the system for "127.0.0.1:0".
+func (e *NetworkEngineEndpoint) Addr() net.Addr {
+	return e.listener.Addr()
+}
+
+// Accept waits for a new connection. It returns io.EOF once the endpoint is closed.
+func (e *NetworkEngineEndpoint) Accept() (EngineConn, error) {
+	conn, err := e.listener.Accept()
+	if err != nil {
+		e.mu.Lock()
+		closed := e.closed
+		e.mu.Unlock()
+		if closed || errors.Is(err, net.ErrClosed) {
+			return nil, io.EOF
+		}
+		return nil, err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.closed {
+		conn.Close() //nolint
+		return nil, io.EOF
+	}
+	e.lastID++
+	c := e.newConn(conn, e.lastID, func() {
+		e.mu.Lock()
+		delete(e.conns, conn)
+		e.mu.Unlock()
+	})
+	e.conns[conn] = c
+	return c, nil
+}
+
+// Close stops listening and closes the open connections.
+// Pending and future Accept calls return io.EOF.
+func (e *NetworkEngineEndpoint) Close() {
+	e.mu.Lock()
+	if e.closed {
+		e.mu.Unlock()
+		return
+	}
+	e.closed = true
+	conns := make([]networkConn, 0, len(e.conns))
+	for _, c := range e.conns {
+		conns = append(conns, c)
+	}
+	e.mu.Unlock()
+
+	e.listener.Close() //nolint
+	for _, c := range conns {
+		c.close()
+	}
+}
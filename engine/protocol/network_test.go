@@ -0,0 +1,210 @@
+package protocol
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNetworkEndpoints(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		network string
+		address string
+	}{
+		{
+			name:    "TCP",
+			network: "tcp",
+			address: "127.0.0.1:0",
+		},
+		{
+			name:    "Unix socket",
+			network: "unix",
+			address: filepath.Join(t.TempDir(), "aion.sock"),
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			engineEndpoint, err := NewNetworkEngineEndpoint(tt.network, tt.address)
+			if err != nil {
+				t.Fatal(err)
+			}
+			done := make(chan struct{})
+			go func() {
+				defer close(done)
+				conn, err := engineEndpoint.Accept()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				serve(t, conn)
+			}()
+
+			driverEndpoint := NewNetworkDriverEndpoint()
+			conn, err := driverEndpoint.New(tt.network + "://" + engineEndpoint.Addr().String())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := conn.WriteExec("insert"); err != nil {
+				t.Fatal(err)
+			}
+			id, affected, err := conn.ReadResult()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if id != 4 || affected != 2 {
+				t.Errorf("mismatch result: want=(4, 2), got=(%d, %d)", id, affected)
+			}
+
+			if err := conn.WriteQuery("rows"); err != nil {
+				t.Fatal(err)
+			}
+			rows, err := conn.ReadRows()
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := [][]*string{}
+			for r := range rows {
+				got = append(got, r)
+			}
+			want := [][]*string{{text("id"), text("name")}, {text("1"), text("<nil>")}, {text("2"), nil}}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+
+			if err := conn.WriteExec("rows"); err != nil {
+				t.Fatal(err)
+			}
+			if _, affected, err := conn.ReadResult(); err != nil || affected != 2 {
+				t.Errorf("mismatch discarded rows: want=(2, <nil>), got=(%d, %v)", affected, err)
+			}
+
+			if err := conn.WriteQuery("fail"); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := conn.ReadRows(); err == nil || err.Error() != "failed" {
+				t.Errorf("mismatch error: want=failed, got=%v", err)
+			}
+
+			conn.Close()
+			<-done
+			if err := conn.WriteExec("insert"); !errors.Is(err, ErrConnClosed) {
+				t.Errorf("mismatch error: want=%v, got=%v", ErrConnClosed, err)
+			}
+
+			engineEndpoint.Close()
+			if _, err := engineEndpoint.Accept(); !errors.Is(err, io.EOF) {
+				t.Errorf("mismatch error: want=%v, got=%v", io.EOF, err)
+			}
+		})
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		addr        string
+		wantNetwork string
+		wantAddress string
+		wantErr     bool
+	}{
+		{
+			name:        "TCP address",
+			addr:        "tcp://127.0.0.1:5433",
+			wantNetwork: "tcp",
+			wantAddress: "127.0.0.1:5433",
+		},
+		{
+			name:        "Address without scheme",
+			addr:        "localhost:5433",
+			wantNetwork: "tcp",
+			wantAddress: "localhost:5433",
+		},
+		{
+			name:        "Unix socket with options",
+			addr:        "unix:///tmp/aion.sock?dialect=mysql",
+			wantNetwork: "unix",
+			wantAddress: "/tmp/aion.sock",
+		},
+		{
+			name:    "Unsupported network",
+			addr:    "udp://127.0.0.1:5433",
+			wantErr: true,
+		},
+		{
+			name:    "Missing address",
+			addr:    "unix://",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			network, address, err := ParseAddress(tt.addr)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("mismatch error: wantErr=%v, got=%v", tt.wantErr, err)
+			}
+			if network != tt.wantNetwork || address != tt.wantAddress {
+				t.Errorf("mismatch address: want=(%s, %s), got=(%s, %s)", tt.wantNetwork, tt.wantAddress, network, address)
+			}
+		})
+	}
+}
+
+func TestFrame(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Round trip", func(t *testing.T) {
+		t.Parallel()
+
+		want := message{Type: rowValueMessage, Value: []string{"1", "", "héllo", ""}, Null: []bool{false, false, false, true}}
+		buf := &bytes.Buffer{}
+		if err := writeFrame(buf, want); err != nil {
+			t.Fatal(err)
+		}
+		got, err := readFrame(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("value is mismatch (-want +got):\n%s", diff)
+		}
+	})
+
+	t.Run("Truncated frame", func(t *testing.T) {
+		t.Parallel()
+
+		buf := &bytes.Buffer{}
+		if err := writeFrame(buf, message{Type: queryMessage, Value: []string{"SELECT 1"}}); err != nil {
+			t.Fatal(err)
+		}
+		buf.Truncate(buf.Len() - 1)
+		if _, err := readFrame(buf); !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Errorf("mismatch error: want=%v, got=%v", io.ErrUnexpectedEOF, err)
+		}
+	})
+
+	t.Run("Unknown message type", func(t *testing.T) {
+		t.Parallel()
+
+		if _, err := readFrame(bytes.NewReader([]byte{42, 0, 0, 0, 0})); err == nil {
+			t.Error("expect error, however no error")
+		}
+	})
+}
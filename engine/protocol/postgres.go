@@ -0,0 +1,971 @@
+package protocol
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nao1215/aiondb/engine/parser/postgres"
+)
+
+// The PostgreSQL endpoint speaks the frontend/backend protocol version 3, so that
+// PostgreSQL clients (psql, lib/pq, pgx, ...) can connect to an AION DB engine.
+// See https://www.postgresql.org/docs/current/protocol.html.
+//
+// Supported features are the startup without authentication (SSL and GSSAPI
+// encryption are declined), the simple query protocol, and the extended query
+// protocol with Parse, Bind, Describe, Execute, Close, Flush and Sync.
+// Every column is described as text (OID 25) and values are sent as text,
+// which is also their binary representation.
+
+const (
+	// pgProtocolVersion is the version 3.0 of the protocol sent in the startup message.
+	pgProtocolVersion = 196608
+	// pgSSLRequest is the code of the startup message asking for SSL.
+	pgSSLRequest = 80877103
+	// pgGSSENCRequest is the code of the startup message asking for GSSAPI encryption.
+	pgGSSENCRequest = 80877104
+	// pgCancelRequest is the code of the startup message cancelling a running query.
+	pgCancelRequest = 80877102
+	// pgServerVersion is the server_version reported to clients.
+	pgServerVersion = "14.0"
+)
+
+// Type OIDs of PostgreSQL, used to describe parameters and columns.
+const (
+	pgTypeBool        = 16
+	pgTypeBytea       = 17
+	pgTypeName        = 19
+	pgTypeInt8        = 20
+	pgTypeInt2        = 21
+	pgTypeInt4        = 23
+	pgTypeText        = 25
+	pgTypeFloat4      = 700
+	pgTypeFloat8      = 701
+	pgTypeUnknown     = 705
+	pgTypeBpchar      = 1042
+	pgTypeVarchar     = 1043
+	pgTypeDate        = 1082
+	pgTypeTimestamp   = 1114
+	pgTypeTimestampTZ = 1184
+	pgTypeUUID        = 2950
+)
+
+// pgEpoch returns the origin of binary dates and timestamps.
+func pgEpoch() time.Time {
+	return time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+}
+
+// NewPostgresEngineEndpoint returns a new NetworkEngineEndpoint listening on the
+// network ("tcp" or "unix") and the address, whose connections speak the
+// PostgreSQL frontend/backend protocol.
+func NewPostgresEngineEndpoint(network string, address string) (*NetworkEngineEndpoint, error) {
+	return listen(network, address, func(conn net.Conn, id uint32, release func()) networkConn {
+		return &PostgresEngineConn{
+			conn:       conn,
+			r:          bufio.NewReader(conn),
+			w:          bufio.NewWriter(conn),
+			id:         id,
+			release:    release,
+			statements: make(map[string]*pgStatement),
+			portals:    make(map[string]*pgPortal),
+		}
+	})
+}
+
+// PostgresEngineConn implements EngineConn for PostgreSQL clients.
+//
+// The statements of the client are handed to the engine one at a time by
+// ReadStatement, and the result written by the engine is buffered. The next call
+// to ReadStatement, made once the engine is done with the statement, answers
+// the client: the answer depends on the message (Query, Describe or Execute)
+// the statement was run for.
+type PostgresEngineConn struct {
+	// conn is the socket connected to the client.
+	conn net.Conn
+	// r buffers the messages read from the client.
+	r *bufio.Reader
+	// w buffers the messages written to the client. Like PostgreSQL,
+	// it is flushed when the client waits for an answer (ReadyForQuery, Flush).
+	w *bufio.Writer
+	// id is the process ID reported to the client.
+	id uint32
+	// release is called once when the connection is closed.
+	release func()
+	// once ensures the connection is closed only once.
+	once sync.Once
+	// started is true once the startup is done.
+	started bool
+	// statements are the prepared statements, keyed by name ("" is the unnamed statement).
+	statements map[string]*pgStatement
+	// portals are the bound statements, keyed by name ("" is the unnamed portal).
+	portals map[string]*pgPortal
+	// queue is the list of statements of a simple query not run yet.
+	queue []string
+	// simple is true while a simple query is running. ReadyForQuery is sent
+	// once its last statement is done.
+	simple bool
+	// failed is true once a message of the extended query protocol failed.
+	// Messages are discarded until the next Sync.
+	failed bool
+	// running is the statement run by the engine.
+	running *pgRun
+}
+
+// pgStatement is a statement prepared with a Parse message.
+type pgStatement struct {
+	// query is the statement with its $n parameters.
+	query string
+	// paramTypes are the parameter type OIDs given by the client, 0 when unspecified.
+	paramTypes []uint32
+}
+
+// pgPortal is a statement bound to parameters with a Bind message.
+type pgPortal struct {
+	// query is the statement with its parameters replaced by their values.
+	query string
+	// resultFormats are the format codes of the result columns asked by the client.
+	resultFormats []int16
+}
+
+// pgRunMode is the message a statement run by the engine answers.
+type pgRunMode int
+
+const (
+	// pgRunQuery answers a statement of a simple Query message.
+	pgRunQuery pgRunMode = iota
+	// pgRunDescribeStatement describes the columns of a prepared statement, without running it.
+	pgRunDescribeStatement
+	// pgRunDescribePortal describes the columns of a portal, without running it.
+	pgRunDescribePortal
+	// pgRunExecute runs a portal and sends its rows.
+	pgRunExecute
+)
+
+// pgRun is a statement run by the engine.
+type pgRun struct {
+	// mode is the message the statement answers.
+	mode pgRunMode
+	// portal is the portal of the statement run for Describe or Execute.
+	portal *pgPortal
+	// result is the result written by the engine.
+	result *pgResult
+}
+
+// pgResult is the result of a statement written by the engine.
+type pgResult struct {
+	// command is the command of the statement (e.g. "INSERT").
+	command string
+	// header is the column names of the rows, nil if the statement returned no rows.
+	header []string
+	// rows are the rows returned by the statement, NULL values are nil.
+	rows [][]*string
+	// rowsAffected is the number of rows affected by the statement.
+	rowsAffected int64
+	// err is the error of the statement.
+	err error
+}
+
+// ReadStatement answers the statement previously run by the engine, then handles
+// the messages of the client until a statement must be run by the engine.
+// It returns io.EOF once the client terminates the connection.
+func (c *PostgresEngineConn) ReadStatement() (string, error) {
+	if !c.started {
+		if err := c.startup(); err != nil {
+			c.close()
+			return "", eof(err)
+		}
+		c.started = true
+	}
+
+	if c.running != nil {
+		run := c.running
+		c.running = nil
+		c.answer(run)
+	}
+
+	for {
+		if len(c.queue) > 0 {
+			stmt := c.queue[0]
+			c.queue = c.queue[1:]
+			if c.run(&pgRun{mode: pgRunQuery}, stmt) {
+				return stmt, nil
+			}
+			continue
+		}
+		if c.simple {
+			c.simple = false
+			c.readyForQuery()
+			if err := c.w.Flush(); err != nil {
+				c.close()
+				return "", eof(err)
+			}
+		}
+
+		typ, body, err := c.readMessage()
+		if err != nil {
+			c.close()
+			return "", eof(err)
+		}
+		stmt, ok, err := c.handle(typ, body)
+		if err != nil {
+			c.close()
+			return "", eof(err)
+		}
+		if ok {
+			return stmt, nil
+		}
+	}
+}
+
+// eof returns io.EOF for errors meaning the client is gone.
+func eof(err error) error {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) || errors.Is(err, net.ErrClosed) {
+		return io.EOF
+	}
+	return err
+}
+
+// startup handles the startup messages until the client is ready to send queries.
+func (c *PostgresEngineConn) startup() error {
+	for {
+		var head [4]byte
+		if _, err := io.ReadFull(c.r, head[:]); err != nil {
+			return err
+		}
+		length := int(binary.BigEndian.Uint32(head[:]))
+		if length < 8 || length > maxFrameSize {
+			return fmt.Errorf("protocol error: invalid startup message length %d", length)
+		}
+		body := make([]byte, length-4)
+		if _, err := io.ReadFull(c.r, body); err != nil {
+			return err
+		}
+
+		msg := &pgReader{buf: body}
+		code := msg.int32()
+		switch code {
+		case pgSSLRequest, pgGSSENCRequest:
+			// Encryption is not supported, the client goes on without it
+			if err := c.w.WriteByte('N'); err != nil {
+				return err
+			}
+			if err := c.w.Flush(); err != nil {
+				return err
+			}
+			continue
+		case pgCancelRequest:
+			// Statements are not cancellable, they are run one at a time
+			return io.EOF
+		case pgProtocolVersion:
+		default:
+			c.sendError(fmt.Errorf("unsupported frontend protocol %d.%d", code>>16, code&0xffff), "FATAL", "0A000")
+			c.w.Flush() //nolint
+			return io.EOF
+		}
+
+		params := map[string]string{}
+		for {
+			key := msg.cstring()
+			if key == "" || msg.err != nil {
+				break
+			}
+			params[key] = msg.cstring()
+		}
+
+		c.send('R', appendInt32(nil, 0)) // AuthenticationOk
+		for _, p := range [][2]string{
+			{"server_version", pgServerVersion},
+			{"server_encoding", "UTF8"},
+			{"client_encoding", "UTF8"},
+			{"DateStyle", "ISO, MDY"},
+			{"TimeZone", "UTC"},
+			{"integer_datetimes", "on"},
+			{"standard_conforming_strings", "on"},
+			{"application_name", params["application_name"]},
+		} {
+			c.send('S', appendCString(appendCString(nil, p[0]), p[1])) // ParameterStatus
+		}
+		c.send('K', appendInt32(appendInt32(nil, int32(c.id)), int32(c.id))) //nolint:gosec // BackendKeyData
+		c.readyForQuery()
+		return c.w.Flush()
+	}
+}
+
+// handle handles a message of the client. It returns the statement to run
+// and true if the message must be answered by the engine.
+func (c *PostgresEngineConn) handle(typ byte, body []byte) (string, bool, error) {
+	msg := &pgReader{buf: body}
+	if typ == 'X' { // Terminate
+		return "", false, io.EOF
+	}
+	if c.failed && typ != 'S' {
+		return "", false, nil
+	}
+
+	switch typ {
+	case 'Q': // Query
+		stmts := splitQuery(msg.cstring())
+		if len(stmts) == 0 {
+			c.send('I', nil) // EmptyQueryResponse
+			c.readyForQuery()
+			return "", false, c.w.Flush()
+		}
+		c.queue = stmts
+		c.simple = true
+		return "", false, nil
+	case 'P': // Parse
+		c.parse(msg)
+	case 'B': // Bind
+		c.bind(msg)
+	case 'D': // Describe
+		return c.describe(msg)
+	case 'E': // Execute
+		return c.execute(msg)
+	case 'C': // Close
+		kind, name := msg.byte(), msg.cstring()
+		if kind == 'S' {
+			delete(c.statements, name)
+		} else {
+			delete(c.portals, name)
+		}
+		c.send('3', nil) // CloseComplete
+	case 'S': // Sync
+		c.failed = false
+		delete(c.portals, "")
+		c.readyForQuery()
+		return "", false, c.w.Flush()
+	case 'H': // Flush
+		return "", false, c.w.Flush()
+	default:
+		c.fail(fmt.Errorf("unsupported message type %q", typ), "0A000")
+	}
+	return "", false, nil
+}
+
+// parse handles a Parse message, which prepares a statement.
+func (c *PostgresEngineConn) parse(msg *pgReader) {
+	name, query := msg.cstring(), msg.cstring()
+	n := int(msg.int16())
+	paramTypes := make([]uint32, 0, n)
+	for i := 0; i < n; i++ {
+		paramTypes = append(paramTypes, uint32(msg.int32())) //nolint:gosec // OIDs are unsigned
+	}
+	if msg.err != nil {
+		c.fail(msg.err, "08P01")
+		return
+	}
+
+	stmts := splitQuery(query)
+	if len(stmts) > 1 {
+		c.fail(errors.New("cannot insert multiple commands into a prepared statement"), "42601")
+		return
+	}
+	if len(stmts) == 1 {
+		query = stmts[0]
+	}
+	c.statements[name] = &pgStatement{query: query, paramTypes: paramTypes}
+	c.send('1', nil) // ParseComplete
+}
+
+// bind handles a Bind message, which binds a prepared statement to parameters.
+func (c *PostgresEngineConn) bind(msg *pgReader) {
+	portal, name := msg.cstring(), msg.cstring()
+	formats := msg.int16s()
+	n := int(msg.int16())
+	params := make([]*string, 0, n)
+	raw := make([][]byte, 0, n)
+	for i := 0; i < n; i++ {
+		raw = append(raw, msg.bytes())
+	}
+	resultFormats := msg.int16s()
+	if msg.err != nil {
+		c.fail(msg.err, "08P01")
+		return
+	}
+
+	stmt, ok := c.statements[name]
+	if !ok {
+		c.fail(fmt.Errorf("prepared statement \"%s\" does not exist", name), "26000")
+		return
+	}
+	for i, b := range raw {
+		if b == nil {
+			params = append(params, nil)
+			continue
+		}
+		format := int16(0)
+		switch {
+		case len(formats) == 1:
+			format = formats[0]
+		case i < len(formats):
+			format = formats[i]
+		}
+		oid := uint32(0)
+		if i < len(stmt.paramTypes) {
+			oid = stmt.paramTypes[i]
+		}
+		v, err := decodeParameter(b, format, oid)
+		if err != nil {
+			c.fail(fmt.Errorf("parameter $%d: %w", i+1, err), "22P03")
+			return
+		}
+		params = append(params, &v)
+	}
+
+	query, err := replaceParameters(stmt.query, func(i int) (string, error) {
+		if i < 1 || i > len(params) {
+			return "", fmt.Errorf("there is no parameter $%d", i)
+		}
+		return quoteParameter(params[i-1])
+	})
+	if err != nil {
+		c.fail(err, "42P02")
+		return
+	}
+	c.portals[portal] = &pgPortal{query: query, resultFormats: resultFormats}
+	c.send('2', nil) // BindComplete
+}
+
+// describe handles a Describe message. The engine describes the columns of the
+// statement or the portal without running it, see DescribeConn.
+func (c *PostgresEngineConn) describe(msg *pgReader) (string, bool, error) {
+	kind, name := msg.byte(), msg.cstring()
+	if kind == 'S' {
+		stmt, ok := c.statements[name]
+		if !ok {
+			c.fail(fmt.Errorf("prepared statement \"%s\" does not exist", name), "26000")
+			return "", false, nil
+		}
+
+		n := len(stmt.paramTypes)
+		replaceParameters(stmt.query, func(i int) (string, error) { //nolint
+			if i > n {
+				n = i
+			}
+			return "", nil
+		})
+		b := appendInt16(nil, int16(n)) //nolint:gosec // the number of parameters is small
+		for i := 0; i < n; i++ {
+			oid := uint32(pgTypeText)
+			if i < len(stmt.paramTypes) && stmt.paramTypes[i] != 0 {
+				oid = stmt.paramTypes[i]
+			}
+			b = appendInt32(b, int32(oid)) //nolint:gosec // OIDs are sent as int32
+		}
+		c.send('t', b) // ParameterDescription
+		return stmt.query, c.run(&pgRun{mode: pgRunDescribeStatement}, stmt.query), nil
+	}
+
+	portal, ok := c.portals[name]
+	if !ok {
+		c.fail(fmt.Errorf("portal \"%s\" does not exist", name), "34000")
+		return "", false, nil
+	}
+	return portal.query, c.run(&pgRun{mode: pgRunDescribePortal, portal: portal}, portal.query), nil
+}
+
+// execute handles an Execute message, which runs a portal.
+// The maximum number of rows is not supported, all the rows are sent.
+func (c *PostgresEngineConn) execute(msg *pgReader) (string, bool, error) {
+	name := msg.cstring()
+	portal, ok := c.portals[name]
+	if !ok {
+		c.fail(fmt.Errorf("portal \"%s\" does not exist", name), "34000")
+		return "", false, nil
+	}
+	return portal.query, c.run(&pgRun{mode: pgRunExecute, portal: portal}, portal.query), nil
+}
+
+// run prepares the connection to buffer the result of a statement.
+// Session statements are answered without the engine, see sessionCommand.
+// It returns true if the engine must run the statement.
+func (c *PostgresEngineConn) run(run *pgRun, stmt string) bool {
+	run.result = &pgResult{command: Command(stmt)}
+	c.running = run
+
+	command, ok, err := sessionCommand(stmt)
+	if !ok {
+		return true
+	}
+	run.result.command = command
+	// Session statements have no rows to describe, they only fail once run
+	if !run.describing() {
+		run.result.err = err
+	}
+
+	c.running = nil
+	c.answer(run)
+	return false
+}
+
+// describing returns true if the statement is only described, see DescribeConn.
+func (run *pgRun) describing() bool {
+	return run.mode == pgRunDescribeStatement || run.mode == pgRunDescribePortal
+}
+
+// answer answers the message a statement was run for, with its result.
+func (c *PostgresEngineConn) answer(run *pgRun) {
+	result := run.result
+	switch run.mode {
+	case pgRunQuery:
+		if result.err != nil {
+			// The following statements of the query are not run
+			c.sendError(result.err, "ERROR", sqlState(result.err))
+			c.queue = nil
+			return
+		}
+		c.sendResult(result, true)
+	case pgRunDescribeStatement, pgRunDescribePortal:
+		if result.err != nil {
+			c.fail(result.err, sqlState(result.err))
+			return
+		}
+		var formats []int16
+		if run.portal != nil {
+			formats = run.portal.resultFormats
+		}
+		c.sendDescription(result, formats)
+	case pgRunExecute:
+		if result.err != nil {
+			c.fail(result.err, sqlState(result.err))
+			return
+		}
+		c.sendResult(result, false)
+	}
+}
+
+// sendDescription sends the RowDescription of a result, or NoData if it has no rows.
+func (c *PostgresEngineConn) sendDescription(result *pgResult, formats []int16) {
+	if result.header == nil {
+		c.send('n', nil) // NoData
+		return
+	}
+
+	b := appendInt16(nil, int16(len(result.header))) //nolint:gosec // the number of columns is small
+	for i, name := range result.header {
+		format := int16(0)
+		switch {
+		case len(formats) == 1:
+			format = formats[0]
+		case i < len(formats):
+			format = formats[i]
+		}
+		b = appendCString(b, name)
+		b = appendInt32(b, 0)          // table OID
+		b = appendInt16(b, 0)          // column number
+		b = appendInt32(b, pgTypeText) // type OID
+		b = appendInt16(b, -1)         // type size
+		b = appendInt32(b, -1)         // type modifier
+		b = appendInt16(b, format)     // format code
+	}
+	c.send('T', b) // RowDescription
+}
+
+// sendResult sends the rows of a result, preceded by their description
+// if withDescription is true, and followed by the command tag.
+func (c *PostgresEngineConn) sendResult(result *pgResult, withDescription bool) {
+	rowsAffected := result.rowsAffected
+	if result.header != nil {
+		if withDescription {
+			c.sendDescription(result, nil)
+		}
+		for _, row := range result.rows {
+			b := appendInt16(nil, int16(len(row))) //nolint:gosec // the number of columns is small
+			for _, v := range row {
+				if v == nil {
+					b = appendInt32(b, -1)
+					continue
+				}
+				b = appendInt32(b, int32(len(*v))) //nolint:gosec // values are smaller than maxFrameSize
+				b = append(b, *v...)
+			}
+			c.send('D', b) // DataRow
+		}
+		rowsAffected = int64(len(result.rows))
+	}
+	c.send('C', appendCString(nil, CommandTag(result.command, rowsAffected))) // CommandComplete
+}
+
+// fail sends an error answering a message of the extended query protocol.
+// The following messages are discarded until the next Sync.
+func (c *PostgresEngineConn) fail(err error, code string) {
+	c.sendError(err, "ERROR", code)
+	c.failed = true
+}
+
+// sendError sends an ErrorResponse.
+func (c *PostgresEngineConn) sendError(err error, severity string, code string) {
+	var b []byte
+	b = appendCString(append(b, 'S'), severity)
+	b = appendCString(append(b, 'V'), severity)
+	b = appendCString(append(b, 'C'), code)
+	b = appendCString(append(b, 'M'), err.Error())
+	b = append(b, 0)
+	c.send('E', b)
+}
+
+// readyForQuery sends ReadyForQuery. Statements are applied immediately,
+// so the connection is always idle.
+func (c *PostgresEngineConn) readyForQuery() {
+	c.send('Z', []byte{'I'})
+}
+
+// send buffers a message. Write errors are reported by the next flush.
+func (c *PostgresEngineConn) send(typ byte, body []byte) {
+	b := make([]byte, 0, 5+len(body))
+	b = append(b, typ)
+	b = appendInt32(b, int32(4+len(body))) //nolint:gosec // bodies are smaller than maxFrameSize
+	b = append(b, body...)
+	c.w.Write(b) //nolint
+}
+
+// readMessage reads a message of the client.
+func (c *PostgresEngineConn) readMessage() (byte, []byte, error) {
+	var head [5]byte
+	if _, err := io.ReadFull(c.r, head[:]); err != nil {
+		return 0, nil, err
+	}
+	length := int(binary.BigEndian.Uint32(head[1:]))
+	if length < 4 || length > maxFrameSize {
+		return 0, nil, fmt.Errorf("protocol error: invalid message length %d", length)
+	}
+	body := make([]byte, length-4)
+	if _, err := io.ReadFull(c.r, body); err != nil {
+		return 0, nil, unexpectedEOF(err)
+	}
+	return head[0], body, nil
+}
+
+// Describing returns true if the statement run by the engine answers a Describe
+// message, the engine only writes the row header of its result then.
+func (c *PostgresEngineConn) Describing() bool {
+	return c.running != nil && c.running.describing()
+}
+
+// WriteResult buffers the result of a statement.
+func (c *PostgresEngineConn) WriteResult(_ int64, rowsAffected int64) error {
+	result, err := c.result()
+	if err != nil {
+		return err
+	}
+	result.rowsAffected = rowsAffected
+	return nil
+}
+
+// WriteError buffers the error of a statement.
+func (c *PostgresEngineConn) WriteError(err error) error {
+	result, rerr := c.result()
+	if rerr != nil {
+		return rerr
+	}
+	result.err = err
+	return nil
+}
+
+// WriteRowHeader buffers the header of a row set.
+func (c *PostgresEngineConn) WriteRowHeader(header []string) error {
+	result, err := c.result()
+	if err != nil {
+		return err
+	}
+	result.header = append([]string{}, header...)
+	result.rows = [][]*string{}
+	return nil
+}
+
+// WriteRow buffers a row of a row set.
+func (c *PostgresEngineConn) WriteRow(row []*string) error {
+	result, err := c.result()
+	if err != nil {
+		return err
+	}
+	result.rows = append(result.rows, append([]*string{}, row...))
+	return nil
+}
+
+// WriteRowEnd ends a row set.
+func (c *PostgresEngineConn) WriteRowEnd() error {
+	_, err := c.result()
+	return err
+}
+
+// result returns the result of the statement run by the engine.
+func (c *PostgresEngineConn) result() (*pgResult, error) {
+	if c.running == nil {
+		return nil, errors.New("protocol error: no statement is running")
+	}
+	return c.running.result, nil
+}
+
+// close closes the socket and releases the connection.
+func (c *PostgresEngineConn) close() {
+	c.once.Do(func() {
+		c.conn.Close() //nolint
+		c.release()
+	})
+}
+
+// sqlState returns the SQLSTATE code of an error of the engine.
+func sqlState(err error) string {
+	switch classifyError(err) {
+	case errorUniqueViolation:
+		return "23505"
+	case errorNotNullViolation:
+		return "23502"
+	case errorDuplicateTable:
+		return "42P07"
+	case errorUndefinedTable:
+		return "42P01"
+	case errorUndefinedColumn:
+		return "42703"
+	case errorNotSupported:
+		return "0A000"
+	case errorSyntax:
+		return "42601"
+	default:
+		return "XX000"
+	}
+}
+
+// decodeParameter returns the text value of a parameter sent in the given format
+// (0 text, 1 binary) for the given type OID.
+func decodeParameter(b []byte, format int16, oid uint32) (string, error) {
+	if format == 0 {
+		return string(b), nil
+	}
+
+	switch oid {
+	case 0, pgTypeText, pgTypeVarchar, pgTypeBpchar, pgTypeName, pgTypeUnknown, pgTypeBytea:
+		return string(b), nil
+	case pgTypeBool:
+		if len(b) != 1 {
+			return "", errors.New("invalid binary boolean")
+		}
+		return strconv.FormatBool(b[0] != 0), nil
+	case pgTypeInt2, pgTypeInt4, pgTypeInt8:
+		switch len(b) {
+		case 2:
+			return strconv.FormatInt(int64(int16(binary.BigEndian.Uint16(b))), 10), nil //nolint:gosec // two's complement
+		case 4:
+			return strconv.FormatInt(int64(int32(binary.BigEndian.Uint32(b))), 10), nil //nolint:gosec // two's complement
+		case 8:
+			return strconv.FormatInt(int64(binary.BigEndian.Uint64(b)), 10), nil //nolint:gosec // two's complement
+		}
+		return "", errors.New("invalid binary integer")
+	case pgTypeFloat4:
+		if len(b) != 4 {
+			return "", errors.New("invalid binary float4")
+		}
+		return strconv.FormatFloat(float64(math.Float32frombits(binary.BigEndian.Uint32(b))), 'g', -1, 32), nil
+	case pgTypeFloat8:
+		if len(b) != 8 {
+			return "", errors.New("invalid binary float8")
+		}
+		return strconv.FormatFloat(math.Float64frombits(binary.BigEndian.Uint64(b)), 'g', -1, 64), nil
+	case pgTypeDate:
+		if len(b) != 4 {
+			return "", errors.New("invalid binary date")
+		}
+		days := int(int32(binary.BigEndian.Uint32(b))) //nolint:gosec // two's complement
+		return pgEpoch().AddDate(0, 0, days).Format("2006-01-02"), nil
+	case pgTypeTimestamp, pgTypeTimestampTZ:
+		if len(b) != 8 {
+			return "", errors.New("invalid binary timestamp")
+		}
+		us := int64(binary.BigEndian.Uint64(b)) //nolint:gosec // two's complement
+		return pgEpoch().Add(time.Duration(us) * time.Microsecond).Format("2006-01-02 15:04:05.999999999"), nil
+	case pgTypeUUID:
+		if len(b) != 16 {
+			return "", errors.New("invalid binary uuid")
+		}
+		return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+	default:
+		return "", fmt.Errorf("binary format is not supported for type OID %d", oid)
+	}
+}
+
+// quoteParameter returns the SQL representation of a parameter value.
+// Like the driver, values are escaped with $$ so that they are lexed as a single token.
+func quoteParameter(v *string) (string, error) {
+	if v == nil {
+		return "null", nil
+	}
+	if strings.Contains(*v, "$$") {
+		return "", errors.New("parameter must not contain $$")
+	}
+	return "$$" + *v + "$$", nil
+}
+
+// replaceParameters replaces the $n parameters of a query with the values
+// returned by replace, called with n. Parameters inside quotes and comments are left untouched.
+func replaceParameters(query string, replace func(n int) (string, error)) (string, error) {
+	var b strings.Builder
+	var scanner postgres.Scanner
+	for i := 0; i < len(query); {
+		end, kind := scanner.Next(query, i)
+		if kind != postgres.ScanCode || query[i] != '$' || i+1 == len(query) || !isDigit(query[i+1]) {
+			b.WriteString(query[i:end])
+			i = end
+			continue
+		}
+
+		j := i + 1
+		for j < len(query) && isDigit(query[j]) {
+			j++
+		}
+		n, err := strconv.Atoi(query[i+1 : j])
+		if err != nil {
+			return "", err
+		}
+		v, err := replace(n)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(v)
+		i = j
+	}
+	return b.String(), nil
+}
+
+// splitQuery splits the statements of a simple query on semicolons.
+// Semicolons inside quotes and comments do not terminate a statement.
+// Empty statements are dropped.
+func splitQuery(query string) []string {
+	stmts := []string{}
+	start := 0
+	flush := func(end int) {
+		if stmt := strings.TrimSpace(query[start:end]); stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+		start = end + 1
+	}
+
+	var scanner postgres.Scanner
+	for i := 0; i < len(query); {
+		end, kind := scanner.Next(query, i)
+		if kind == postgres.ScanCode && query[i] == ';' {
+			flush(i)
+		}
+		i = end
+	}
+	flush(len(query))
+	return stmts
+}
+
+// isDigit returns true if c is an ASCII digit.
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+// pgReader reads the fields of a message body. The first read
+// error is recorded, following reads return zero values.
+type pgReader struct {
+	// buf is the unread part of the body.
+	buf []byte
+	// err is the first read error.
+	err error
+}
+
+// next returns the next n bytes of the body.
+func (r *pgReader) next(n int) []byte {
+	if r.err != nil {
+		return nil
+	}
+	if n > len(r.buf) {
+		r.err = errors.New("protocol error: message is too short")
+		return nil
+	}
+	b := r.buf[:n]
+	r.buf = r.buf[n:]
+	return b
+}
+
+// byte reads a byte.
+func (r *pgReader) byte() byte {
+	b := r.next(1)
+	if b == nil {
+		return 0
+	}
+	return b[0]
+}
+
+// int16 reads a big-endian int16.
+func (r *pgReader) int16() int16 {
+	b := r.next(2)
+	if b == nil {
+		return 0
+	}
+	return int16(binary.BigEndian.Uint16(b)) //nolint:gosec // two's complement
+}
+
+// int32 reads a big-endian int32.
+func (r *pgReader) int32() int32 {
+	b := r.next(4)
+	if b == nil {
+		return 0
+	}
+	return int32(binary.BigEndian.Uint32(b)) //nolint:gosec // two's complement
+}
+
+// int16s reads a list of int16 preceded by its length.
+func (r *pgReader) int16s() []int16 {
+	n := int(r.int16())
+	values := []int16{}
+	for i := 0; i < n && r.err == nil; i++ {
+		values = append(values, r.int16())
+	}
+	return values
+}
+
+// bytes reads a value preceded by its length. It returns nil for NULL (length -1).
+func (r *pgReader) bytes() []byte {
+	n := int(r.int32())
+	if n < 0 || r.err != nil {
+		return nil
+	}
+	b := r.next(n)
+	if r.err != nil {
+		return nil
+	}
+	return append([]byte{}, b...)
+}
+
+// cstring reads a null-terminated string.
+func (r *pgReader) cstring() string {
+	if r.err != nil {
+		return ""
+	}
+	i := bytes.IndexByte(r.buf, 0)
+	if i < 0 {
+		r.err = errors.New("protocol error: unterminated string")
+		return ""
+	}
+	s := string(r.buf[:i])
+	r.buf = r.buf[i+1:]
+	return s
+}
+
+// appendInt16 appends a big-endian int16.
+func appendInt16(b []byte, v int16) []byte {
+	return binary.BigEndian.AppendUint16(b, uint16(v)) //nolint:gosec // two's complement
+}
+
+// appendInt32 appends a big-endian int32.
+func appendInt32(b []byte, v int32) []byte {
+	return binary.BigEndian.AppendUint32(b, uint32(v)) //nolint:gosec // two's complement
+}
+
+// appendCString appends a null-terminated string.
+func appendCString(b []byte, s string) []byte {
+	return append(append(b, s...), 0)
+}
@@ -0,0 +1,550 @@
+package protocol
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// pgClient is a minimal PostgreSQL frontend recording the answers of the server
+// as readable strings, e.g. "DataRow [1 foo]".
+type pgClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+// newPgClient connects to the endpoint and runs the startup, asking for SSL first like psql.
+func newPgClient(t *testing.T, endpoint *NetworkEngineEndpoint) *pgClient {
+	t.Helper()
+
+	conn, err := net.Dial("tcp", endpoint.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := &pgClient{conn: conn, r: bufio.NewReader(conn)}
+
+	ssl := appendInt32(appendInt32(nil, 8), pgSSLRequest)
+	if _, err := conn.Write(ssl); err != nil {
+		t.Fatal(err)
+	}
+	if b, err := c.r.ReadByte(); err != nil || b != 'N' {
+		t.Fatalf("mismatch SSL answer: want=N, got=(%q, %v)", b, err)
+	}
+
+	body := appendInt32(nil, pgProtocolVersion)
+	body = appendCString(appendCString(body, "user"), "aion")
+	body = appendCString(appendCString(body, "database"), "testdb")
+	body = append(body, 0)
+	if _, err := conn.Write(append(appendInt32(nil, int32(4+len(body))), body...)); err != nil {
+		t.Fatal(err)
+	}
+	got := c.readUntilReady(t)
+	if got[0] != "AuthenticationOk" || got[len(got)-1] != "ReadyForQuery I" {
+		t.Fatalf("unexpected startup answer: %v", got)
+	}
+	return c
+}
+
+// send sends messages to the server.
+func (c *pgClient) send(t *testing.T, msgs ...[]byte) {
+	t.Helper()
+	for _, m := range msgs {
+		if _, err := c.conn.Write(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// pgMessage returns a message of the given type.
+func pgMessage(typ byte, body []byte) []byte {
+	return append(appendInt32([]byte{typ}, int32(4+len(body))), body...)
+}
+
+// readUntilReady reads the answers of the server until ReadyForQuery.
+func (c *pgClient) readUntilReady(t *testing.T) []string {
+	t.Helper()
+
+	got := []string{}
+	for {
+		typ, err := c.r.ReadByte()
+		if err != nil {
+			t.Fatal(err)
+		}
+		var length [4]byte
+		if _, err := io.ReadFull(c.r, length[:]); err != nil {
+			t.Fatal(err)
+		}
+		body := make([]byte, binary.BigEndian.Uint32(length[:])-4)
+		if _, err := io.ReadFull(c.r, body); err != nil {
+			t.Fatal(err)
+		}
+
+		msg := &pgReader{buf: body}
+		switch typ {
+		case 'R':
+			got = append(got, "AuthenticationOk")
+		case 'S', 'K':
+		case 'Z':
+			got = append(got, "ReadyForQuery "+string(msg.byte()))
+			return got
+		case 'T':
+			names := []string{}
+			for i := msg.int16(); i > 0; i-- {
+				names = append(names, msg.cstring())
+				msg.next(18)
+			}
+			got = append(got, fmt.Sprintf("RowDescription %v", names))
+		case 'D':
+			values := []string{}
+			for i := msg.int16(); i > 0; i-- {
+				v := msg.bytes()
+				if v == nil {
+					values = append(values, "NULL")
+					continue
+				}
+				values = append(values, string(v))
+			}
+			got = append(got, fmt.Sprintf("DataRow %v", values))
+		case 'C':
+			got = append(got, "CommandComplete "+msg.cstring())
+		case 'E':
+			fields := map[byte]string{}
+			for code := msg.byte(); code != 0; code = msg.byte() {
+				fields[code] = msg.cstring()
+			}
+			got = append(got, fmt.Sprintf("ErrorResponse %s %s", fields['C'], fields['M']))
+		case 't':
+			got = append(got, fmt.Sprintf("ParameterDescription %d", msg.int16()))
+		case '1':
+			got = append(got, "ParseComplete")
+		case '2':
+			got = append(got, "BindComplete")
+		case '3':
+			got = append(got, "CloseComplete")
+		case 'n':
+			got = append(got, "NoData")
+		case 'I':
+			got = append(got, "EmptyQueryResponse")
+		default:
+			got = append(got, fmt.Sprintf("unexpected %q", typ))
+		}
+	}
+}
+
+// pgQuery returns a simple Query message.
+func pgQuery(q string) []byte {
+	return pgMessage('Q', appendCString(nil, q))
+}
+
+// pgParse returns a Parse message of the unnamed statement.
+func pgParse(q string, types ...int32) []byte {
+	b := appendInt16(appendCString(appendCString(nil, ""), q), int16(len(types)))
+	for _, oid := range types {
+		b = appendInt32(b, oid)
+	}
+	return pgMessage('P', b)
+}
+
+// pgBind returns a Bind message of the unnamed portal. nil parameters are NULL,
+// parameters are sent in binary format if binary is true.
+func pgBind(binary bool, params ...[]byte) []byte {
+	b := appendCString(appendCString(nil, ""), "")
+	if binary {
+		b = appendInt16(appendInt16(b, 1), 1)
+	} else {
+		b = appendInt16(b, 0)
+	}
+	b = appendInt16(b, int16(len(params)))
+	for _, p := range params {
+		if p == nil {
+			b = appendInt32(b, -1)
+			continue
+		}
+		b = append(appendInt32(b, int32(len(p))), p...)
+	}
+	return pgMessage('B', appendInt16(b, 0))
+}
+
+// pgDescribe returns a Describe message of the unnamed statement ('S') or portal ('P').
+func pgDescribe(kind byte) []byte {
+	return pgMessage('D', appendCString([]byte{kind}, ""))
+}
+
+// pgExecute returns an Execute message of the unnamed portal.
+func pgExecute() []byte {
+	return pgMessage('E', appendInt32(appendCString(nil, ""), 0))
+}
+
+// pgSync returns a Sync message.
+func pgSync() []byte {
+	return pgMessage('S', nil)
+}
+
+// servePostgres starts an engine answering like serve on a PostgreSQL endpoint.
+func servePostgres(t *testing.T) *NetworkEngineEndpoint {
+	t.Helper()
+
+	endpoint, err := NewPostgresEngineEndpoint("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(endpoint.Close)
+
+	go func() {
+		for {
+			conn, err := endpoint.Accept()
+			if err != nil {
+				return
+			}
+			go func() {
+				for {
+					stmt, err := conn.ReadStatement()
+					if err != nil {
+						return
+					}
+					describing := conn.(DescribeConn).Describing()
+					switch {
+					case Command(stmt) == "SELECT" || strings.Contains(stmt, "RETURNING"):
+						_ = conn.WriteRowHeader([]string{"id", "name"})
+						if !describing {
+							_ = conn.WriteRow([]*string{text("1"), text(stmt)})
+							_ = conn.WriteRow([]*string{text("2"), nil})
+						}
+						_ = conn.WriteRowEnd()
+					case describing:
+					case strings.HasPrefix(stmt, "fail"):
+						_ = conn.WriteError(errors.New("duplicate key value violates unique constraint on column \"id\""))
+					default:
+						_ = conn.WriteResult(0, 1)
+					}
+				}
+			}()
+		}
+	}()
+	return endpoint
+}
+
+func TestPostgresEngineConn(t *testing.T) {
+	t.Parallel()
+
+	int8Param := binary.BigEndian.AppendUint64(nil, 42)
+	tests := []struct {
+		name string
+		msgs [][]byte
+		want []string
+	}{
+		{
+			name: "Simple query with several statements",
+			msgs: [][]byte{pgQuery("INSERT INTO t VALUES (1); SELECT 'a;b'")},
+			want: []string{
+				"CommandComplete INSERT 0 1",
+				"RowDescription [id name]",
+				"DataRow [1 SELECT 'a;b']",
+				"DataRow [2 NULL]",
+				"CommandComplete SELECT 2",
+				"ReadyForQuery I",
+			},
+		},
+		{
+			name: "Error stops the simple query",
+			msgs: [][]byte{pgQuery("fail; INSERT INTO t VALUES (1)")},
+			want: []string{
+				`ErrorResponse 23505 duplicate key value violates unique constraint on column "id"`,
+				"ReadyForQuery I",
+			},
+		},
+		{
+			name: "Empty query",
+			msgs: [][]byte{pgQuery(" ; ")},
+			want: []string{"EmptyQueryResponse", "ReadyForQuery I"},
+		},
+		{
+			name: "Transaction statements are accepted",
+			msgs: [][]byte{pgQuery("BEGIN; COMMIT")},
+			want: []string{"CommandComplete BEGIN", "CommandComplete COMMIT", "ReadyForQuery I"},
+		},
+		{
+			name: "Extended query with text parameters",
+			msgs: [][]byte{
+				pgParse("SELECT * FROM t WHERE name = $1 AND id = $2"),
+				pgBind(false, []byte("foo"), nil),
+				pgDescribe('P'),
+				pgExecute(),
+				pgSync(),
+			},
+			want: []string{
+				"ParseComplete",
+				"BindComplete",
+				"RowDescription [id name]",
+				"DataRow [1 SELECT * FROM t WHERE name = $$foo$$ AND id = null]",
+				"DataRow [2 NULL]",
+				"CommandComplete SELECT 2",
+				"ReadyForQuery I",
+			},
+		},
+		{
+			name: "Extended query with binary parameters",
+			msgs: [][]byte{
+				pgParse("SELECT * FROM t WHERE id = $1", pgTypeInt8),
+				pgBind(true, int8Param),
+				pgExecute(),
+				pgSync(),
+			},
+			want: []string{
+				"ParseComplete",
+				"BindComplete",
+				"DataRow [1 SELECT * FROM t WHERE id = $$42$$]",
+				"DataRow [2 NULL]",
+				"CommandComplete SELECT 2",
+				"ReadyForQuery I",
+			},
+		},
+		{
+			name: "Describe statement",
+			msgs: [][]byte{
+				pgParse("SELECT * FROM t WHERE id = $1"),
+				pgDescribe('S'),
+				pgParse("INSERT INTO t VALUES ($1, $2)"),
+				pgDescribe('S'),
+				pgSync(),
+			},
+			want: []string{
+				"ParseComplete",
+				"ParameterDescription 1",
+				"RowDescription [id name]",
+				"ParseComplete",
+				"ParameterDescription 2",
+				"NoData",
+				"ReadyForQuery I",
+			},
+		},
+		{
+			name: "Describe portal does not run the statement",
+			msgs: [][]byte{
+				pgParse("SELECT * FROM t WHERE id = $1"),
+				pgBind(false, []byte("1")),
+				pgDescribe('P'),
+				pgParse("UPDATE t SET name = $1"),
+				pgBind(false, []byte("foo")),
+				pgDescribe('P'),
+				pgSync(),
+			},
+			want: []string{
+				"ParseComplete",
+				"BindComplete",
+				"RowDescription [id name]",
+				"ParseComplete",
+				"BindComplete",
+				"NoData",
+				"ReadyForQuery I",
+			},
+		},
+		{
+			name: "Extended query with RETURNING",
+			msgs: [][]byte{
+				pgParse("INSERT INTO t VALUES ($1) RETURNING id, name"),
+				pgDescribe('S'),
+				pgBind(false, []byte("1")),
+				pgExecute(),
+				pgSync(),
+			},
+			want: []string{
+				"ParseComplete",
+				"ParameterDescription 1",
+				"RowDescription [id name]",
+				"BindComplete",
+				"DataRow [1 INSERT INTO t VALUES ($$1$$) RETURNING id, name]",
+				"DataRow [2 NULL]",
+				"CommandComplete INSERT 0 2",
+				"ReadyForQuery I",
+			},
+		},
+		{
+			name: "Extended query with a leading comment",
+			msgs: [][]byte{
+				pgParse("-- comment\n/* another\ncomment */ SELECT * FROM t WHERE id = $1"),
+				pgDescribe('S'),
+				pgBind(false, []byte("1")),
+				pgExecute(),
+				pgParse("/* comment */ INSERT INTO t VALUES ($1)"),
+				pgBind(false, []byte("1")),
+				pgDescribe('P'),
+				pgExecute(),
+				pgSync(),
+			},
+			want: []string{
+				"ParseComplete",
+				"ParameterDescription 1",
+				"RowDescription [id name]",
+				"BindComplete",
+				"DataRow [1 -- comment\n/* another\ncomment */ SELECT * FROM t WHERE id = $$1$$]",
+				"DataRow [2 NULL]",
+				"CommandComplete SELECT 2",
+				"ParseComplete",
+				"BindComplete",
+				"NoData",
+				"CommandComplete INSERT 0 1",
+				"ReadyForQuery I",
+			},
+		},
+		{
+			name: "Error discards messages until Sync",
+			msgs: [][]byte{
+				pgParse("fail"),
+				pgBind(false),
+				pgExecute(),
+				pgParse("INSERT INTO t VALUES (1)"),
+				pgSync(),
+				pgBind(false),
+				pgExecute(),
+				pgSync(),
+			},
+			want: []string{
+				"ParseComplete",
+				"BindComplete",
+				`ErrorResponse 23505 duplicate key value violates unique constraint on column "id"`,
+				"ReadyForQuery I",
+				"BindComplete",
+				`ErrorResponse 23505 duplicate key value violates unique constraint on column "id"`,
+				"ReadyForQuery I",
+			},
+		},
+		{
+			name: "Parameter $0 does not exist",
+			msgs: [][]byte{
+				pgParse("SELECT * FROM t WHERE id = $0"),
+				pgBind(false, []byte("1")),
+				pgExecute(),
+				pgSync(),
+			},
+			want: []string{
+				"ParseComplete",
+				"ErrorResponse 42P02 there is no parameter $0",
+				"ReadyForQuery I",
+			},
+		},
+		{
+			name: "Unknown prepared statement",
+			msgs: [][]byte{
+				pgMessage('B', appendInt16(appendInt16(appendInt16(appendCString(appendCString(nil, ""), "missing"), 0), 0), 0)),
+				pgSync(),
+			},
+			want: []string{
+				`ErrorResponse 26000 prepared statement "missing" does not exist`,
+				"ReadyForQuery I",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			c := newPgClient(t, servePostgres(t))
+			defer c.conn.Close()
+
+			c.send(t, tt.msgs...)
+			got := []string{}
+			for len(got) < len(tt.want) {
+				got = append(got, c.readUntilReady(t)...)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+
+			// Terminate closes the connection
+			c.send(t, pgMessage('X', nil))
+			if _, err := c.r.ReadByte(); !errors.Is(err, io.EOF) {
+				t.Errorf("mismatch error: want=%v, got=%v", io.EOF, err)
+			}
+		})
+	}
+}
+
+func TestSplitQuery(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{
+			name:  "Single statement without semicolon",
+			query: "SELECT * FROM t",
+			want:  []string{"SELECT * FROM t"},
+		},
+		{
+			name:  "Semicolons in quotes and comments",
+			query: "INSERT INTO t VALUES ('a;b', $$c;d$$); -- e;f\nSELECT \"g;h\" FROM t /* i;j */;",
+			want:  []string{"INSERT INTO t VALUES ('a;b', $$c;d$$)", "-- e;f\nSELECT \"g;h\" FROM t /* i;j */"},
+		},
+		{
+			name:  "Escaped quotes",
+			query: "SELECT 'a'';b', E'c\\';d'; SELECT 1",
+			want:  []string{"SELECT 'a'';b', E'c\\';d'", "SELECT 1"},
+		},
+		{
+			name:  "Empty statements",
+			query: " ;; ",
+			want:  []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			if diff := cmp.Diff(tt.want, splitQuery(tt.query)); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReplaceParameters(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "Parameters",
+			query: "SELECT * FROM t WHERE a = $1 AND b = $2",
+			want:  "SELECT * FROM t WHERE a = p1 AND b = p2",
+		},
+		{
+			name:  "Parameters in quotes and comments",
+			query: "SELECT E'it\\'s $1', $tag$ $1 $tag$, \"$1\" FROM t -- $1\nWHERE a = /* $1 */ $1",
+			want:  "SELECT E'it\\'s $1', $tag$ $1 $tag$, \"$1\" FROM t -- $1\nWHERE a = /* $1 */ p1",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := replaceParameters(tt.query, func(n int) (string, error) {
+				return fmt.Sprintf("p%d", n), nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
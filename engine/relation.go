@@ -13,6 +13,8 @@ type Relation struct {
 	table *Table
 	// rows is the slice of rows of the relation
 	rows []*Tuple
+	// sequence is the last value assigned to the auto-incremented attribute
+	sequence int64
 }
 
 // NewRelation initializes a new Relation struct
@@ -1,157 +1,114 @@
 package engine
 
 import (
+	"errors"
 	"fmt"
 
 	"github.com/nao1215/aiondb/engine/parser/core"
 	"github.com/nao1215/aiondb/engine/protocol"
 )
 
-// fromExecutor returns a slice of tables from a FROM declaration
-func fromExecutor(fromDecl *core.Decl) []*Table {
-	tables := make([]*Table, 0, len(fromDecl.DeclList))
-	for _, t := range fromDecl.DeclList {
-		tables = append(tables, NewTable(t.Lexeme.String()))
+// whereExecutor returns the predicates of a WHERE condition, a row must validate all of them.
+// A nil condition, e.g. the implicit WHERE 1, is validated by all rows.
+func whereExecutor(where core.Expr, fromTableName string) ([]Predicate, error) {
+	if where == nil {
+		return []Predicate{{True: true}}, nil
 	}
-	return tables
+
+	if b, ok := where.(*core.BinaryExpr); ok && (b.Op == core.OpAnd || b.Op == core.OpOr) {
+		if b.Op == core.OpOr {
+			return nil, errors.New("OR is not supported in WHERE clause")
+		}
+		left, err := whereExecutor(b.Left, fromTableName)
+		if err != nil {
+			return nil, err
+		}
+		right, err := whereExecutor(b.Right, fromTableName)
+		if err != nil {
+			return nil, err
+		}
+		return append(left, right...), nil
+	}
+
+	p, err := predicateExecutor(where, fromTableName)
+	if err != nil {
+		return nil, err
+	}
+	return []Predicate{p}, nil
 }
 
-// whereExecutor returns a slice of predicates from a WHERE declaration.
-func whereExecutor(whereDecl *core.Decl, fromTableName string) ([]Predicate, error) {
-	predicates := make([]Predicate, 0, len(whereDecl.DeclList))
+// predicateExecutor returns the predicate of a condition of a WHERE clause.
+func predicateExecutor(cond core.Expr, fromTableName string) (Predicate, error) {
+	var p Predicate
 	var err error
-	whereDecl.String(0)
-
-	for i := range whereDecl.DeclList {
-		var p Predicate
-		tableName := fromTableName
-		cond := whereDecl.DeclList[i]
 
+	switch c := cond.(type) {
+	case *core.Literal:
 		// 1 PREDICATE
-		if cond.Lexeme == "1" {
-			predicates = append(predicates, Predicate{True: true})
-			continue
+		if c.Kind != core.LiteralNumber || c.Value != "1" {
+			return p, fmt.Errorf("malformed predicate \"%s\"", c.Value)
 		}
-
-		if len(cond.DeclList) == 0 {
-			// log.Debug("whereExecutor: you must be AND or OR: %v", cond)
-			continue
+		p.True = true
+	case *core.InExpr:
+		if p.LeftValue, err = attributeValueOf(c.Expr, fromTableName); err != nil {
+			return p, err
 		}
-
-		switch cond.DeclList[0].TokenID {
-		case core.TokenIDEquality, core.TokenIDDistinctness, core.TokenIDLeftDiple, core.TokenIDRightDiple, core.TokenIDLessOrEqual, core.TokenIDGreaterOrEqual:
-			// log.Debug("whereExecutor: it's = <> < > <= >=\n")
-		case core.TokenIDIn:
-			// log.Debug("whereExecutor: it's IN\n")
-		case core.TokenIDNot:
-			// log.Debug("whereExecutor: it's NOT\n")
-		case core.TokenIDIs:
-			// log.Debug("whereExecutor: it's IS token\n")
-			// log.Debug("whereExecutor: %+v\n", cond.DeclList[0])
-		default:
-			// log.Debug("it's the table name ! -> %s", cond.DeclList[0].Lexeme)
-			tableName = cond.DeclList[0].Lexeme.String()
-			cond.DeclList = cond.DeclList[1:]
-		}
-
-		p.LeftValue.lexeme = whereDecl.DeclList[i].Lexeme.String()
-		// Handle IN keyword
-		if cond.DeclList[0].TokenID == core.TokenIDIn {
-			err := inExecutor(cond.DeclList[0], &p)
-			if err != nil {
-				return nil, err
-			}
-			p.LeftValue.table = tableName
-			predicates = append(predicates, p)
-			continue
+		p.Operator = inOperator
+		if c.Not {
+			p.Operator = notInOperator
 		}
-
-		// Handle NOT IN keywords
-		if cond.DeclList[0].TokenID == core.TokenIDNot && cond.DeclList[0].DeclList[0].TokenID == core.TokenIDIn {
-			err := notInExecutor(cond.DeclList[0].DeclList[0], &p)
+		values := make([]string, 0, len(c.List))
+		for _, expr := range c.List {
+			v, err := valueText(expr)
 			if err != nil {
-				return nil, err
+				return p, err
 			}
-			p.LeftValue.table = tableName
-			predicates = append(predicates, p)
-			continue
+			values = append(values, v)
 		}
-
-		// Handle IS NULL and IS NOT NULL
-		if cond.DeclList[0].TokenID == core.TokenIDIs {
-			err := isExecutor(cond.DeclList[0], &p)
-			if err != nil {
-				return nil, err
-			}
-			p.LeftValue.table = tableName
-			predicates = append(predicates, p)
-			continue
+		p.RightValue.v = values
+	case *core.IsNullExpr:
+		if p.LeftValue, err = attributeValueOf(c.Expr, fromTableName); err != nil {
+			return p, err
 		}
-
-		// 2 is the minimum number of elements in a predicate
-		if len(cond.DeclList) < 2 {
-			return nil, fmt.Errorf("malformed predicate \"%s\"", cond.Lexeme)
+		p.Operator = isNullOperator
+		if c.Not {
+			p.Operator = isNotNullOperator
 		}
-
-		// The first element of the list is then the relation of the attribute
-		op := cond.DeclList[0]
-		val := cond.DeclList[1]
-
-		p.Operator, err = NewOperator(op.TokenID, op.Lexeme.String())
-		if err != nil {
-			return nil, err
+	case *core.BinaryExpr:
+		if p.LeftValue, err = attributeValueOf(c.Left, fromTableName); err != nil {
+			return p, err
+		}
+		if p.Operator, err = NewOperator(c.Op); err != nil {
+			return p, err
+		}
+		right := c.Right
+		// The value may be followed by COLLATE NOCASE
+		if collate, ok := right.(*core.CollateExpr); ok {
+			p.LeftValue.nocase = collate.Collation == "NOCASE"
+			right = collate.Expr
+		}
+		if p.RightValue.lexeme, err = valueText(right); err != nil {
+			return p, err
 		}
-		p.RightValue.lexeme = val.Lexeme.String()
 		p.RightValue.valid = true
-		p.LeftValue.table = tableName
-		predicates = append(predicates, p)
+	default:
+		return p, fmt.Errorf("unsupported predicate %T", cond)
 	}
-
-	if len(predicates) == 0 {
-		return nil, fmt.Errorf("no predicates provided")
-	}
-	return predicates, nil
+	return p, nil
 }
 
-// inExecutor handles the IN operator
-func inExecutor(inDecl *core.Decl, p *Predicate) error {
-	inDecl.String(0)
-	p.Operator = inOperator
-
-	// Put everything in a []string
-	values := make([]string, 0, len(inDecl.DeclList))
-	for i := range inDecl.DeclList {
-		values = append(values, inDecl.DeclList[i].Lexeme.String())
+// attributeValueOf returns the value of the attribute compared by a predicate.
+// Attributes which are not qualified belong to the FROM table.
+func attributeValueOf(expr core.Expr, fromTableName string) (Value, error) {
+	column, ok := expr.(*core.ColumnRef)
+	if !ok {
+		return Value{}, fmt.Errorf("expected attribute in predicate, got %T", expr)
 	}
-	p.RightValue.v = values
-	return nil
-}
-
-// notInExecutor handles the NOT IN operator
-func notInExecutor(inDecl *core.Decl, p *Predicate) error {
-	inDecl.String(0)
-	p.Operator = notInOperator
-
-	// Put everything in a []string
-	values := make([]string, 0, len(inDecl.DeclList))
-	for i := range inDecl.DeclList {
-		values = append(values, inDecl.DeclList[i].Lexeme.String())
-	}
-	p.RightValue.v = values
-
-	return nil
-}
-
-// isExecutor handles the IS operator
-func isExecutor(isDecl *core.Decl, p *Predicate) error {
-	isDecl.String(0)
-
-	if isDecl.DeclList[0].TokenID == core.TokenIDNull {
-		p.Operator = isNullOperator
-	} else {
-		p.Operator = isNotNullOperator
+	v := Value{lexeme: column.Name, table: column.Table}
+	if v.table == "" {
+		v.table = fromTableName
 	}
-	return nil
+	return v, nil
 }
 
 // selectExecutor returns a slice of attributes from a SELECT declaration.
@@ -186,3 +143,296 @@ func selectRows(row virtualRow, predicates []PredicateLinker, functors []selectF
 	}
 	return nil
 }
+
+// selection is the tables and the select list of a SELECT statement.
+type selection struct {
+	// tableNames is the FROM table followed by the joined tables.
+	tableNames []string
+	// joiners is the list of joined tables.
+	joiners []joiner
+	// attributes is the list of selected attributes, computed columns included.
+	// The expressions of DISTINCT ON come first.
+	attributes []Attribute
+	// computed is the list of computed columns of the select list.
+	computed []computedColumn
+	// counted is true if COUNT is selected.
+	counted bool
+	// countedAttribute is the qualified attribute of COUNT(column), whose NULL values
+	// are not counted. It is empty for COUNT(*), which counts every row.
+	countedAttribute string
+}
+
+// selectionExecutor returns the selection of a SELECT statement.
+func selectionExecutor(e *Engine, stmt *core.SelectStmt) (*selection, error) {
+	if len(stmt.From) == 0 {
+		return nil, errors.New("no table provided in FROM clause")
+	}
+	if len(stmt.From) > 1 {
+		return nil, errors.New("selecting from several tables is not supported, use JOIN")
+	}
+
+	s := &selection{tableNames: []string{stmt.From[0].Name}}
+	for _, j := range stmt.Joins {
+		joiner, err := joinExecutor(j)
+		if err != nil {
+			return nil, err
+		}
+		s.joiners = append(s.joiners, joiner)
+		s.tableNames = append(s.tableNames, joiner.On())
+	}
+
+	columns := append(append([]core.Expr{}, stmt.DistinctOn...), stmt.Columns...)
+	for _, expr := range columns {
+		if !isComputedColumn(expr) {
+			attr, err := getSelectedAttributes(e, expr, s.tableNames)
+			if err != nil {
+				return nil, err
+			}
+			s.attributes = append(s.attributes, attr...)
+
+			if f, ok := expr.(*core.FuncCall); ok && f.Name == "count" {
+				s.counted = true
+				if column, ok := f.Args[0].(*core.ColumnRef); ok {
+					if s.countedAttribute, err = qualifyAttribute(e, column, s.tableNames); err != nil {
+						return nil, err
+					}
+				}
+			}
+			continue
+		}
+
+		c, err := computedColumnExecutor(e, expr, s.tableNames, len(s.computed))
+		if err != nil {
+			return nil, err
+		}
+		s.computed = append(s.computed, c)
+		s.attributes = append(s.attributes, Attribute{name: c.name})
+	}
+	if s.counted && len(s.attributes) > 1 {
+		return nil, errors.New("COUNT cannot be selected with other columns")
+	}
+	return s, nil
+}
+
+// header returns the column names of the selected rows, without the
+// expressions of DISTINCT ON which are not returned.
+func (s *selection) header(distinctOn int) []string {
+	if s.counted {
+		return []string{"count"}
+	}
+	header := make([]string, 0, len(s.attributes))
+	for _, a := range s.attributes[distinctOn:] {
+		header = append(header, columnAlias(a.name))
+	}
+	return header
+}
+
+// selectExecutor executes a SELECT statement.
+// The rows of the FROM table, joined with the JOIN tables and filtered by
+// the WHERE predicates, are streamed to conn.
+func selectExecutor(e *Engine, stmt *core.SelectStmt, conn protocol.EngineConn) error {
+	s, err := selectionExecutor(e, stmt)
+	if err != nil {
+		return err
+	}
+	tableName := s.tableNames[0]
+
+	// get WHERE predicates
+	var predicates []PredicateLinker
+	if stmt.Where != nil {
+		p, err := whereExecutor(stmt.Where, tableName)
+		if err != nil {
+			return err
+		}
+		for i := range p {
+			predicates = append(predicates, &p[i])
+		}
+	}
+
+	// Rows go through DISTINCT, then OFFSET, then LIMIT
+	if stmt.Limit != nil {
+		if *stmt.Limit < 0 {
+			return errors.New("LIMIT must not be negative")
+		}
+		conn = limitedConn(conn, int(*stmt.Limit))
+	}
+	if stmt.Offset != nil {
+		if *stmt.Offset < 0 {
+			return errors.New("OFFSET must not be negative")
+		}
+		conn = offsetedConn(conn, int(*stmt.Offset))
+	}
+	if stmt.Distinct {
+		conn = distinctedConn(conn, len(stmt.DistinctOn))
+	}
+
+	var functors []selectFunctor
+	switch {
+	case s.counted:
+		functors = append(functors, &countSelectFunction{attribute: s.countedAttribute})
+	case len(stmt.OrderBy) > 0:
+		f, err := orderbyExecutor(e, stmt.OrderBy, s.tableNames)
+		if err != nil {
+			return err
+		}
+		functors = append(functors, f)
+	default:
+		functors = append(functors, &defaultSelectFunction{})
+	}
+	if len(s.computed) > 0 {
+		functors[0] = &computedSelectFunction{selectFunctor: functors[0], columns: s.computed}
+	}
+
+	return generateVirtualRows(e, s.attributes, conn, tableName, s.joiners, predicates, functors)
+}
+
+// getSelectedAttributes returns the attributes selected by an expression (*, COUNT or a column).
+// Selected columns are qualified with the name of their table.
+func getSelectedAttributes(e *Engine, expr core.Expr, tables []string) ([]Attribute, error) {
+	switch v := expr.(type) {
+	case *core.Star:
+		var attributes []Attribute
+		for _, t := range tables {
+			if v.Table != "" && v.Table != t {
+				// table.*
+				continue
+			}
+			r := e.readRelation(t)
+			if r == nil {
+				return nil, fmt.Errorf("table \"%s\" does not exist", t)
+			}
+			for _, a := range r.table.attributes {
+				attributes = append(attributes, Attribute{name: t + "." + a.name})
+			}
+		}
+		if len(attributes) == 0 {
+			return nil, fmt.Errorf("table \"%s\" is not in FROM clause", v.Table)
+		}
+		return attributes, nil
+	case *core.FuncCall:
+		if v.Name != "count" || len(v.Args) != 1 {
+			return nil, fmt.Errorf("cannot select %s", v.Name)
+		}
+		if column, ok := v.Args[0].(*core.ColumnRef); ok {
+			name, err := qualifyAttribute(e, column, tables)
+			if err != nil {
+				return nil, err
+			}
+			if err := attributesExistInTables(e, []Attribute{{name: name}}, tables); err != nil {
+				return nil, err
+			}
+		}
+		return []Attribute{{name: "COUNT"}}, nil
+	case *core.ColumnRef:
+		name, err := qualifyAttribute(e, v, tables)
+		if err != nil {
+			return nil, err
+		}
+		if err := attributesExistInTables(e, []Attribute{{name: name}}, tables); err != nil {
+			return nil, err
+		}
+		return []Attribute{{name: name}}, nil
+	default:
+		return nil, fmt.Errorf("cannot select %T", expr)
+	}
+}
+
+// qualifyAttribute returns the name of the column prefixed by its table name.
+func qualifyAttribute(e *Engine, column *core.ColumnRef, tables []string) (string, error) {
+	if column.Table != "" {
+		return column.Table + "." + column.Name, nil
+	}
+
+	name := column.Name
+	qualified := ""
+	for _, t := range tables {
+		if attributeExistsInTable(e, name, t) != nil {
+			continue
+		}
+		if qualified != "" {
+			return "", fmt.Errorf("ambiguous attribute %s", name)
+		}
+		qualified = t + "." + name
+	}
+	if qualified == "" {
+		return "", fmt.Errorf("attribute %s does not exist in tables %v", name, tables)
+	}
+	return qualified, nil
+}
+
+// defaultSelectFunction writes every selected row.
+type defaultSelectFunction struct {
+	// conn is the connection rows are written to.
+	conn protocol.EngineConn
+	// attributes is the list of qualified attributes to write.
+	attributes []string
+}
+
+// Init writes the row header.
+func (f *defaultSelectFunction) Init(_ *Engine, conn protocol.EngineConn, attr []string, alias []string) error {
+	f.conn = conn
+	f.attributes = attr
+	return f.conn.WriteRowHeader(alias)
+}
+
+// FeedVirtualRow writes the selected attributes of the row.
+func (f *defaultSelectFunction) FeedVirtualRow(row virtualRow) error {
+	values := make([]*string, 0, len(f.attributes))
+	for _, attr := range f.attributes {
+		val, ok := row[attr]
+		if !ok {
+			return fmt.Errorf("could not select attribute %s", attr)
+		}
+		values = append(values, rowValue(val.v))
+	}
+	return f.conn.WriteRow(values)
+}
+
+// rowValue returns the text of a value written in a row, nil for NULL.
+func rowValue(v interface{}) *string {
+	if v == nil {
+		return nil
+	}
+	s := fmt.Sprintf("%v", v)
+	return &s
+}
+
+// Done writes the end of the rows.
+func (f *defaultSelectFunction) Done() error {
+	return f.conn.WriteRowEnd()
+}
+
+// countSelectFunction counts the selected rows.
+type countSelectFunction struct {
+	// conn is the connection the count is written to.
+	conn protocol.EngineConn
+	// attribute is the qualified attribute of COUNT(column), empty for COUNT(*).
+	attribute string
+	// count is the number of rows selected so far.
+	count int64
+}
+
+// Init writes the row header.
+func (f *countSelectFunction) Init(_ *Engine, conn protocol.EngineConn, _ []string, _ []string) error {
+	f.conn = conn
+	return f.conn.WriteRowHeader([]string{"count"})
+}
+
+// FeedVirtualRow counts the row, unless the counted attribute is NULL.
+func (f *countSelectFunction) FeedVirtualRow(row virtualRow) error {
+	if f.attribute != "" {
+		if v, ok := row[f.attribute]; !ok || v.v == nil {
+			return nil
+		}
+	}
+	f.count++
+	return nil
+}
+
+// Done writes the count and the end of the rows.
+func (f *countSelectFunction) Done() error {
+	if err := f.conn.WriteRow([]*string{rowValue(f.count)}); err != nil {
+		return err
+	}
+	return f.conn.WriteRowEnd()
+}
@@ -0,0 +1,323 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+func TestSelectExecutor(t *testing.T) {
+	t.Parallel()
+
+	e := newTestEngine(t)
+	addRelation(e, "users",
+		[]Attribute{NewAttribute("id", "int", true), NewAttribute("name", "text", false), NewAttribute("age", "int", false)},
+		[]interface{}{int64(1), "alice", int64(30)},
+		[]interface{}{int64(2), "bob", int64(25)},
+		[]interface{}{int64(3), "carol", int64(35)},
+		[]interface{}{int64(4), "bob", nil},
+	)
+	addRelation(e, "addresses",
+		[]Attribute{NewAttribute("user_id", "int", false), NewAttribute("city", "text", false)},
+		[]interface{}{int64(1), "tokyo"},
+		[]interface{}{int64(3), "osaka"},
+	)
+	addRelation(e, "visits",
+		[]Attribute{NewAttribute("user_id", "int", false), NewAttribute("count", "int", false)},
+		[]interface{}{int64(1), int64(3)},
+		[]interface{}{int64(2), int64(1)},
+	)
+
+	tests := []struct {
+		name       string
+		query      string
+		wantHeader []string
+		wantRows   [][]string
+		wantErr    bool
+	}{
+		{
+			name:       "select star",
+			query:      "SELECT * FROM users",
+			wantHeader: []string{"id", "name", "age"},
+			wantRows:   [][]string{{"1", "alice", "30"}, {"2", "bob", "25"}, {"3", "carol", "35"}, {"4", "bob", "NULL"}},
+		},
+		{
+			name:       "select columns with where",
+			query:      "SELECT name, users.age FROM users WHERE age > 26 AND name <> 'carol'",
+			wantHeader: []string{"name", "age"},
+			wantRows:   [][]string{{"alice", "30"}},
+		},
+		{
+			name:       "select with greater or equal and less or equal",
+			query:      "SELECT name FROM users WHERE age >= 30 AND age <= 34",
+			wantHeader: []string{"name"},
+			wantRows:   [][]string{{"alice"}},
+		},
+		{
+			name:       "select with in and is null",
+			query:      "SELECT id FROM users WHERE name IN ('bob', 'carol') AND age IS NOT NULL;",
+			wantHeader: []string{"id"},
+			wantRows:   [][]string{{"2"}, {"3"}},
+		},
+		{
+			name:       "count",
+			query:      "SELECT COUNT(*) FROM users WHERE name = 'bob'",
+			wantHeader: []string{"count"},
+			wantRows:   [][]string{{"2"}},
+		},
+		{
+			name:       "count of a column does not count NULL",
+			query:      "SELECT COUNT(age) FROM users WHERE name = 'bob'",
+			wantHeader: []string{"count"},
+			wantRows:   [][]string{{"1"}},
+		},
+		{
+			name:       "order by, limit and offset",
+			query:      "SELECT name FROM users ORDER BY age DESC OFFSET 1 LIMIT 2",
+			wantHeader: []string{"name"},
+			wantRows:   [][]string{{"carol"}, {"alice"}},
+		},
+		{
+			name:       "distinct",
+			query:      "SELECT DISTINCT name FROM users",
+			wantHeader: []string{"name"},
+			wantRows:   [][]string{{"alice"}, {"bob"}, {"carol"}},
+		},
+		{
+			name:       "join",
+			query:      "SELECT name, city FROM users JOIN addresses ON users.id = addresses.user_id",
+			wantHeader: []string{"name", "city"},
+			wantRows:   [][]string{{"alice", "tokyo"}, {"carol", "osaka"}},
+		},
+		{
+			name:       "column named count",
+			query:      "SELECT user_id, count FROM visits WHERE count = 1",
+			wantHeader: []string{"user_id", "count"},
+			wantRows:   [][]string{{"2", "1"}},
+		},
+		{
+			name:       "count of a column named count",
+			query:      "SELECT COUNT(count) FROM visits WHERE count > 2",
+			wantHeader: []string{"count"},
+			wantRows:   [][]string{{"1"}},
+		},
+		{
+			name:    "unknown table",
+			query:   "SELECT * FROM unknown",
+			wantErr: true,
+		},
+		{
+			name:    "OR is not supported",
+			query:   "SELECT name FROM users WHERE age > 30 OR name = 'bob'",
+			wantErr: true,
+		},
+		{
+			name:    "negative limit",
+			query:   "SELECT name FROM users LIMIT -1",
+			wantErr: true,
+		},
+		{
+			name:    "negative offset",
+			query:   "SELECT name FROM users OFFSET -1",
+			wantErr: true,
+		},
+		{
+			name:    "unknown attribute",
+			query:   "SELECT unknown FROM users",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := run(t, e, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.wantHeader, got.header); diff != "" {
+				t.Errorf("header is mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantRows, got.rows); diff != "" {
+				t.Errorf("rows are mismatch (-want +got):\n%s", diff)
+			}
+			if !got.ended {
+				t.Error("rows are not ended")
+			}
+		})
+	}
+}
+
+func TestSelectCollateNocase(t *testing.T) {
+	t.Parallel()
+
+	e := newTestEngine(t)
+	for _, query := range []string{
+		"CREATE TABLE [users] (id INTEGER PRIMARY KEY AUTOINCREMENT, name TEXT COLLATE NOCASE, code TEXT)",
+		"INSERT INTO users (name, code) VALUES ('bob', 'x'), ('Alice', 'Y'), ('carol', 'y'), ('ALICE', 'z')",
+	} {
+		if _, err := runWithMode(t, e, core.SQLSyntaxModeSQLite, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		wantRows [][]string
+		wantErr  bool
+	}{
+		{
+			name:     "equality on a NOCASE column",
+			query:    "SELECT id FROM users WHERE name = 'alice'",
+			wantRows: [][]string{{"2"}, {"4"}},
+		},
+		{
+			name:     "IN on a NOCASE column",
+			query:    "SELECT id FROM users WHERE name IN ('BOB', 'Carol')",
+			wantRows: [][]string{{"1"}, {"3"}},
+		},
+		{
+			name:     "equality on a BINARY column",
+			query:    "SELECT id FROM users WHERE code = 'y'",
+			wantRows: [][]string{{"3"}},
+		},
+		{
+			name:     "equality with COLLATE NOCASE",
+			query:    "SELECT id FROM users WHERE code = 'y' COLLATE NOCASE",
+			wantRows: [][]string{{"2"}, {"3"}},
+		},
+		{
+			name:     "ORDER BY a NOCASE column",
+			query:    "SELECT name FROM users ORDER BY name",
+			wantRows: [][]string{{"Alice"}, {"ALICE"}, {"bob"}, {"carol"}},
+		},
+		{
+			name:    "unbound parameter",
+			query:   "SELECT id FROM users WHERE name = :name",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := runWithMode(t, e, core.SQLSyntaxModeSQLite, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.wantRows, got.rows); diff != "" {
+				t.Errorf("rows are mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestSelectOracle(t *testing.T) {
+	t.Parallel()
+
+	e := newTestEngine(t)
+	for _, query := range []string{
+		"CREATE TABLE users (id NUMBER(10) PRIMARY KEY, name VARCHAR2(20 CHAR), nickname VARCHAR2(20), score NUMBER(10, 2))",
+		"INSERT INTO users (id, name, nickname) VALUES (3, 'carol', NULL)",
+		"INSERT INTO users (id, name, nickname) VALUES (1, 'alice', 'al')",
+		"INSERT INTO users (id, name, nickname) VALUES (2, 'bob', NULL)",
+	} {
+		if _, err := runWithMode(t, e, core.SQLSyntaxModeOracle, query); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name       string
+		query      string
+		wantHeader []string
+		wantRows   [][]string
+		wantErr    bool
+	}{
+		{
+			name:       "constant FROM DUAL",
+			query:      "SELECT 1 FROM DUAL",
+			wantHeader: []string{"1"},
+			wantRows:   [][]string{{"1"}},
+		},
+		{
+			name:       "DUAL columns",
+			query:      "SELECT * FROM dual",
+			wantHeader: []string{"dummy"},
+			wantRows:   [][]string{{"X"}},
+		},
+		{
+			name:       "ROWNUM filtering",
+			query:      "SELECT id FROM users WHERE ROWNUM <= 2",
+			wantHeader: []string{"id"},
+			wantRows:   [][]string{{"3"}, {"1"}},
+		},
+		{
+			name:       "ROWNUM with other predicates",
+			query:      "SELECT id FROM users WHERE id > 1 AND ROWNUM < 2",
+			wantHeader: []string{"id"},
+			wantRows:   [][]string{{"3"}},
+		},
+		{
+			name:       "FETCH FIRST n ROWS ONLY",
+			query:      "SELECT id FROM users ORDER BY id FETCH FIRST 2 ROWS ONLY",
+			wantHeader: []string{"id"},
+			wantRows:   [][]string{{"1"}, {"2"}},
+		},
+		{
+			name:       "OFFSET n ROWS FETCH NEXT n ROWS ONLY",
+			query:      "SELECT id FROM users ORDER BY id OFFSET 1 ROWS FETCH NEXT 1 ROW ONLY",
+			wantHeader: []string{"id"},
+			wantRows:   [][]string{{"2"}},
+		},
+		{
+			name:       "NVL",
+			query:      "SELECT id, NVL(nickname, 'none') FROM users ORDER BY id",
+			wantHeader: []string{"id", "nvl"},
+			wantRows:   [][]string{{"1", "al"}, {"2", "none"}, {"3", "none"}},
+		},
+		{
+			name:    "NVL with an unknown attribute",
+			query:   "SELECT NVL(nick, 'none') FROM users",
+			wantErr: true,
+		},
+		{
+			name:    "unbound bind variable",
+			query:   "SELECT id FROM users WHERE name = :name",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := runWithMode(t, e, core.SQLSyntaxModeOracle, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.wantHeader, got.header); diff != "" {
+				t.Errorf("header is mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantRows, got.rows); diff != "" {
+				t.Errorf("rows are mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
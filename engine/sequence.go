@@ -0,0 +1,112 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/nao1215/aiondb/engine/parser/core"
+	"github.com/nao1215/aiondb/engine/protocol"
+)
+
+// Sequence is a generator of integers created by CREATE SEQUENCE.
+// Its values are read through the NEXTVAL and CURRVAL pseudocolumns (e.g. seq.NEXTVAL).
+type Sequence struct {
+	// name is the name of the sequence
+	name string
+	// value is the last value returned by NEXTVAL, or the first value if NEXTVAL was never called
+	value int64
+	// increment is added to the value by each NEXTVAL
+	increment int64
+	// started is true once NEXTVAL has been called, CURRVAL is not defined before
+	started bool
+}
+
+// createSequenceExecutor executes a CREATE SEQUENCE statement.
+func createSequenceExecutor(e *Engine, stmt *core.CreateSequenceStmt, conn protocol.EngineConn) error {
+	s := &Sequence{
+		name:      stmt.Name,
+		value:     1,
+		increment: 1,
+	}
+	if stmt.Start != nil {
+		s.value = *stmt.Start
+	}
+	if stmt.Increment != nil {
+		if *stmt.Increment == 0 {
+			return errors.New("INCREMENT must not be zero")
+		}
+		s.increment = *stmt.Increment
+	}
+
+	e.Lock()
+	defer e.Unlock()
+	if _, ok := e.sequences[s.name]; ok {
+		return fmt.Errorf("sequence \"%s\" already exists", s.name)
+	}
+	e.sequences[s.name] = s
+	return conn.WriteResult(0, 1)
+}
+
+// dropSequence drops the sequence with the given name.
+func (e *Engine) dropSequence(name string) error {
+	e.Lock()
+	defer e.Unlock()
+	if _, ok := e.sequences[name]; !ok {
+		return fmt.Errorf("sequence '%s' not found", name)
+	}
+	delete(e.sequences, name)
+	return nil
+}
+
+// sequenceValue returns the value of a NEXTVAL or CURRVAL pseudocolumn.
+// NEXTVAL increments the sequence before returning its value.
+func (e *Engine) sequenceValue(v *core.SequenceValue) (int64, error) {
+	e.Lock()
+	defer e.Unlock()
+	s, ok := e.sequences[v.Sequence]
+	if !ok {
+		return 0, fmt.Errorf("sequence \"%s\" does not exist", v.Sequence)
+	}
+
+	if v.Next {
+		if s.started {
+			s.value += s.increment
+		}
+		s.started = true
+		return s.value, nil
+	}
+	if !s.started {
+		return 0, fmt.Errorf("sequence %s.CURRVAL is not yet defined, NEXTVAL must be called first", v.Sequence)
+	}
+	return s.value, nil
+}
+
+// sequenceValues returns the values with the NEXTVAL and CURRVAL pseudocolumns
+// replaced by the numbers they evaluate to.
+func (e *Engine) sequenceValues(values []core.Expr) ([]core.Expr, error) {
+	resolved := make([]core.Expr, 0, len(values))
+	for _, expr := range values {
+		v, ok := expr.(*core.SequenceValue)
+		if !ok {
+			resolved = append(resolved, expr)
+			continue
+		}
+		n, err := e.sequenceValue(v)
+		if err != nil {
+			return nil, err
+		}
+		resolved = append(resolved, &core.Literal{Kind: core.LiteralNumber, Value: strconv.FormatInt(n, 10), Number: core.NumberKindInteger})
+	}
+	return resolved, nil
+}
+
+// sequenceValueError returns the error for a NEXTVAL or CURRVAL pseudocolumn which
+// cannot be evaluated, because it is not in an INSERT or SELECT statement.
+func sequenceValueError(v *core.SequenceValue) error {
+	name := "CURRVAL"
+	if v.Next {
+		name = "NEXTVAL"
+	}
+	return fmt.Errorf("%s.%s is only allowed in INSERT values and SELECT lists", v.Sequence, name)
+}
@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+func TestSequence(t *testing.T) {
+	t.Parallel()
+
+	e := newTestEngine(t)
+	if _, err := runWithMode(t, e, core.SQLSyntaxModeOracle, "CREATE TABLE users (id NUMBER PRIMARY KEY, name VARCHAR2(20))"); err != nil {
+		t.Fatal(err)
+	}
+
+	// The steps depend on the state of the sequence, so they are not run in parallel
+	steps := []struct {
+		query    string
+		wantRows [][]string
+		wantErr  bool
+	}{
+		{query: "SELECT seq.NEXTVAL FROM DUAL", wantErr: true},
+		{query: "CREATE SEQUENCE seq START WITH 10 INCREMENT BY 5 NOCACHE"},
+		{query: "CREATE SEQUENCE seq", wantErr: true},
+		{query: "SELECT seq.CURRVAL FROM DUAL", wantErr: true},
+		{query: "INSERT INTO users (id, name) VALUES (seq.NEXTVAL, 'alice')"},
+		{query: "INSERT INTO users (id, name) VALUES (seq.NEXTVAL, 'bob')"},
+		{query: "SELECT id, name FROM users", wantRows: [][]string{{"10", "alice"}, {"15", "bob"}}},
+		{query: "SELECT seq.CURRVAL FROM DUAL", wantRows: [][]string{{"15"}}},
+		{query: "SELECT seq.NEXTVAL FROM DUAL", wantRows: [][]string{{"20"}}},
+		{query: "SELECT id FROM users WHERE id = seq.CURRVAL", wantErr: true},
+		{query: "CREATE SEQUENCE zero INCREMENT BY 0", wantErr: true},
+		{query: "DROP SEQUENCE seq"},
+		{query: "SELECT seq.NEXTVAL FROM DUAL", wantErr: true},
+		{query: "DROP SEQUENCE seq", wantErr: true},
+	}
+	for _, step := range steps {
+		got, err := runWithMode(t, e, core.SQLSyntaxModeOracle, step.query)
+		if (err != nil) != step.wantErr {
+			t.Fatalf("%s: unexpected error: %v", step.query, err)
+		}
+		if step.wantErr || step.wantRows == nil {
+			continue
+		}
+		if diff := cmp.Diff(step.wantRows, got.rows); diff != "" {
+			t.Errorf("%s: rows are mismatch (-want +got):\n%s", step.query, diff)
+		}
+	}
+}
@@ -1,10 +1,20 @@
 package engine
 
+import (
+	"fmt"
+
+	"github.com/nao1215/aiondb/engine/parser/core"
+	"github.com/nao1215/aiondb/engine/protocol"
+)
+
 // Table is defined by a name and attributes
 // A table with data is called a Relation
 type Table struct {
 	name       string
 	attributes []Attribute
+	// primaryKey is the list of attributes of a composite primary key.
+	// A single attribute primary key is flagged on the attribute itself.
+	primaryKey []string
 }
 
 // NewTable initializes a new Table
@@ -14,3 +24,80 @@ func NewTable(name string) *Table {
 	}
 	return t
 }
+
+// AddAttribute adds an attribute to the table.
+func (t *Table) AddAttribute(attr Attribute) error {
+	if t.attributeIndex(attr.name) >= 0 {
+		return fmt.Errorf("column \"%s\" specified more than once", attr.name)
+	}
+	t.attributes = append(t.attributes, attr)
+	return nil
+}
+
+// attributeIndex returns the index of the attribute, or -1 if it does not exist.
+func (t *Table) attributeIndex(name string) int {
+	for i := range t.attributes {
+		if t.attributes[i].name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// setPrimaryKey sets the primary key of the table from a 'primary key (column, ...)' constraint.
+func (t *Table) setPrimaryKey(columns []string) error {
+	for _, c := range columns {
+		i := t.attributeIndex(c)
+		if i < 0 {
+			return fmt.Errorf("column \"%s\" named in key does not exist", c)
+		}
+		t.attributes[i].notNull = true
+	}
+
+	if len(columns) == 1 {
+		i := t.attributeIndex(columns[0])
+		t.attributes[i].primaryKey = true
+		t.attributes[i].unique = true
+		return nil
+	}
+	t.primaryKey = columns
+	return nil
+}
+
+// createTableExecutor executes a CREATE TABLE statement.
+func createTableExecutor(e *Engine, stmt *core.CreateTableStmt, conn protocol.EngineConn) error {
+	t := NewTable(stmt.Table.Name)
+	for _, column := range stmt.Columns {
+		attr, err := newAttribute(column)
+		if err != nil {
+			return err
+		}
+		if err := t.AddAttribute(attr); err != nil {
+			return err
+		}
+	}
+
+	if stmt.PrimaryKey != nil {
+		for _, attr := range t.attributes {
+			if attr.primaryKey {
+				return fmt.Errorf("multiple primary keys for table \"%s\" are not allowed", t.name)
+			}
+		}
+		if err := t.setPrimaryKey(stmt.PrimaryKey); err != nil {
+			return err
+		}
+	}
+
+	e.Lock()
+	if _, ok := e.relations[t.name]; ok {
+		e.Unlock()
+		if stmt.IfNotExists {
+			return conn.WriteResult(0, 0)
+		}
+		return fmt.Errorf("relation \"%s\" already exists", t.name)
+	}
+	e.relations[t.name] = NewRelation(t)
+	e.Unlock()
+
+	return conn.WriteResult(0, 1)
+}
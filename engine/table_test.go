@@ -0,0 +1,113 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestCreateTableExecutor(t *testing.T) {
+	t.Parallel()
+
+	t.Run("build typed schema", func(t *testing.T) {
+		t.Parallel()
+
+		e := newTestEngine(t)
+		query := `CREATE TABLE IF NOT EXISTS "users" (
+			id BIGSERIAL PRIMARY KEY,
+			name varchar(255) NOT NULL UNIQUE,
+			score numeric DEFAULT 1.5,
+			created_at timestamp WITH TIME ZONE DEFAULT now(),
+			active bool DEFAULT false
+		)`
+		got, err := run(t, e, query)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.rowsAffected != 1 {
+			t.Errorf("mismatch rows affected: want=1, got=%d", got.rowsAffected)
+		}
+
+		r := e.relation("users")
+		if r == nil {
+			t.Fatal("relation users is not registered")
+		}
+		type column struct {
+			name, typeName                             string
+			defaultValue                               interface{}
+			autoIncrement, unique, notNull, primaryKey bool
+		}
+		want := []column{
+			{name: "id", typeName: "BIGSERIAL", autoIncrement: true, unique: true, notNull: true, primaryKey: true},
+			{name: "name", typeName: "varchar(255)", unique: true, notNull: true},
+			{name: "score", typeName: "numeric", defaultValue: 1.5},
+			{name: "created_at", typeName: "timestamp with time zone", defaultValue: "now()"},
+			{name: "active", typeName: "bool", defaultValue: "false"},
+		}
+		columns := []column{}
+		for _, a := range r.table.attributes {
+			c := column{a.name, a.typeName, a.defaultValue, a.autoIncrement, a.unique, a.notNull, a.primaryKey}
+			if _, ok := a.defaultValue.(func() interface{}); ok {
+				c.defaultValue = "now()"
+			}
+			columns = append(columns, c)
+		}
+		if diff := cmp.Diff(want, columns, cmp.AllowUnexported(column{})); diff != "" {
+			t.Errorf("attributes are mismatch (-want +got):\n%s", diff)
+		}
+
+		// IF NOT EXISTS
+		if _, err := run(t, e, query); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := run(t, e, "CREATE TABLE users (id int)"); err == nil {
+			t.Error("expect error, however creating an existing table succeeded")
+		}
+	})
+
+	t.Run("honour constraints on insert", func(t *testing.T) {
+		t.Parallel()
+
+		e := newTestEngine(t)
+		if _, err := run(t, e, `CREATE TABLE users (id serial, email text UNIQUE, name text NOT NULL, age int DEFAULT 20);
+			CREATE TABLE members (team int, user_id int, PRIMARY KEY (team, user_id))`); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := run(t, e, "INSERT INTO users (email, name) VALUES ('a@example.com', 'a'), ('b@example.com', 'b') RETURNING id")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([][]string{{"1"}, {"2"}}, got.rows); diff != "" {
+			t.Errorf("returned ids are mismatch (-want +got):\n%s", diff)
+		}
+
+		got, err = run(t, e, "INSERT INTO users (email, name, age) VALUES (NULL, 'c', DEFAULT)")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.lastInsertedID != 3 {
+			t.Errorf("mismatch last inserted id: want=3, got=%d", got.lastInsertedID)
+		}
+
+		got, err = run(t, e, "SELECT id, email, age FROM users WHERE email IS NULL")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff([][]string{{"3", "NULL", "20"}}, got.rows); diff != "" {
+			t.Errorf("rows are mismatch (-want +got):\n%s", diff)
+		}
+
+		for _, query := range []string{
+			"INSERT INTO users (email, name) VALUES ('a@example.com', 'd')",
+			"INSERT INTO users (email) VALUES ('d@example.com')",
+			"INSERT INTO users (email, age) VALUES ('d@example.com', 'abc')",
+			"INSERT INTO users (email, name) VALUES ('d@example.com')",
+			"INSERT INTO members (team, user_id) VALUES (1, 1), (1, 1)",
+		} {
+			if _, err := run(t, e, query); err == nil {
+				t.Errorf("expect error, however %s succeeded", query)
+			}
+		}
+	})
+}
@@ -8,10 +8,8 @@ import (
 )
 
 // truncateExecutor executes truncate statement
-func truncateExecutor(e *Engine, trDecl *core.Decl, conn protocol.EngineConn) error {
-	// get tables to be deleted
-	table := NewTable(trDecl.DeclList[0].Lexeme.String())
-	return truncateTable(e, table, conn)
+func truncateExecutor(e *Engine, stmt *core.TruncateStmt, conn protocol.EngineConn) error {
+	return truncateTable(e, NewTable(stmt.Table.Name), conn)
 }
 
 // truncateTable truncates table
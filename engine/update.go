@@ -0,0 +1,163 @@
+package engine
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/nao1215/aiondb/engine/parser/core"
+	"github.com/nao1215/aiondb/engine/protocol"
+)
+
+// assignment is a value assigned to an attribute by the SET clause of an UPDATE statement.
+type assignment struct {
+	// index is the index of the attribute in the table.
+	index int
+	// value is the assigned value.
+	value core.Expr
+}
+
+// updateExecutor executes an UPDATE statement.
+func updateExecutor(e *Engine, stmt *core.UpdateStmt, conn protocol.EngineConn) error {
+	tableName := stmt.Table.Name
+	r := e.relation(tableName)
+	if r == nil {
+		return fmt.Errorf("table %s does not exist", tableName)
+	}
+
+	assignments, err := setExecutor(r.table, stmt.Set)
+	if err != nil {
+		return err
+	}
+
+	// Without WHERE clause, all rows are updated
+	predicates, err := whereExecutor(stmt.Where, tableName)
+	if err != nil {
+		return err
+	}
+
+	r.Lock()
+	defer r.Unlock()
+
+	rowsUpdated, err := updateRows(r, assignments, predicates)
+	if err != nil {
+		return err
+	}
+	return conn.WriteResult(0, rowsUpdated)
+}
+
+// setExecutor returns the assignments of a SET or ON DUPLICATE KEY UPDATE clause.
+func setExecutor(t *Table, set []*core.Assignment) ([]assignment, error) {
+	assignments := make([]assignment, 0, len(set))
+	for _, a := range set {
+		i := t.attributeIndex(a.Column)
+		if i < 0 {
+			return nil, fmt.Errorf("attribute %s does not exist in table %s", a.Column, t.name)
+		}
+		assignments = append(assignments, assignment{index: i, value: a.Value})
+	}
+	return assignments, nil
+}
+
+// updateRows assigns new values to the rows validating all predicates and
+// returns the number of updated rows. The relation must be write locked.
+// Rows are updated atomically: if a constraint is violated, no row is updated.
+func updateRows(r *Relation, assignments []assignment, predicates []Predicate) (int64, error) {
+	updated := make(map[int]*Tuple)
+	for i, row := range r.rows {
+		ok, err := matchTuple(r, row, predicates)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			continue
+		}
+
+		t, err := updateTuple(r, row, assignments, nil)
+		if err != nil {
+			return 0, err
+		}
+		updated[i] = t
+	}
+
+	// Replace rows, then check constraints against the updated relation
+	old := make(map[int]*Tuple, len(updated))
+	for i, t := range updated {
+		old[i] = r.rows[i]
+		r.rows[i] = t
+	}
+	for _, t := range updated {
+		if err := checkTuple(r, t); err != nil {
+			for i, row := range old {
+				r.rows[i] = row
+			}
+			return 0, err
+		}
+	}
+
+	// Explicit values of auto-incremented attributes bump the sequence, like insert
+	for _, t := range updated {
+		bumpSequence(r, t)
+	}
+	return int64(len(updated)), nil
+}
+
+// bumpSequence bumps the sequence of the relation to the explicit value of the
+// auto-incremented attribute of the tuple, if it is greater, like insert.
+func bumpSequence(r *Relation, t *Tuple) {
+	for i, attr := range r.table.attributes {
+		if v, ok := t.Values[i].(int64); ok && attr.autoIncrement && v > r.sequence {
+			r.sequence = v
+		}
+	}
+}
+
+// updateTuple returns a copy of the tuple with the assigned values.
+// VALUES(column) is the value of the column in inserted, the tuple an
+// INSERT ... ON DUPLICATE KEY UPDATE statement failed to insert.
+func updateTuple(r *Relation, row *Tuple, assignments []assignment, inserted *Tuple) (*Tuple, error) {
+	t := NewTuple(row.Values...)
+	for _, a := range assignments {
+		attr := r.table.attributes[a.index]
+		if f, ok := a.value.(*core.FuncCall); ok && f.Name == "values" {
+			if inserted == nil || len(f.Args) != 1 {
+				return nil, errors.New("VALUES() is only allowed in the ON DUPLICATE KEY UPDATE clause")
+			}
+			column, ok := f.Args[0].(*core.ColumnRef)
+			if !ok {
+				return nil, errors.New("VALUES() expects an attribute")
+			}
+			i := r.table.attributeIndex(column.Name)
+			if i < 0 {
+				return nil, fmt.Errorf("attribute %s does not exist in table %s", column.Name, r.table.name)
+			}
+			t.Values[a.index] = inserted.Values[i]
+			continue
+		}
+		if isDefault(a.value) {
+			switch val := attr.defaultValue.(type) {
+			case func() interface{}:
+				t.Values[a.index] = val()
+			default:
+				t.Values[a.index] = attr.defaultValue
+			}
+			continue
+		}
+
+		value, err := attributeValue(attr, a.value)
+		if err != nil {
+			return nil, err
+		}
+		t.Values[a.index] = value
+	}
+	return t, nil
+}
+
+// checkTuple checks the constraints of all attributes of a tuple already in the relation.
+func checkTuple(r *Relation, t *Tuple) error {
+	for i, attr := range r.table.attributes {
+		if err := checkConstraints(r, attr, t.Values[i], t); err != nil {
+			return err
+		}
+	}
+	return checkPrimaryKey(r, t, t)
+}
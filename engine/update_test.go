@@ -0,0 +1,161 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestUpdateExecutor(t *testing.T) {
+	t.Parallel()
+
+	setup := func(t *testing.T) *Engine {
+		t.Helper()
+
+		e := newTestEngine(t)
+		for _, query := range []string{
+			"CREATE TABLE users (id SERIAL PRIMARY KEY, email TEXT UNIQUE, name TEXT NOT NULL, age INT DEFAULT 20, score NUMERIC)",
+			"INSERT INTO users (email, name, age) VALUES ('a@example.com', 'alice', 30), ('b@example.com', 'bob', 25), ('c@example.com', 'carol', 35)",
+		} {
+			if _, err := run(t, e, query); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return e
+	}
+
+	tests := []struct {
+		name         string
+		query        string
+		rowsAffected int64
+		want         [][]string
+		wantErr      bool
+	}{
+		{
+			name:         "update rows matching the WHERE clause",
+			query:        "UPDATE users SET name = 'robert', age = 26, score = 1.5 WHERE id = 2",
+			rowsAffected: 1,
+			want: [][]string{
+				{"1", "alice", "30", "NULL"},
+				{"2", "robert", "26", "1.5"},
+				{"3", "carol", "35", "NULL"},
+			},
+		},
+		{
+			name:         "update all rows without WHERE clause",
+			query:        "UPDATE users SET age = DEFAULT",
+			rowsAffected: 3,
+			want: [][]string{
+				{"1", "alice", "20", "NULL"},
+				{"2", "bob", "20", "NULL"},
+				{"3", "carol", "20", "NULL"},
+			},
+		},
+		{
+			name:         "update with several predicates",
+			query:        "UPDATE users SET score = NULL, age = 40 WHERE age > 26 AND name <> 'carol'",
+			rowsAffected: 1,
+			want: [][]string{
+				{"1", "alice", "40", "NULL"},
+				{"2", "bob", "25", "NULL"},
+				{"3", "carol", "35", "NULL"},
+			},
+		},
+		{
+			name:         "no row matches",
+			query:        "UPDATE users SET age = 1 WHERE name = 'dave'",
+			rowsAffected: 0,
+			want: [][]string{
+				{"1", "alice", "30", "NULL"},
+				{"2", "bob", "25", "NULL"},
+				{"3", "carol", "35", "NULL"},
+			},
+		},
+		{
+			name:         "NULL does not match a comparison",
+			query:        "UPDATE users SET age = 1 WHERE score <> 1.5",
+			rowsAffected: 0,
+			want: [][]string{
+				{"1", "alice", "30", "NULL"},
+				{"2", "bob", "25", "NULL"},
+				{"3", "carol", "35", "NULL"},
+			},
+		},
+		{
+			name:         "update rows matching a qualified attribute",
+			query:        "UPDATE users SET age = 1 WHERE users.score IS NULL AND users.id = 3",
+			rowsAffected: 1,
+			want: [][]string{
+				{"1", "alice", "30", "NULL"},
+				{"2", "bob", "25", "NULL"},
+				{"3", "carol", "1", "NULL"},
+			},
+		},
+		{
+			name:    "attribute of another table",
+			query:   "UPDATE users SET age = 1 WHERE orders.id = 2",
+			wantErr: true,
+		},
+		{
+			name:    "unique constraint violation",
+			query:   "UPDATE users SET email = 'a@example.com' WHERE id = 2",
+			wantErr: true,
+		},
+		{
+			name:    "unique constraint violation between updated rows",
+			query:   "UPDATE users SET email = 'x@example.com' WHERE age > 26",
+			wantErr: true,
+		},
+		{
+			name:    "not null constraint violation",
+			query:   "UPDATE users SET name = NULL WHERE id = 1",
+			wantErr: true,
+		},
+		{
+			name:    "invalid integer",
+			query:   "UPDATE users SET age = 'abc'",
+			wantErr: true,
+		},
+		{
+			name:    "unknown attribute",
+			query:   "UPDATE users SET nickname = 'a'",
+			wantErr: true,
+		},
+		{
+			name:    "unknown table",
+			query:   "UPDATE customers SET name = 'a'",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			e := setup(t)
+			got, err := run(t, e, tt.query)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			rows, err := run(t, e, "SELECT id, name, age, score FROM users ORDER BY id")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr {
+				// Rows are updated atomically
+				tt.want = [][]string{
+					{"1", "alice", "30", "NULL"},
+					{"2", "bob", "25", "NULL"},
+					{"3", "carol", "35", "NULL"},
+				}
+			} else if got.rowsAffected != tt.rowsAffected {
+				t.Errorf("mismatch rows affected: want=%d, got=%d", tt.rowsAffected, got.rowsAffected)
+			}
+			if diff := cmp.Diff(tt.want, rows.rows); diff != "" {
+				t.Errorf("rows are mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
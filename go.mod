@@ -5,6 +5,7 @@ go 1.20
 require (
 	github.com/charmbracelet/log v0.2.1
 	github.com/google/go-cmp v0.5.9
+	github.com/mattn/go-runewidth v0.0.14
 	github.com/spf13/cobra v1.7.0
 )
 
@@ -15,7 +16,6 @@ require (
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-isatty v0.0.18 // indirect
-	github.com/mattn/go-runewidth v0.0.14 // indirect
 	github.com/muesli/reflow v0.3.0 // indirect
 	github.com/muesli/termenv v0.15.1 // indirect
 	github.com/rivo/uniseg v0.2.0 // indirect
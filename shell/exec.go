@@ -0,0 +1,149 @@
+package shell
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/nao1215/aiondb/engine"
+	"github.com/nao1215/aiondb/engine/parser"
+	"github.com/nao1215/aiondb/engine/parser/core"
+	"github.com/nao1215/aiondb/engine/protocol"
+)
+
+// Script is a named SQL script run by Exec, e.g. the content of a file.
+type Script struct {
+	// Name is the name of the script reported in errors (e.g. a file path).
+	Name string
+	// SQL is the content of the script.
+	SQL string
+}
+
+// ExecError is an error of a statement run by Exec or formatted by FormatScript.
+type ExecError struct {
+	// Script is the name of the script of the statement.
+	Script string
+	// Statement is the number of the statement in the script, starting at 1.
+	Statement int
+	// Line is the line of the statement in the script, or of the unexpected token
+	// for syntax errors, starting at 1.
+	Line int
+	// Column is the column of the statement in the script, or of the unexpected token
+	// for syntax errors, starting at 1.
+	Column int
+	// Err is the error returned by the parser or the engine.
+	Err error
+}
+
+// Error returns the error prefixed by its position, e.g. "schema.sql:12:1: statement 3: ...".
+func (e *ExecError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: statement %d: %s", e.Script, e.Line, e.Column, e.Statement, e.Err)
+}
+
+// Unwrap returns the error returned by the parser or the engine.
+func (e *ExecError) Unwrap() error {
+	return e.Err
+}
+
+// Exec runs the statements of the scripts in order on a fresh in-memory engine
+// and writes the command tags and result sets to out in the given format.
+// Each statement is parsed with parser.Parser.Parse before being executed.
+// It stops at the first failing statement and returns an *ExecError.
+func Exec(scripts []Script, out io.Writer, format Format) error {
+	_, endpoint := protocol.NewChannelEndpoints()
+	e, err := engine.New(endpoint)
+	if err != nil {
+		return err
+	}
+	defer e.Stop()
+
+	p := parser.NewParser(core.SQLSyntaxModePostgreSQL)
+	conn := &execConn{
+		out:      out,
+		renderer: NewRenderer(out, format),
+	}
+
+	for _, script := range scripts {
+		for i, stmt := range splitStatements(script.SQL) {
+			conn.command = protocol.Command(stmt.text)
+			conn.err = nil
+
+			stmts, err := p.Parse(stmt.text)
+			if err == nil {
+				err = e.Execute(stmts, conn)
+			}
+			if err == nil {
+				err = conn.err
+			}
+			if err != nil {
+				return newExecError(script, i, stmt, err)
+			}
+		}
+	}
+	return nil
+}
+
+// newExecError returns the error of the i-th statement of a script. A syntax error
+// is reported at the position of the unexpected token instead of the statement,
+// and its position is moved from the statement to the script.
+func newExecError(script Script, i int, stmt statement, err error) *ExecError {
+	offset := stmt.offset
+	var syntaxErr *core.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		offset += syntaxErr.Pos
+	}
+	line, col := lineColumn(script.SQL, offset)
+	if syntaxErr != nil {
+		syntaxErr.Pos, syntaxErr.Line, syntaxErr.Col = offset, line, col
+	}
+	return &ExecError{
+		Script:    script.Name,
+		Statement: i + 1,
+		Line:      line,
+		Column:    col,
+		Err:       err,
+	}
+}
+
+// execConn is the protocol.EngineConn statements run by Exec write their results to.
+type execConn struct {
+	// out is the output command tags are written to.
+	out io.Writer
+	// renderer writes the result sets.
+	renderer *Renderer
+	// command is the command of the running statement (e.g. "INSERT").
+	command string
+	// err is the error written by the engine for the running statement.
+	err error
+}
+
+// ReadStatement is not used, statements are executed with Engine.Execute.
+func (c *execConn) ReadStatement() (string, error) {
+	return "", io.EOF
+}
+
+// WriteResult writes the command tag of the statement.
+func (c *execConn) WriteResult(_ int64, rowsAffected int64) error {
+	return writeCommandTag(c.out, c.command, rowsAffected)
+}
+
+// WriteError records the error of the statement.
+func (c *execConn) WriteError(err error) error {
+	c.err = err
+	return nil
+}
+
+// WriteRowHeader starts a new result set.
+func (c *execConn) WriteRowHeader(header []string) error {
+	return c.renderer.WriteRowHeader(header)
+}
+
+// WriteRow appends a row to the result set.
+func (c *execConn) WriteRow(row []*string) error {
+	return c.renderer.WriteRow(row)
+}
+
+// WriteRowEnd writes the result set in the output format.
+func (c *execConn) WriteRowEnd() error {
+	return c.renderer.WriteRowEnd()
+}
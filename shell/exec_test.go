@@ -0,0 +1,121 @@
+package shell
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestExec(t *testing.T) {
+	t.Parallel()
+
+	schema := Script{
+		Name: "schema.sql",
+		SQL: `-- users of the application
+CREATE TABLE users (
+  id SERIAL PRIMARY KEY,
+  email TEXT UNIQUE
+);
+INSERT INTO users (email) VALUES ('alice@example.com');`,
+	}
+
+	tests := []struct {
+		name    string
+		scripts []Script
+		want    string
+		wantErr *ExecError
+		wantMsg string
+	}{
+		{
+			name: "run scripts in order",
+			scripts: []Script{
+				schema,
+				{Name: "command 1", SQL: "UPDATE users SET email = 'bob@example.com'; SELECT id, email FROM users"},
+			},
+			want: `CREATE TABLE
+INSERT 0 1
+UPDATE 1
+[
+  {"id":"1","email":"bob@example.com"}
+]
+`,
+		},
+		{
+			name: "run statements with comments and escaped quotes",
+			scripts: []Script{
+				schema,
+				{Name: "seed.sql", SQL: "INSERT INTO users (email) VALUES ('o''brien@example.com'); -- it's a comment\n" +
+					"SELECT email /* nested /* comment */ */ FROM users WHERE email = E'o\\'brien@example.com'"},
+			},
+			want: `CREATE TABLE
+INSERT 0 1
+INSERT 0 1
+[
+  {"email":"o'brien@example.com"}
+]
+`,
+		},
+		{
+			name: "report the position of a failing statement",
+			scripts: []Script{
+				schema,
+				{Name: "seed.sql", SQL: "INSERT INTO users (email) VALUES ('bob@example.com');\n\n  INSERT INTO users (email) VALUES ('alice@example.com');\nSELECT * FROM users;"},
+			},
+			want: "CREATE TABLE\nINSERT 0 1\nINSERT 0 1\n",
+			wantErr: &ExecError{
+				Script:    "seed.sql",
+				Statement: 2,
+				Line:      3,
+				Column:    3,
+			},
+		},
+		{
+			name: "report syntax errors",
+			scripts: []Script{
+				{Name: "command 1", SQL: "CREATE TABLE users (id SERIAL);\nSELECT * FORM users"},
+			},
+			want: "CREATE TABLE\n",
+			wantErr: &ExecError{
+				Script:    "command 1",
+				Statement: 2,
+				Line:      2,
+				Column:    10,
+			},
+			wantMsg: `command 1:2:10: statement 2: syntax error at line 2, column 10 near "FORM", expected "FROM"`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+			err := Exec(tt.scripts, &out, FormatJSON)
+			if tt.wantErr == nil && err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantErr != nil {
+				var got *ExecError
+				if !errors.As(err, &got) {
+					t.Fatalf("mismatch error: want=*ExecError, got=%v", err)
+				}
+				if got.Err == nil {
+					t.Error("ExecError has no cause")
+				}
+				if tt.wantMsg != "" && got.Error() != tt.wantMsg {
+					t.Errorf("mismatch error message: want=%q, got=%q", tt.wantMsg, got.Error())
+				}
+				got.Err = nil
+				if diff := cmp.Diff(tt.wantErr, got); diff != "" {
+					t.Errorf("error is mismatch (-want +got):\n%s", diff)
+				}
+			}
+			if diff := cmp.Diff(tt.want, out.String()); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
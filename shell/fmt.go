@@ -0,0 +1,94 @@
+package shell
+
+import (
+	"strings"
+
+	"github.com/nao1215/aiondb/engine/parser"
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+// fmtIndent is the indentation of the statements formatted by FormatScript.
+const fmtIndent = "    "
+
+// FormatScript formats the statements of a script in the SQL syntax mode with
+// core.FormatIndent. Each statement is terminated by a semicolon and separated from
+// the next one by an empty line. The comments between statements are kept, while the
+// statements containing comments are kept as is, since the formatter would drop them.
+// It returns an *ExecError if a statement cannot be parsed or formatted.
+func FormatScript(script Script, mode core.SQLSyntaxMode) (string, error) {
+	p := parser.NewParser(mode)
+	b := strings.Builder{}
+	prev := 0
+
+	stmts := splitStatements(script.SQL)
+	for i, stmt := range stmts {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(comments(script.SQL[prev:stmt.offset]))
+		prev = stmt.end
+
+		text := stmt.text
+		if !stmt.comment {
+			formatted, err := formatStatement(p, text, mode)
+			if err != nil {
+				return "", newExecError(script, i, stmt, err)
+			}
+			text = formatted
+		}
+		b.WriteString(text)
+		b.WriteString(";\n")
+	}
+
+	if trailing := comments(script.SQL[prev:]); trailing != "" {
+		if len(stmts) > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(trailing)
+	}
+	return b.String(), nil
+}
+
+// formatStatement parses a statement and returns its formatted SQL text.
+func formatStatement(p parser.Parser, text string, mode core.SQLSyntaxMode) (string, error) {
+	stmts, err := p.Parse(text)
+	if err != nil {
+		return "", err
+	}
+	formatted := make([]string, 0, len(stmts))
+	for _, stmt := range stmts {
+		s, err := core.FormatIndent(stmt, mode, fmtIndent)
+		if err != nil {
+			return "", err
+		}
+		formatted = append(formatted, s)
+	}
+	return strings.Join(formatted, ";\n\n"), nil
+}
+
+// comments returns the comments found in the text between two statements, each one
+// followed by a new line. Spaces and empty statements are dropped.
+func comments(text string) string {
+	b := strings.Builder{}
+	for i := 0; i < len(text); i++ {
+		var end int
+		switch {
+		case strings.HasPrefix(text[i:], "--"):
+			end = strings.IndexByte(text[i:], '\n')
+		case strings.HasPrefix(text[i:], "/*"):
+			end = strings.Index(text[i:], "*/")
+			if end >= 0 {
+				end += len("*/")
+			}
+		default:
+			continue
+		}
+		if end < 0 {
+			end = len(text) - i
+		}
+		b.WriteString(strings.TrimSpace(text[i : i+end]))
+		b.WriteString("\n")
+		i += end
+	}
+	return b.String()
+}
@@ -0,0 +1,114 @@
+package shell
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/nao1215/aiondb/engine/parser/core"
+)
+
+func TestFormatScript(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		mode    core.SQLSyntaxMode
+		sql     string
+		want    string
+		wantErr *ExecError
+	}{
+		{
+			name: "format statements and keep the comments between them",
+			mode: core.SQLSyntaxModePostgreSQL,
+			sql: `-- users of the application
+create table users (id serial primary key, name varchar(20) not null);;
+/* seed */ insert into users (name) values ('alice'), ('bob');
+select id, users.name from users join groups on users.gid = groups.id where id > 1 and name = 'alice' order by id limit 3
+-- end of the script
+`,
+			want: `-- users of the application
+CREATE TABLE users (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(20) NOT NULL
+);
+
+/* seed */
+INSERT INTO users (name)
+VALUES
+    ('alice'),
+    ('bob');
+
+SELECT
+    id,
+    users.name
+FROM users
+JOIN groups ON users.gid = groups.id
+WHERE id > 1
+    AND name = 'alice'
+ORDER BY id
+LIMIT 3;
+
+-- end of the script
+`,
+		},
+		{
+			name: "keep statements containing comments as they are",
+			mode: core.SQLSyntaxModePostgreSQL,
+			sql:  "update users set name = 'bob' where id = 1; SELECT /* all */ * FROM users",
+			want: "UPDATE users\nSET\n    name = 'bob'\nWHERE id = 1;\n\nSELECT /* all */ * FROM users;\n",
+		},
+		{
+			name: "format in the syntax of the dialect",
+			mode: core.SQLSyntaxModeMySQL,
+			sql:  "insert into `order` (id) values (1) on duplicate key update id = 2",
+			want: "INSERT INTO `order` (id)\nVALUES\n    (1)\nON DUPLICATE KEY UPDATE\n    id = 2;\n",
+		},
+		{
+			name: "report syntax errors",
+			mode: core.SQLSyntaxModePostgreSQL,
+			sql:  "SELECT * FROM users;\nSELECT * FORM users",
+			wantErr: &ExecError{
+				Script:    "schema.sql",
+				Statement: 2,
+				Line:      2,
+				Column:    10,
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			got, err := FormatScript(Script{Name: "schema.sql", SQL: tt.sql}, tt.mode)
+			if tt.wantErr != nil {
+				var gotErr *ExecError
+				if !errors.As(err, &gotErr) {
+					t.Fatalf("mismatch error: want=*ExecError, got=%v", err)
+				}
+				gotErr.Err = nil
+				if diff := cmp.Diff(tt.wantErr, gotErr); diff != "" {
+					t.Errorf("error is mismatch (-want +got):\n%s", diff)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+
+			// Formatting is idempotent
+			again, err := FormatScript(Script{Name: "schema.sql", SQL: got}, tt.mode)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, again); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,301 @@
+package shell
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+	"github.com/nao1215/aiondb/engine/protocol"
+)
+
+// Format is an output format of result sets.
+type Format string
+
+const (
+	// FormatTable writes result sets as aligned tables followed by the row count, like psql.
+	FormatTable Format = "table"
+	// FormatCSV writes result sets as CSV with a header line.
+	FormatCSV Format = "csv"
+	// FormatJSON writes result sets as JSON arrays of objects.
+	FormatJSON Format = "json"
+	// FormatMarkdown writes result sets as markdown tables.
+	FormatMarkdown Format = "markdown"
+)
+
+// Formats is the list of supported output formats.
+func Formats() []Format {
+	return []Format{FormatTable, FormatCSV, FormatJSON, FormatMarkdown}
+}
+
+// ParseFormat returns the output format with the given name.
+// Like psql, "aligned" is an alias of "table".
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(name)); f {
+	case FormatTable, FormatCSV, FormatJSON, FormatMarkdown:
+		return f, nil
+	case "aligned":
+		return FormatTable, nil
+	default:
+		names := make([]string, 0, len(Formats()))
+		for _, f := range Formats() {
+			names = append(names, string(f))
+		}
+		return "", fmt.Errorf("unknown output format \"%s\", allowed formats are %s", name, strings.Join(names, ", "))
+	}
+}
+
+// Renderer writes the result sets sent by the engine in an output format.
+// It implements the row writing methods of protocol.EngineConn, so that
+// connections printing result sets (shell, exec) only forward them.
+type Renderer struct {
+	// out is the output result sets are written to.
+	out io.Writer
+	// format is the output format.
+	format Format
+	// header is the header of the result set being read.
+	header []string
+	// rows is the rows of the result set being read, NULL values are nil.
+	rows [][]*string
+}
+
+// NewRenderer returns a new Renderer writing result sets to out in the given format.
+func NewRenderer(out io.Writer, format Format) *Renderer {
+	return &Renderer{
+		out:    out,
+		format: format,
+	}
+}
+
+// Format returns the output format.
+func (r *Renderer) Format() Format {
+	return r.format
+}
+
+// SetFormat changes the output format of the next result sets.
+func (r *Renderer) SetFormat(format Format) {
+	r.format = format
+}
+
+// WriteRowHeader starts a new result set.
+func (r *Renderer) WriteRowHeader(header []string) error {
+	r.header = header
+	r.rows = nil
+	return nil
+}
+
+// WriteRow appends a row to the result set.
+// Rows are buffered until the end of the result set, the width of columns depends on all rows.
+func (r *Renderer) WriteRow(row []*string) error {
+	r.rows = append(r.rows, row)
+	return nil
+}
+
+// WriteRowEnd writes the result set.
+func (r *Renderer) WriteRowEnd() error {
+	return r.Render(r.header, r.rows)
+}
+
+// Render writes a result set in the output format. NULL values of the rows are nil.
+func (r *Renderer) Render(header []string, rows [][]*string) error {
+	switch r.format {
+	case FormatCSV:
+		return writeCSV(r.out, header, rows)
+	case FormatJSON:
+		return writeJSON(r.out, header, rows)
+	case FormatMarkdown:
+		if err := writeMarkdown(r.out, header, rows); err != nil {
+			return err
+		}
+		_, err := io.WriteString(r.out, "\n")
+		return err
+	default:
+		if err := writeTable(r.out, header, rows); err != nil {
+			return err
+		}
+		return writeRowCount(r.out, len(rows))
+	}
+}
+
+// texts returns a row of values which are not NULL.
+func texts(values ...string) []*string {
+	row := make([]*string, 0, len(values))
+	for i := range values {
+		row = append(row, &values[i])
+	}
+	return row
+}
+
+// value returns the i-th value of the row, NULL values are returned as empty strings.
+func value(row []*string, i int) string {
+	if i >= len(row) || row[i] == nil {
+		return ""
+	}
+	return *row[i]
+}
+
+// columnWidths returns the display width of each column.
+func columnWidths(header []string, rows [][]*string, escape func(string) string) []int {
+	widths := make([]int, len(header))
+	for i, h := range header {
+		widths[i] = runewidth.StringWidth(escape(h))
+	}
+	for _, row := range rows {
+		for i := range widths {
+			if w := runewidth.StringWidth(escape(value(row, i))); w > widths[i] {
+				widths[i] = w
+			}
+		}
+	}
+	return widths
+}
+
+// pad pads the value with spaces up to the width.
+func pad(v string, width int) string {
+	return v + strings.Repeat(" ", width-runewidth.StringWidth(v))
+}
+
+// writeTable writes the rows as an aligned table, like psql.
+// NULL values are written as empty strings.
+func writeTable(w io.Writer, header []string, rows [][]*string) error {
+	noEscape := func(v string) string { return v }
+	widths := columnWidths(header, rows, noEscape)
+
+	var b strings.Builder
+	writeLine := func(values []*string) {
+		cells := make([]string, 0, len(widths))
+		for i := range widths {
+			cells = append(cells, " "+pad(value(values, i), widths[i])+" ")
+		}
+		b.WriteString(strings.TrimRight(strings.Join(cells, "|"), " ") + "\n")
+	}
+
+	writeLine(texts(header...))
+	separators := make([]string, 0, len(widths))
+	for _, width := range widths {
+		separators = append(separators, strings.Repeat("-", width+2))
+	}
+	b.WriteString(strings.Join(separators, "+") + "\n")
+	for _, row := range rows {
+		writeLine(row)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeRowCount writes the number of rows of a table followed by an empty line, like psql.
+func writeRowCount(w io.Writer, n int) error {
+	if n == 1 {
+		_, err := io.WriteString(w, "(1 row)\n\n")
+		return err
+	}
+	_, err := fmt.Fprintf(w, "(%d rows)\n\n", n)
+	return err
+}
+
+// writeMarkdown writes the rows as a markdown table. Pipes in values are escaped.
+func writeMarkdown(w io.Writer, header []string, rows [][]*string) error {
+	escape := func(v string) string {
+		return strings.ReplaceAll(strings.ReplaceAll(v, "|", `\|`), "\n", " ")
+	}
+	widths := columnWidths(header, rows, escape)
+	for i := range widths {
+		// A delimiter row needs at least three dashes
+		if widths[i] < 3 {
+			widths[i] = 3
+		}
+	}
+
+	var b strings.Builder
+	writeLine := func(cells []string) {
+		b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+	}
+
+	cells := make([]string, 0, len(widths))
+	for i := range widths {
+		cells = append(cells, pad(escape(header[i]), widths[i]))
+	}
+	writeLine(cells)
+
+	cells = cells[:0]
+	for _, width := range widths {
+		cells = append(cells, strings.Repeat("-", width))
+	}
+	writeLine(cells)
+
+	for _, row := range rows {
+		cells = cells[:0]
+		for i := range widths {
+			cells = append(cells, pad(escape(value(row, i)), widths[i]))
+		}
+		writeLine(cells)
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeCSV writes the rows as CSV with a header line. NULL values are written as empty fields.
+func writeCSV(w io.Writer, header []string, rows [][]*string) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, row := range rows {
+		record := make([]string, 0, len(header))
+		for i := range header {
+			record = append(record, value(row, i))
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeJSON writes the rows as a JSON array of objects, one object per line.
+// Keys are in column order, values are strings and NULL values are null.
+func writeJSON(w io.Writer, header []string, rows [][]*string) error {
+	if len(rows) == 0 {
+		_, err := io.WriteString(w, "[]\n")
+		return err
+	}
+
+	var b strings.Builder
+	b.WriteString("[\n")
+	for n, row := range rows {
+		fields := make([]string, 0, len(header))
+		for i, h := range header {
+			k, err := json.Marshal(h)
+			if err != nil {
+				return err
+			}
+			v := []byte("null")
+			if i < len(row) && row[i] != nil {
+				if v, err = json.Marshal(*row[i]); err != nil {
+					return err
+				}
+			}
+			fields = append(fields, string(k)+":"+string(v))
+		}
+		b.WriteString("  {" + strings.Join(fields, ",") + "}")
+		if n < len(rows)-1 {
+			b.WriteString(",")
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString("]\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeCommandTag writes the command tag of a statement, like psql (e.g. "INSERT 0 1").
+func writeCommandTag(w io.Writer, command string, rowsAffected int64) error {
+	_, err := fmt.Fprintln(w, protocol.CommandTag(command, rowsAffected))
+	return err
+}
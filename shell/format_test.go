@@ -0,0 +1,119 @@
+package shell
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRenderer(t *testing.T) {
+	t.Parallel()
+
+	header := []string{"id", "name", "email"}
+	rows := [][]*string{
+		texts("1", "alice", "alice@example.com"),
+		append(texts("2", `b|o"b`), nil),
+		texts("3", "ロバート", "a,b"),
+	}
+
+	tests := []struct {
+		format Format
+		rows   [][]*string
+		want   string
+	}{
+		{
+			format: FormatTable,
+			rows:   rows,
+			want: ` id | name     | email
+----+----------+-------------------
+ 1  | alice    | alice@example.com
+ 2  | b|o"b    |
+ 3  | ロバート | a,b
+(3 rows)
+
+`,
+		},
+		{
+			format: FormatCSV,
+			rows:   rows,
+			want: `id,name,email
+1,alice,alice@example.com
+2,"b|o""b",
+3,ロバート,"a,b"
+`,
+		},
+		{
+			format: FormatJSON,
+			rows:   rows,
+			want: `[
+  {"id":"1","name":"alice","email":"alice@example.com"},
+  {"id":"2","name":"b|o\"b","email":null},
+  {"id":"3","name":"ロバート","email":"a,b"}
+]
+`,
+		},
+		{
+			format: FormatJSON,
+			want:   "[]\n",
+		},
+		{
+			format: FormatMarkdown,
+			rows:   rows,
+			want: `| id  | name     | email             |
+| --- | -------- | ----------------- |
+| 1   | alice    | alice@example.com |
+| 2   | b\|o"b   |                   |
+| 3   | ロバート | a,b               |
+
+`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(string(tt.format), func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+			r := NewRenderer(&out, tt.format)
+			if err := r.WriteRowHeader(header); err != nil {
+				t.Fatal(err)
+			}
+			for _, row := range tt.rows {
+				if err := r.WriteRow(row); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := r.WriteRowEnd(); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, out.String()); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	t.Parallel()
+
+	for name, want := range map[string]Format{
+		"table":    FormatTable,
+		"aligned":  FormatTable,
+		"CSV":      FormatCSV,
+		"json":     FormatJSON,
+		"markdown": FormatMarkdown,
+	} {
+		got, err := ParseFormat(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Errorf("want=%s, got=%s", want, got)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Error("expect error, however ParseFormat() returned nil")
+	}
+}
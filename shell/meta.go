@@ -0,0 +1,204 @@
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/nao1215/aiondb/engine"
+)
+
+// metaCommandsHelp is the help printed by \?.
+const metaCommandsHelp = `General
+  \q                     quit aion shell
+  \?                     show help on meta-commands
+
+Informational
+  \d [NAME]              list tables, or describe table NAME
+  \dt                    list tables
+
+Input/Output
+  \i FILE                execute commands from file
+
+Formatting
+  \pset format [FORMAT]  set output format (table, csv, json, markdown)
+  \timing [on|off]       toggle timing of commands
+
+`
+
+// runMetaCommand runs a psql-like meta-command (e.g. \dt).
+// Mistakes of the user are printed, the returned error is an output error.
+// It returns true if the user asks to quit the shell.
+func (s *Shell) runMetaCommand(line string) (bool, error) {
+	fields := strings.Fields(line)
+	name, args := fields[0], fields[1:]
+
+	var err error
+	switch name {
+	case `\q`:
+		return true, nil
+	case `\?`:
+		_, err = io.WriteString(s.out, metaCommandsHelp)
+	case `\dt`:
+		err = s.listTables()
+	case `\d`:
+		if len(args) == 0 {
+			err = s.listTables()
+			break
+		}
+		err = s.describeTable(strings.Trim(args[0], `"`))
+	case `\i`:
+		if len(args) == 0 {
+			_, err = fmt.Fprintf(s.out, "%s: missing required argument\n", name)
+			break
+		}
+		err = s.include(args[0])
+	case `\pset`:
+		err = s.pset(args)
+	case `\timing`:
+		err = s.setTiming(args)
+	default:
+		_, err = fmt.Fprintf(s.out, "invalid command %s\nTry \\? for help.\n", name)
+	}
+	return false, err
+}
+
+// listTables prints the tables of the engine (\dt).
+func (s *Shell) listTables() error {
+	tables := s.engine.Tables()
+	if len(tables) == 0 {
+		_, err := io.WriteString(s.out, "Did not find any relations.\n")
+		return err
+	}
+
+	rows := make([][]*string, 0, len(tables))
+	for _, t := range tables {
+		rows = append(rows, texts(t.Name(), "table"))
+	}
+	if s.renderer.Format() == FormatTable {
+		if _, err := io.WriteString(s.out, "List of relations\n"); err != nil {
+			return err
+		}
+	}
+	return s.renderer.Render([]string{"Name", "Type"}, rows)
+}
+
+// describeTable prints the attributes of a table (\d NAME).
+func (s *Shell) describeTable(name string) error {
+	t := s.engine.Table(name)
+	if t == nil {
+		_, err := fmt.Fprintf(s.out, "Did not find any relation named \"%s\".\n", name)
+		return err
+	}
+
+	attributes := t.Attributes()
+	rows := make([][]*string, 0, len(attributes))
+	for _, attr := range attributes {
+		nullable := ""
+		if attr.NotNull() {
+			nullable = "not null"
+		}
+		rows = append(rows, texts(attr.Name(), attr.TypeName(), nullable, attr.Default(), constraints(attr)))
+	}
+
+	header := []string{"Column", "Type", "Nullable", "Default", "Constraints"}
+	if s.renderer.Format() != FormatTable {
+		return s.renderer.Render(header, rows)
+	}
+
+	// Like psql, the table is described without row count
+	if _, err := fmt.Fprintf(s.out, "Table \"%s\"\n", t.Name()); err != nil {
+		return err
+	}
+	if err := writeTable(s.out, header, rows); err != nil {
+		return err
+	}
+	if pk := t.PrimaryKey(); len(pk) > 0 {
+		if _, err := fmt.Fprintf(s.out, "Primary key: (%s)\n", strings.Join(pk, ", ")); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(s.out, "\n")
+	return err
+}
+
+// constraints returns the constraints and flags of an attribute described by \d.
+func constraints(attr engine.Attribute) string {
+	c := []string{}
+	switch {
+	case attr.PrimaryKey():
+		c = append(c, "primary key")
+	case attr.Unique():
+		c = append(c, "unique")
+	}
+	if attr.AutoIncrement() {
+		c = append(c, "auto increment")
+	}
+	return strings.Join(c, ", ")
+}
+
+// include runs the statements of a SQL script (\i FILE).
+// The script is read before the rest of the current input.
+func (s *Shell) include(path string) error {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		_, err := fmt.Fprintf(s.out, "%s: %s\n", path, unwrapPathError(err))
+		return err
+	}
+	s.inputs = append(s.inputs, bufio.NewReader(strings.NewReader(string(b))))
+	return nil
+}
+
+// unwrapPathError returns the reason of a file error without the operation and the path.
+func unwrapPathError(err error) error {
+	var pathErr *os.PathError
+	if errors.As(err, &pathErr) {
+		return pathErr.Err
+	}
+	return err
+}
+
+// setTiming toggles or sets the timing of statements (\timing [on|off]).
+func (s *Shell) setTiming(args []string) error {
+	switch {
+	case len(args) == 0:
+		s.timing = !s.timing
+	case strings.EqualFold(args[0], "on"):
+		s.timing = true
+	case strings.EqualFold(args[0], "off"):
+		s.timing = false
+	default:
+		_, err := fmt.Fprintf(s.out, "unrecognized value \"%s\" for \"\\timing\": Boolean expected\n", args[0])
+		return err
+	}
+
+	if s.timing {
+		_, err := io.WriteString(s.out, "Timing is on.\n")
+		return err
+	}
+	_, err := io.WriteString(s.out, "Timing is off.\n")
+	return err
+}
+
+// pset sets an output option (\pset format [FORMAT]).
+// Without value, the current value of the option is printed.
+func (s *Shell) pset(args []string) error {
+	if len(args) == 0 || args[0] != "format" {
+		_, err := io.WriteString(s.out, "\\pset: allowed options are format\n")
+		return err
+	}
+
+	if len(args) > 1 {
+		format, err := ParseFormat(args[1])
+		if err != nil {
+			_, err := fmt.Fprintf(s.out, "\\pset: %s\n", err)
+			return err
+		}
+		s.renderer.SetFormat(format)
+	}
+	_, err := fmt.Fprintf(s.out, "Output format is %s.\n", s.renderer.Format())
+	return err
+}
@@ -0,0 +1,138 @@
+package shell
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestMetaCommands(t *testing.T) {
+	t.Parallel()
+
+	script := filepath.Join(t.TempDir(), "members.sql")
+	if err := os.WriteFile(script, []byte("CREATE TABLE members (team INT, user_id INT, PRIMARY KEY (team, user_id));\nINSERT INTO members (team, user_id) VALUES (1, 1)"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "list tables",
+			input: "\\dt\nCREATE TABLE users (id SERIAL);\nCREATE TABLE books (id SERIAL);\n\\dt\n",
+			want: `Did not find any relations.
+CREATE TABLE
+CREATE TABLE
+List of relations
+ Name  | Type
+-------+-------
+ books | table
+ users | table
+(2 rows)
+
+`,
+		},
+		{
+			name: "describe table",
+			input: `CREATE TABLE users (id SERIAL PRIMARY KEY, email VARCHAR(255) UNIQUE NOT NULL, name TEXT DEFAULT 'anon', age INT DEFAULT 20, created_at TIMESTAMP DEFAULT NOW());
+\d users
+\d "users"
+\d nope
+`,
+			want: `CREATE TABLE
+Table "users"
+ Column     | Type         | Nullable | Default | Constraints
+------------+--------------+----------+---------+-----------------------------
+ id         | SERIAL       | not null |         | primary key, auto increment
+ email      | VARCHAR(255) | not null |         | unique
+ name       | TEXT         |          | 'anon'  |
+ age        | INT          |          | 20      |
+ created_at | TIMESTAMP    |          | now()   |
+
+Table "users"
+ Column     | Type         | Nullable | Default | Constraints
+------------+--------------+----------+---------+-----------------------------
+ id         | SERIAL       | not null |         | primary key, auto increment
+ email      | VARCHAR(255) | not null |         | unique
+ name       | TEXT         |          | 'anon'  |
+ age        | INT          |          | 20      |
+ created_at | TIMESTAMP    |          | now()   |
+
+Did not find any relation named "nope".
+`,
+		},
+		{
+			name:  "run a script",
+			input: "\\i " + script + "\n\\d members\nSELECT * FROM members;\n\\i\n",
+			want: `CREATE TABLE
+INSERT 0 1
+Table "members"
+ Column  | Type | Nullable | Default | Constraints
+---------+------+----------+---------+-------------
+ team    | INT  | not null |         |
+ user_id | INT  | not null |         |
+Primary key: (team, user_id)
+
+ team | user_id
+------+---------
+ 1    | 1
+(1 row)
+
+\i: missing required argument
+`,
+		},
+		{
+			name:  "set output format",
+			input: "\\pset format csv\nCREATE TABLE users (id SERIAL);\n\\dt\n\\pset format xml\n\\pset format\n\\pset\n",
+			want: `Output format is csv.
+CREATE TABLE
+Name,Type
+users,table
+\pset: unknown output format "xml", allowed formats are table, csv, json, markdown
+Output format is csv.
+\pset: allowed options are format
+`,
+		},
+		{
+			name:  "unknown meta-command and quit",
+			input: "\\x\n\\q\n\\dt\n",
+			want:  "invalid command \\x\nTry \\? for help.\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+			if err := New(strings.NewReader(tt.input), &out, false).Run(); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, out.String()); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	t.Run("timing", func(t *testing.T) {
+		t.Parallel()
+
+		var out bytes.Buffer
+		input := "\\timing\nCREATE TABLE users (id SERIAL);\n\\timing off\nCREATE TABLE books (id SERIAL);\n\\timing maybe\n"
+		if err := New(strings.NewReader(input), &out, false).Run(); err != nil {
+			t.Fatal(err)
+		}
+		want := regexp.MustCompile(`^Timing is on\.\nCREATE TABLE\nTime: \d+\.\d{3} ms\nTiming is off\.\nCREATE TABLE\nunrecognized value "maybe" for "\\timing": Boolean expected\n$`)
+		if !want.MatchString(out.String()) {
+			t.Errorf("unexpected output:\n%s", out.String())
+		}
+	})
+}
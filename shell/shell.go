@@ -0,0 +1,273 @@
+// Package shell implements AION SHELL, an interactive SQL shell on an in-memory AION DB engine.
+//
+// The shell is both the endpoint and the single connection of the engine it starts:
+// the engine reads the statements typed by the user through ReadStatement and
+// writes the results back to the shell, which prints them.
+package shell
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/nao1215/aiondb/engine"
+	"github.com/nao1215/aiondb/engine/parser/core"
+	"github.com/nao1215/aiondb/engine/protocol"
+)
+
+const (
+	// prompt is the prompt printed before a new statement.
+	prompt = "aion=> "
+	// continuationPrompt is the prompt printed while a statement is not terminated.
+	continuationPrompt = "aion-> "
+)
+
+// Shell reads SQL statements from an input, runs them and prints the results.
+// It implements protocol.EngineEndpoint and protocol.EngineConn.
+type Shell struct {
+	// inputs is the stack of inputs statements are read from: the input
+	// of the shell, followed by the scripts being run by \i.
+	inputs []*bufio.Reader
+	// out is the output results are printed to.
+	out io.Writer
+	// interactive is true if prompts are printed.
+	interactive bool
+	// splitter splits the input into statements.
+	splitter *splitter
+	// queue is the list of read statements not sent to the engine yet.
+	queue []statement
+	// eof is true once the end of the input is reached.
+	eof bool
+	// engine is the engine running the statements.
+	engine *engine.Engine
+	// timing is true if the execution time of statements is printed.
+	timing bool
+	// start is the time the running statement was sent to the engine.
+	start time.Time
+	// command is the command of the running statement (e.g. "INSERT").
+	command string
+	// statement is the text of the running statement.
+	statement string
+	// renderer writes the result sets.
+	renderer *Renderer
+	// accepted is true once the engine accepted the shell as its connection.
+	accepted bool
+	// ready is closed once the engine is started.
+	ready chan struct{}
+	// done is closed once the input is consumed.
+	done chan struct{}
+	// closed is closed with the endpoint.
+	closed chan struct{}
+	// doneOnce ensures done is closed only once.
+	doneOnce sync.Once
+	// closeOnce ensures closed is closed only once.
+	closeOnce sync.Once
+	// mu protects accepted.
+	mu sync.Mutex
+}
+
+// New returns a new Shell reading statements from in and printing results to out.
+// If interactive is true, prompts are printed before reading a line.
+func New(in io.Reader, out io.Writer, interactive bool) *Shell {
+	return &Shell{
+		inputs:      []*bufio.Reader{bufio.NewReader(in)},
+		out:         out,
+		interactive: interactive,
+		splitter:    newSplitter(),
+		renderer:    NewRenderer(out, FormatTable),
+		ready:       make(chan struct{}),
+		done:        make(chan struct{}),
+		closed:      make(chan struct{}),
+	}
+}
+
+// SetFormat sets the output format of result sets. The default format is FormatTable.
+func (s *Shell) SetFormat(format Format) {
+	s.renderer.SetFormat(format)
+}
+
+// Run starts an in-memory engine and runs the statements until the end of the input.
+func (s *Shell) Run() error {
+	e, err := engine.New(s)
+	if err != nil {
+		return err
+	}
+	defer e.Stop()
+	s.engine = e
+	close(s.ready)
+
+	<-s.done
+	return nil
+}
+
+// Accept returns the shell as the connection of the engine once the engine is started.
+// Following calls wait until the endpoint is closed.
+func (s *Shell) Accept() (protocol.EngineConn, error) {
+	<-s.ready
+
+	s.mu.Lock()
+	if !s.accepted {
+		s.accepted = true
+		s.mu.Unlock()
+		return s, nil
+	}
+	s.mu.Unlock()
+
+	<-s.closed
+	return nil, io.EOF
+}
+
+// Close closes the endpoint.
+func (s *Shell) Close() {
+	s.closeOnce.Do(func() {
+		close(s.closed)
+	})
+}
+
+// ReadStatement reads the next statement terminated by a semicolon.
+// An unterminated statement at the end of the input is run as well.
+// Lines starting with a backslash are meta-commands, they are run by the shell itself.
+// It returns io.EOF at the end of the input or when the user quits.
+func (s *Shell) ReadStatement() (string, error) {
+	for len(s.queue) == 0 {
+		if s.eof {
+			return "", s.finish(io.EOF)
+		}
+		if s.interactive && len(s.inputs) == 1 {
+			p := prompt
+			if s.splitter.Pending() {
+				p = continuationPrompt
+			}
+			if _, err := io.WriteString(s.out, p); err != nil {
+				return "", s.finish(err)
+			}
+		}
+
+		line, err := s.inputs[len(s.inputs)-1].ReadString('\n')
+		if err != nil && !errors.Is(err, io.EOF) {
+			return "", s.finish(err)
+		}
+		if errors.Is(err, io.EOF) {
+			// End of a script run by \i: continue with the including input
+			if len(s.inputs) > 1 {
+				s.inputs = s.inputs[:len(s.inputs)-1]
+			} else {
+				s.eof = true
+				if s.interactive {
+					fmt.Fprintln(s.out) //nolint
+				}
+			}
+			s.queue = append(s.queue, s.splitter.Feed(line)...)
+			if stmt, ok := s.splitter.Flush(); ok {
+				s.queue = append(s.queue, stmt)
+			}
+			continue
+		}
+
+		if !s.splitter.Pending() && isQuit(line) {
+			return "", s.finish(io.EOF)
+		}
+		if !s.splitter.Pending() && strings.HasPrefix(strings.TrimSpace(line), `\`) {
+			quit, err := s.runMetaCommand(strings.TrimSpace(line))
+			if err != nil {
+				return "", s.finish(err)
+			}
+			if quit {
+				return "", s.finish(io.EOF)
+			}
+			continue
+		}
+		s.queue = append(s.queue, s.splitter.Feed(line)...)
+	}
+
+	stmt := s.queue[0]
+	s.queue = s.queue[1:]
+	s.command = protocol.Command(stmt.text)
+	s.statement = stmt.text
+	s.start = time.Now()
+	return stmt.text, nil
+}
+
+// finish marks the input as consumed and returns err.
+func (s *Shell) finish(err error) error {
+	s.doneOnce.Do(func() {
+		close(s.done)
+	})
+	return err
+}
+
+// WriteResult prints the command tag of the statement, like psql (e.g. "INSERT 0 1").
+func (s *Shell) WriteResult(_ int64, rowsAffected int64) error {
+	if err := writeCommandTag(s.out, s.command, rowsAffected); err != nil {
+		return err
+	}
+	return s.writeTiming()
+}
+
+// WriteError prints the error. Syntax errors are followed by the line of the statement
+// and a caret pointing at the error, like psql.
+func (s *Shell) WriteError(err error) error {
+	if _, werr := fmt.Fprintf(s.out, "ERROR: %s\n", err); werr != nil {
+		return werr
+	}
+	var syntaxErr *core.SyntaxError
+	if errors.As(err, &syntaxErr) {
+		if _, werr := io.WriteString(s.out, errorContext(s.statement, syntaxErr)); werr != nil {
+			return werr
+		}
+	}
+	return s.writeTiming()
+}
+
+// errorContext returns the line of the statement containing a syntax error, followed by
+// a caret under the error, e.g. "LINE 1: SELECT * FORM users\n                 ^\n".
+func errorContext(stmt string, e *core.SyntaxError) string {
+	lines := strings.Split(stmt, "\n")
+	if e.Line < 1 || e.Line > len(lines) {
+		return ""
+	}
+	prefix := fmt.Sprintf("LINE %d: ", e.Line)
+	line := strings.TrimRight(lines[e.Line-1], "\r")
+	return prefix + line + "\n" + strings.Repeat(" ", len(prefix)+e.Col-1) + "^\n"
+}
+
+// WriteRowHeader starts a new result set.
+func (s *Shell) WriteRowHeader(header []string) error {
+	return s.renderer.WriteRowHeader(header)
+}
+
+// WriteRow appends a row to the result set.
+func (s *Shell) WriteRow(row []*string) error {
+	return s.renderer.WriteRow(row)
+}
+
+// WriteRowEnd prints the result set in the output format.
+func (s *Shell) WriteRowEnd() error {
+	if err := s.renderer.WriteRowEnd(); err != nil {
+		return err
+	}
+	return s.writeTiming()
+}
+
+// writeTiming prints the execution time of the statement if timing is on, like psql.
+func (s *Shell) writeTiming() error {
+	if !s.timing {
+		return nil
+	}
+	_, err := fmt.Fprintf(s.out, "Time: %.3f ms\n", float64(time.Since(s.start).Microseconds())/1000)
+	return err
+}
+
+// isQuit returns true if the line asks to quit the shell.
+func isQuit(line string) bool {
+	switch strings.TrimSpace(line) {
+	case "exit", "quit":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,86 @@
+package shell
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestShell(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name        string
+		input       string
+		interactive bool
+		want        string
+	}{
+		{
+			name: "run statements and print results",
+			input: `CREATE TABLE users (
+  id SERIAL PRIMARY KEY,
+  name TEXT,
+  email TEXT UNIQUE
+);
+INSERT INTO users (name, email) VALUES ('alice', 'alice@example.com'), ('bob', NULL);
+SELECT * FROM users; UPDATE users SET name = 'robert' WHERE id = 2;
+SELECT name FROM users WHERE id = 3;
+DELETE FROM users WHERE id = 1;
+INSERT INTO users (name, email) VALUES ('carol', 'alice@example.com');
+INSERT INTO users (name, email) VALUES ('dave', 'alice@example.com');
+SELECT * FROM users
+`,
+			want: `CREATE TABLE
+INSERT 0 2
+ id | name  | email
+----+-------+-------------------
+ 1  | alice | alice@example.com
+ 2  | bob   |
+(2 rows)
+
+UPDATE 1
+ name
+------
+(0 rows)
+
+DELETE 1
+INSERT 0 1
+ERROR: duplicate key value violates unique constraint on column "email"
+ id | name   | email
+----+--------+-------------------
+ 2  | robert |
+ 3  | carol  | alice@example.com
+(2 rows)
+
+`,
+		},
+		{
+			name:        "print prompts and quit",
+			input:       "SELECT *\nFROM nope;\n\\q\nSELECT 1;\n",
+			interactive: true,
+			want:        "aion=> aion-> ERROR: table \"nope\" does not exist\naion=> ",
+		},
+		{
+			name:  "point at syntax errors",
+			input: "SELECT *\n  FORM users;\n",
+			want:  "ERROR: syntax error at line 2, column 3 near \"FORM\", expected \"FROM\"\nLINE 2:   FORM users\n          ^\n",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			var out bytes.Buffer
+			if err := New(strings.NewReader(tt.input), &out, tt.interactive).Run(); err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, out.String()); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package shell
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/nao1215/aiondb/engine/parser/postgres"
+)
+
+// statement is a statement read by the splitter.
+type statement struct {
+	// text is the statement without its semicolon and leading comments.
+	text string
+	// offset is the byte offset of the statement in the whole input fed to the splitter.
+	offset int
+	// end is the byte offset following the statement and its semicolon in the whole input.
+	end int
+	// comment is true if the statement contains comments.
+	comment bool
+}
+
+// splitter splits an input into statements terminated by a semicolon.
+// Semicolons inside quotes and comments do not terminate a statement.
+// The input can be fed line by line, a statement can span several lines.
+type splitter struct {
+	// buf is the text of the statement being read.
+	buf strings.Builder
+	// scanner finds the quotes and the comments, which may span several lines.
+	scanner postgres.Scanner
+	// empty is true while buf only contains spaces and comments.
+	empty bool
+	// comment is true if the statement being read contains comments.
+	comment bool
+	// commented is true if a comment follows the last character of the statement being read.
+	commented bool
+	// contentLen is the length of buf up to the last character of the statement,
+	// before the trailing spaces and comments.
+	contentLen int
+	// contentEnd is the offset following the last character of the statement in the whole input.
+	contentEnd int
+	// start is the index in buf of the first character of the statement,
+	// after the leading spaces and comments.
+	start int
+	// offset is the number of bytes fed before the current call to Feed.
+	offset int
+	// startOffset is the offset of the first character of the statement in the whole input.
+	startOffset int
+}
+
+// newSplitter returns a new splitter.
+func newSplitter() *splitter {
+	return &splitter{empty: true}
+}
+
+// Feed appends the text to the statement being read and returns
+// the statements terminated in it.
+func (s *splitter) Feed(text string) []statement {
+	stmts := []statement{}
+	defer func() {
+		s.offset += len(text)
+	}()
+
+	for i := 0; i < len(text); {
+		open := s.scanner.Open()
+		if !open && text[i] == ';' {
+			s.comment = s.comment || s.commented
+			if stmt, ok := s.flush(s.buf.Len(), s.offset+i+1); ok {
+				stmts = append(stmts, stmt)
+			}
+			i++
+			continue
+		}
+
+		end, kind := s.scanner.Next(text, i)
+		content := kind != postgres.ScanComment && !(kind == postgres.ScanCode && isSpace(text[i]))
+		switch {
+		case kind == postgres.ScanComment && !open:
+			s.commented = !s.empty
+		case content:
+			s.begin(i)
+		}
+		s.buf.WriteString(text[i:end])
+		if content {
+			s.mark(end - 1)
+		}
+		i = end
+	}
+	return stmts
+}
+
+// mark records that the character at the index i of the text being fed, the last one
+// written to buf, belongs to the statement.
+func (s *splitter) mark(i int) {
+	if s.commented {
+		s.comment = true
+		s.commented = false
+	}
+	s.contentLen = s.buf.Len()
+	s.contentEnd = s.offset + i + 1
+}
+
+// begin records the beginning of the statement at the index i of the text being fed.
+func (s *splitter) begin(i int) {
+	if !s.empty {
+		return
+	}
+	s.empty = false
+	s.start = s.buf.Len()
+	s.startOffset = s.offset + i
+}
+
+// Pending returns true if a statement is being read.
+func (s *splitter) Pending() bool {
+	return !s.empty || s.scanner.Open()
+}
+
+// Flush returns the statement being read, even if it is not terminated,
+// and resets the splitter. It returns false if there is no statement.
+// The trailing comments of the statement are not part of it.
+func (s *splitter) Flush() (statement, bool) {
+	return s.flush(s.contentLen, s.contentEnd)
+}
+
+// flush returns the first n bytes of the statement being read, which ends at the offset
+// end, and resets the splitter. It returns false if the statement only contains spaces
+// and comments.
+func (s *splitter) flush(n int, end int) (statement, bool) {
+	stmt := statement{
+		text:    strings.TrimSpace(s.buf.String()[s.start:n]),
+		offset:  s.startOffset,
+		end:     end,
+		comment: s.comment,
+	}
+	ok := !s.empty
+
+	s.buf.Reset()
+	s.scanner = postgres.Scanner{}
+	s.empty = true
+	s.comment = false
+	s.commented = false
+	s.contentLen = 0
+	s.start = 0
+	return stmt, ok
+}
+
+// splitStatements splits a whole script into statements.
+// The last statement does not need to be terminated by a semicolon.
+func splitStatements(script string) []statement {
+	s := newSplitter()
+	stmts := s.Feed(script)
+	if stmt, ok := s.Flush(); ok {
+		stmts = append(stmts, stmt)
+	}
+	return stmts
+}
+
+// lineColumn returns the line and the column in characters (both starting at 1) of the
+// byte offset in the text.
+func lineColumn(text string, offset int) (int, int) {
+	if offset > len(text) {
+		offset = len(text)
+	}
+	line := strings.Count(text[:offset], "\n") + 1
+	col := utf8.RuneCountInString(text[strings.LastIndex(text[:offset], "\n")+1:offset]) + 1
+	return line, col
+}
+
+// isSpace returns true if the character is a white space.
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
@@ -0,0 +1,126 @@
+package shell
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestSplitter(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		lines   []string
+		want    []string
+		pending string
+	}{
+		{
+			name:  "several statements on a line",
+			lines: []string{"SELECT 1; SELECT 2;\n"},
+			want:  []string{"SELECT 1", "SELECT 2"},
+		},
+		{
+			name:  "statement spanning several lines",
+			lines: []string{"SELECT *\n", "FROM t\n", "WHERE a = 1;\n"},
+			want:  []string{"SELECT *\nFROM t\nWHERE a = 1"},
+		},
+		{
+			name:  "semicolons inside quotes",
+			lines: []string{"INSERT INTO t VALUES ('a;b', \"c;d\", $$e;f$$, $tag$g;$$h$tag$);\n"},
+			want:  []string{"INSERT INTO t VALUES ('a;b', \"c;d\", $$e;f$$, $tag$g;$$h$tag$)"},
+		},
+		{
+			name:  "escaped quotes",
+			lines: []string{"INSERT INTO t VALUES ('a'';b', E'c\\';d', e'\\\\');\n"},
+			want:  []string{"INSERT INTO t VALUES ('a'';b', E'c\\';d', e'\\\\')"},
+		},
+		{
+			name:  "quote spanning several lines",
+			lines: []string{"INSERT INTO t VALUES ('a;\n", "b');\n"},
+			want:  []string{"INSERT INTO t VALUES ('a;\nb')"},
+		},
+		{
+			name:  "semicolons inside comments",
+			lines: []string{"-- a; b\n", "SELECT /* c; */ 1;\n"},
+			want:  []string{"SELECT /* c; */ 1"},
+		},
+		{
+			name:  "semicolons inside nested comments",
+			lines: []string{"/* a /* b; */\n", "c; */ SELECT 1;\n"},
+			want:  []string{"SELECT 1"},
+		},
+		{
+			name:  "parameters are not dollar quotes",
+			lines: []string{"SELECT * FROM t WHERE a = $1; SELECT 2;\n"},
+			want:  []string{"SELECT * FROM t WHERE a = $1", "SELECT 2"},
+		},
+		{
+			name:  "empty statements and comments are skipped",
+			lines: []string{";;\n", "-- comment\n", "/* comment */;\n"},
+			want:  []string{},
+		},
+		{
+			name:    "unterminated statement",
+			lines:   []string{"SELECT 1; SELECT\n", "2\n"},
+			want:    []string{"SELECT 1"},
+			pending: "SELECT\n2",
+		},
+		{
+			name:    "trailing comments of an unterminated statement",
+			lines:   []string{"SELECT 1 -- one\n", "/* two */\n"},
+			want:    []string{},
+			pending: "SELECT 1",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			s := newSplitter()
+			got := []string{}
+			for _, line := range tt.lines {
+				for _, stmt := range s.Feed(line) {
+					got = append(got, stmt.text)
+				}
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("value is mismatch (-want +got):\n%s", diff)
+			}
+			if s.Pending() != (tt.pending != "") {
+				t.Errorf("mismatch pending: want=%v, got=%v", tt.pending != "", s.Pending())
+			}
+			if got, _ := s.Flush(); got.text != tt.pending {
+				t.Errorf("mismatch pending statement: want=%q, got=%q", tt.pending, got.text)
+			}
+		})
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	t.Parallel()
+
+	script := "CREATE TABLE t (a INT);\n\n-- seed\nINSERT INTO t (a)\n  VALUES (1);  SELECT * FROM t"
+
+	type position struct {
+		Text string
+		Line int
+		Col  int
+	}
+	got := []position{}
+	for _, stmt := range splitStatements(script) {
+		line, col := lineColumn(script, stmt.offset)
+		got = append(got, position{Text: stmt.text, Line: line, Col: col})
+	}
+
+	want := []position{
+		{Text: "CREATE TABLE t (a INT)", Line: 1, Col: 1},
+		{Text: "INSERT INTO t (a)\n  VALUES (1)", Line: 4, Col: 1},
+		{Text: "SELECT * FROM t", Line: 5, Col: 16},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("value is mismatch (-want +got):\n%s", diff)
+	}
+}